@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jake/gocode/internal/audit"
+	"github.com/jake/gocode/internal/theme"
+)
+
+// runAuditCommand handles `gocode audit verify [path]`, replaying the
+// hash-chained audit log and reporting whether every entry still chains
+// correctly from the one before it.
+func runAuditCommand(args []string) error {
+	if len(args) < 1 || args[0] != "verify" {
+		return fmt.Errorf("usage: gocode audit verify [path]")
+	}
+
+	path := ""
+	if len(args) > 1 {
+		path = args[1]
+	}
+	if path == "" {
+		var err error
+		path, err = defaultAuditLogPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	count, err := audit.Verify(path)
+	if err != nil {
+		fmt.Printf("%s\n", theme.Error("✗ Audit log invalid after %d verified entries: %v", count, err))
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s\n", theme.Success("✓ Audit log intact - %d entries verified (%s)", count, path))
+	return nil
+}
+
+// defaultAuditLogPath resolves the audit log relative to the current
+// working directory, matching how the agent resolves audit.path (a plain
+// filename) under .gocode by default.
+func defaultAuditLogPath() (string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(workingDir, ".gocode", "audit.jsonl"), nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jake/gocode/internal/checkpoint"
+	"github.com/jake/gocode/internal/theme"
+)
+
+// runCheckpointCommand handles `gocode checkpoint export|import ...` so a
+// teammate can hand off an in-progress thread without sharing the whole
+// .gocode directory.
+func runCheckpointCommand(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: gocode checkpoint <export|import> ...")
+	}
+
+	stateDir, err := checkpointStateDir()
+	if err != nil {
+		return err
+	}
+
+	mgr, err := checkpoint.NewManager(checkpoint.Config{
+		DBPath:       filepath.Join(stateDir, "checkpoints.db"),
+		ArtifactsDir: filepath.Join(stateDir, "artifacts"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint store: %w", err)
+	}
+	defer mgr.Close()
+
+	switch args[0] {
+	case "export":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: gocode checkpoint export <thread-id> <file.tar.gz>")
+		}
+		if err := mgr.Export(args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", theme.Success("✓ Exported thread %s to %s", args[1], args[2]))
+	case "import":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: gocode checkpoint import <file.tar.gz>")
+		}
+		thread, err := mgr.Import(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", theme.Success("✓ Imported thread %s (%s)", thread.ID, thread.Name))
+	default:
+		return fmt.Errorf("unknown checkpoint subcommand: %s", args[0])
+	}
+
+	return nil
+}
+
+// checkpointStateDir resolves .gocode relative to the current working
+// directory, matching how the agent resolves its state dir (checkpoints,
+// artifacts) - see config.Config.StateDir.
+func checkpointStateDir() (string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(workingDir, ".gocode"), nil
+}
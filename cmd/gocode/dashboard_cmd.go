@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jake/gocode/internal/dashboard"
+	"github.com/jake/gocode/internal/theme"
+)
+
+// runDashboardCommand handles `gocode dashboard [--port N]`, serving a
+// read-only web UI over the traces/memory/checkpoint databases under
+// .gocode so a developer can see what a session actually did.
+func runDashboardCommand(args []string) error {
+	port := "8420"
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--port" && i+1 < len(args) {
+			port = args[i+1]
+			i++
+		}
+	}
+
+	gocodeDir, err := dashboardStateDir()
+	if err != nil {
+		return err
+	}
+
+	srv := dashboard.Open(
+		filepath.Join(gocodeDir, "traces.db"),
+		filepath.Join(gocodeDir, "memory.db"),
+		filepath.Join(gocodeDir, "checkpoints.db"),
+	)
+	defer srv.Close()
+
+	addr := "localhost:" + port
+	fmt.Printf("%s\n", theme.Success("✓ Dashboard serving at http://%s (data from %s)", addr, gocodeDir))
+	return http.ListenAndServe(addr, srv.Handler())
+}
+
+// dashboardStateDir resolves .gocode relative to the current working
+// directory, matching how the agent and other subcommands resolve their
+// own state files.
+func dashboardStateDir() (string, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return filepath.Join(workingDir, ".gocode"), nil
+}
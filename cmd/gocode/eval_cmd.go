@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jake/gocode/internal/config"
+	"github.com/jake/gocode/internal/evaluation"
+	"github.com/jake/gocode/internal/theme"
+)
+
+// runEvalCommand handles `gocode eval <tasks.jsonl> <profile-config.yaml>
+// [more-config.yaml ...]`, running GoCode one-shot against each task under
+// every given config (a "model profile" - endpoint, model, temperature,
+// etc.) and printing a pass@1/cost comparison across profiles.
+func runEvalCommand(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: gocode eval <tasks.jsonl> <profile-config.yaml> [more-config.yaml ...]")
+	}
+
+	tasks, err := evaluation.LoadTasks(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", theme.Dim("Loaded %d task(s) from %s", len(tasks), args[0]))
+
+	var reports []evaluation.Report
+	for _, configPath := range args[1:] {
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load profile %s: %w", configPath, err)
+		}
+		profile := cfg.LLM.Model
+
+		fmt.Printf("\n%s\n", theme.HeaderBold("Running %d task(s) against profile %q (%s)", len(tasks), profile, configPath))
+
+		results := evaluation.RunAll(cfg, profile, tasks)
+		for _, r := range results {
+			status := theme.Success("PASS")
+			if !r.Passed {
+				status = theme.Error("FAIL")
+			}
+			fmt.Printf("  %s %s (%.1fs, %d+%d tokens)\n", status, r.TaskID, r.DurationSeconds, r.PromptTokens, r.CompletionTokens)
+			if r.Error != "" {
+				fmt.Printf("    %s\n", theme.Dim("%s", r.Error))
+			}
+		}
+
+		reports = append(reports, evaluation.BuildReport(profile, results))
+	}
+
+	fmt.Printf("\n%s\n\n", theme.HeaderBold("Comparison"))
+	if err := evaluation.WriteComparison(os.Stdout, reports); err != nil {
+		return fmt.Errorf("failed to write comparison report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jake/gocode/internal/codegraph"
+	"github.com/jake/gocode/internal/theme"
+)
+
+// graphIndexSkipDirs mirrors the analyzer's skip list so a standalone
+// `gocode graph export` doesn't walk into vendor/build output.
+var graphIndexSkipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, ".gocode": true,
+	"__pycache__": true, ".venv": true, "venv": true, "dist": true,
+	"build": true, "target": true, ".next": true, ".nuxt": true,
+}
+
+var graphIndexExts = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".ts": true, ".jsx": true, ".tsx": true,
+	".java": true, ".rb": true, ".php": true, ".cs": true, ".rs": true,
+	".c": true, ".h": true, ".cpp": true, ".cc": true, ".cxx": true, ".hpp": true,
+}
+
+// runGraphCommand handles `gocode graph export ...` so architecture/call
+// diagrams can be generated for a doc or PR description without opening the
+// interactive agent.
+func runGraphCommand(args []string) error {
+	if len(args) < 1 || args[0] != "export" {
+		return fmt.Errorf("usage: gocode graph export --format mermaid|dot [--symbol NAME] [--depth N] [--root DIR]")
+	}
+
+	fs := flag.NewFlagSet("graph export", flag.ExitOnError)
+	format := fs.String("format", "mermaid", "Output format: mermaid or dot")
+	symbol := fs.String("symbol", "", "Center the diagram on this symbol's subgraph instead of the whole tree")
+	depth := fs.Int("depth", 2, "Traversal depth from --symbol (ignored without --symbol)")
+	root := fs.String("root", ".", "Directory to index")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *format != "mermaid" && *format != "dot" {
+		return fmt.Errorf("--format must be \"mermaid\" or \"dot\", got %q", *format)
+	}
+
+	rootPath, err := filepath.Abs(*root)
+	if err != nil {
+		return fmt.Errorf("failed to resolve --root: %w", err)
+	}
+
+	graph := codegraph.NewGraph(rootPath, nil)
+	ctx := context.Background()
+	if err := indexDirectory(ctx, graph, rootPath); err != nil {
+		return fmt.Errorf("failed to index %s: %w", rootPath, err)
+	}
+
+	var nodes []*codegraph.SymbolNode
+	if *symbol != "" {
+		matches := graph.FindByName(*symbol)
+		if len(matches) == 0 {
+			return fmt.Errorf("no symbol named %q found under %s", *symbol, rootPath)
+		}
+		seen := make(map[string]*codegraph.SymbolNode)
+		for _, m := range matches {
+			for _, n := range graph.TraverseFrom(m.ID, "", *depth) {
+				seen[n.ID] = n
+			}
+		}
+		for _, n := range seen {
+			nodes = append(nodes, n)
+		}
+	} else {
+		nodes = graph.SymbolsByKind("function", "method", "class", "interface", "struct")
+	}
+	edges := graph.SubgraphEdges(nodes)
+
+	var output string
+	if *format == "dot" {
+		output = codegraph.RenderDOT(nodes, edges)
+	} else {
+		output = codegraph.RenderMermaid(nodes, edges)
+	}
+	fmt.Print(output)
+	if len(edges) == 0 {
+		fmt.Fprintf(os.Stderr, "%s\n", theme.Dim("note: 0 edges — the parser-based indexing used here found no references between the selected symbols; containment edges come only from LSP indexing"))
+	}
+	return nil
+}
+
+// indexDirectory walks root and indexes every recognized source file, since
+// this subcommand runs standalone without the agent's LSP-backed session.
+func indexDirectory(ctx context.Context, graph *codegraph.Graph, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if graphIndexSkipDirs[name] || (strings.HasPrefix(name, ".") && name != ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !graphIndexExts[filepath.Ext(path)] {
+			return nil
+		}
+		return graph.IndexFile(ctx, path)
+	})
+}
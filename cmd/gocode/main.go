@@ -18,8 +18,68 @@ import (
 const version = "1.0.0"
 
 func main() {
+	// Subcommands are dispatched before flag parsing since they have their
+	// own argument shape (e.g. `gocode checkpoint export <thread> <file>`).
+	if len(os.Args) > 1 && os.Args[1] == "checkpoint" {
+		if err := runCheckpointCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Error("Error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := runReplayCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Error("Error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		if err := runGraphCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Error("Error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		if err := runAuditCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Error("Error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		if err := runEvalCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Error("Error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "dashboard" {
+		if err := runDashboardCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Error("Error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		if err := runSyncCommand(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Error("Error: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Parse flags
 	configPath := flag.String("config", "", "Path to config.yaml (default: auto-search)")
+	sessionName := flag.String("session", "", "Named session profile (isolates history, TODO, and checkpoints under .gocode/sessions/<name>/)")
+	tuiMode := flag.Bool("tui", false, "Dashboard-style rendering (conversation, plan, tools, context usage panes)")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	flag.Parse()
 
@@ -45,6 +105,9 @@ func main() {
 	}
 
 	fmt.Printf("%s %s\n", theme.Dim("Using config:"), theme.Agent(configFile))
+	if *sessionName != "" {
+		fmt.Printf("%s %s\n", theme.Dim("Session profile:"), theme.Agent(*sessionName))
+	}
 
 	// Load configuration
 	cfg, err := config.Load(configFile)
@@ -55,6 +118,7 @@ func main() {
 
 	// Set base directory for logs (directory containing config)
 	cfg.BaseDir = filepath.Dir(configFile)
+	cfg.ConfigPath = configFile
 
 	// Set working directory for TODO.md (current directory)
 	workingDir, err := os.Getwd()
@@ -63,6 +127,8 @@ func main() {
 		os.Exit(1)
 	}
 	cfg.WorkingDir = workingDir
+	cfg.Session = *sessionName
+	cfg.TUI = *tuiMode
 
 	// Resolve memory database path to .gocode directory
 	if cfg.Memory.Enabled && cfg.Memory.DBPath != "" && !filepath.IsAbs(cfg.Memory.DBPath) {
@@ -100,7 +166,7 @@ func handleInitialization(workingDir string, cfg *config.Config) (bool, *initial
 	// Check if this is first run
 	if !detector.ShouldInitialize() {
 		// Not first run, try to load cached analysis
-		analyzer := initialization.NewAnalyzer(workingDir, detector)
+		analyzer := initialization.NewAnalyzer(workingDir, detector, cfg.Initialization.ExcludeDirs)
 		if analysis, err := analyzer.LoadCachedAnalysis(); err == nil {
 			return true, analysis
 		}
@@ -119,7 +185,7 @@ func handleInitialization(workingDir string, cfg *config.Config) (bool, *initial
 	// User accepted, perform initialization
 	initialization.DisplayInitProgress("Analyzing project structure...")
 
-	analyzer := initialization.NewAnalyzer(workingDir, detector)
+	analyzer := initialization.NewAnalyzer(workingDir, detector, cfg.Initialization.ExcludeDirs)
 	analysis, err := analyzer.Analyze()
 	if err != nil {
 		initialization.DisplayInitError(err)
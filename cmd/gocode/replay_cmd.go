@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jake/gocode/internal/logging"
+	"github.com/jake/gocode/internal/theme"
+	"github.com/jake/gocode/internal/tools"
+)
+
+// readonlyReplayTools lists tools safe to re-execute during a replay
+// without side effects, so `gocode replay --reexec-readonly` can show what
+// a read/grep/glob actually returns today next to what it returned in the
+// original session.
+var readonlyReplayTools = map[string]bool{
+	"read":            true,
+	"glob":            true,
+	"grep":            true,
+	"list_directory":  true,
+	"list_symbols":    true,
+	"find_definition": true,
+	"find_references": true,
+}
+
+// runReplayCommand handles `gocode replay <session-log.jsonl>`, re-rendering
+// a past session turn by turn so a maintainer can see "why did the agent do
+// X" without needing access to the reporter's machine.
+func runReplayCommand(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	reexecReadonly := fs.Bool("reexec-readonly", false, "Re-run read-only tool calls (read, grep, glob, ...) against the current tree and show fresh output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: gocode replay [--reexec-readonly] <session-log.jsonl>")
+	}
+	logPath := fs.Arg(0)
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to open session log: %w", err)
+	}
+	defer file.Close()
+
+	var registry *tools.Registry
+	if *reexecReadonly {
+		registry = tools.NewRegistry()
+		registry.Register(&tools.ReadTool{})
+		registry.Register(&tools.GlobTool{})
+		registry.Register(&tools.GrepTool{})
+		registry.Register(&tools.ListDirectoryTool{})
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		var entry logging.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			fmt.Printf("%s\n", theme.Warning("line %d: skipping unparseable entry: %v", lineNum, err))
+			continue
+		}
+		printReplayEntry(entry, registry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read session log: %w", err)
+	}
+
+	return nil
+}
+
+func printReplayEntry(entry logging.LogEntry, registry *tools.Registry) {
+	ts := entry.Timestamp.Format("15:04:05")
+
+	switch entry.Type {
+	case "session_start", "session_end":
+		fmt.Printf("%s %s\n", theme.Dim(ts), theme.Dim(entry.Content))
+	case "user_input":
+		fmt.Printf("\n%s %s\n", theme.Dim(ts), theme.UserBold(entry.Content))
+	case "llm_response":
+		var payload struct {
+			Content   string        `json:"content"`
+			ToolCalls []interface{} `json:"tool_calls"`
+		}
+		if err := json.Unmarshal([]byte(entry.Content), &payload); err == nil && payload.Content != "" {
+			fmt.Printf("%s %s\n", theme.Dim(ts), theme.Agent(payload.Content))
+		}
+	case "tool_call":
+		fmt.Printf("%s %s %s\n", theme.Dim(ts), theme.Tool("→ "+entry.ToolName), theme.Dim(entry.ToolArgs))
+		if registry != nil && readonlyReplayTools[entry.ToolName] {
+			result, err := registry.Execute(context.Background(), entry.ToolName, entry.ToolArgs)
+			if err != nil {
+				fmt.Printf("  %s %v\n", theme.Warning("re-exec failed:"), err)
+			} else {
+				fmt.Printf("  %s\n%s\n", theme.Dim("re-executed now:"), result)
+			}
+		}
+	case "tool_result":
+		if entry.ToolError != "" {
+			fmt.Printf("%s %s\n", theme.Dim(ts), theme.Error("✗ %s: %s", entry.ToolName, entry.ToolError))
+		} else {
+			fmt.Printf("%s %s\n", theme.Dim(ts), theme.Success("✓ %s", entry.ToolName))
+		}
+	default:
+		fmt.Printf("%s [%s] %s\n", theme.Dim(ts), entry.Type, entry.Content)
+	}
+}
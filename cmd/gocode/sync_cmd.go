@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jake/gocode/internal/config"
+	"github.com/jake/gocode/internal/remotesync"
+	"github.com/jake/gocode/internal/theme"
+)
+
+// syncedFiles are the two things a developer's accumulated project
+// knowledge lives in, both of which are worth carrying between machines.
+var syncedFiles = map[string]func(cfg *config.Config) string{
+	"memory":   func(cfg *config.Config) string { return cfg.Memory.DBPath },
+	"overview": func(cfg *config.Config) string { return cfg.StateDir() + "/overview.md" },
+}
+
+// runSyncCommand handles `gocode sync push|pull`, encrypting the memory DB
+// and .gocode/overview.md client-side and transferring them to the
+// configured remote (see config.SyncConfig).
+func runSyncCommand(args []string) error {
+	if len(args) != 1 || (args[0] != "push" && args[0] != "pull") {
+		return fmt.Errorf("usage: gocode sync <push|pull>")
+	}
+
+	configPath, err := findConfig()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.Sync.Enabled {
+		return fmt.Errorf("sync.enabled is false in %s", configPath)
+	}
+
+	client, err := remotesync.NewClient(remotesync.Target{
+		Provider: cfg.Sync.Provider,
+		URL:      cfg.Sync.URL,
+		Username: cfg.Sync.Username,
+		Password: cfg.Sync.Password,
+	}, cfg.Sync.EncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for name, pathOf := range syncedFiles {
+		path := pathOf(cfg)
+		if path == "" {
+			continue
+		}
+
+		if args[0] == "push" {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				fmt.Printf("%s\n", theme.Dim("skipping %s (not found at %s)", name, path))
+				continue
+			}
+			if err := client.Push(ctx, name, path); err != nil {
+				return fmt.Errorf("push %s: %w", name, err)
+			}
+			fmt.Printf("%s\n", theme.Success("✓ Pushed %s (%s)", name, path))
+		} else {
+			if err := client.Pull(ctx, name, path); err != nil {
+				return fmt.Errorf("pull %s: %w", name, err)
+			}
+			fmt.Printf("%s\n", theme.Success("✓ Pulled %s (%s)", name, path))
+		}
+	}
+
+	return nil
+}
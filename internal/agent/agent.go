@@ -1,51 +1,153 @@
 package agent
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/chzyer/readline"
+	"github.com/jake/gocode/internal/audit"
+	"github.com/jake/gocode/internal/checkpoint"
 	"github.com/jake/gocode/internal/codegraph"
 	"github.com/jake/gocode/internal/config"
 	"github.com/jake/gocode/internal/confirmation"
+	ctxbudget "github.com/jake/gocode/internal/context"
+	"github.com/jake/gocode/internal/embeddings"
+	"github.com/jake/gocode/internal/events"
+	"github.com/jake/gocode/internal/filewatch"
 	"github.com/jake/gocode/internal/initialization"
+	"github.com/jake/gocode/internal/linkify"
 	"github.com/jake/gocode/internal/llm"
 	"github.com/jake/gocode/internal/logging"
 	"github.com/jake/gocode/internal/lsp"
 	"github.com/jake/gocode/internal/memory"
+	"github.com/jake/gocode/internal/notify"
+	"github.com/jake/gocode/internal/policy"
 	"github.com/jake/gocode/internal/prompts"
+	"github.com/jake/gocode/internal/retrieval"
+	"github.com/jake/gocode/internal/snapshot"
+	"github.com/jake/gocode/internal/telemetry"
 	"github.com/jake/gocode/internal/theme"
 	"github.com/jake/gocode/internal/tools"
+	"github.com/jake/gocode/internal/tui"
 )
 
+// fileChangingTools lists tool names whose args carry the "file_path" the
+// end-of-turn diff summary reads to see what a turn touched. Kept in sync
+// with tools.filePathTools by hand, since that var is unexported and this
+// package only needs the names, not the policy enforcement that lives with it.
+var fileChangingTools = map[string]bool{
+	"write":               true,
+	"edit":                true,
+	"edit_structured":     true,
+	"delete_file":         true,
+	"write_long_document": true,
+}
+
+// sourceEditTools is the subset of fileChangingTools that edits source (not
+// docs or deletions), for the related-tests context injection - editing a
+// long-form doc or deleting a file doesn't need a "keep tests in sync" hint.
+var sourceEditTools = map[string]bool{
+	"write":           true,
+	"edit":            true,
+	"edit_structured": true,
+}
+
 type Agent struct {
-	config           *config.Config
-	llmClient        *llm.Client
-	serverManager    *llm.ServerManager
-	toolRegistry     *tools.Registry
-	confirmSys       *confirmation.System
-	logger           *logging.Logger
-	promptMgr        *prompts.PromptManager
-	messages         []llm.Message
-	rl               *readline.Instance
-	historyFile      string
-	todoTool         *tools.TodoWriteTool
-	selfCheck        *SelfCheckSystem
-	memory           *memory.LongTermMemory
-	lastBashExitCode int      // Track last bash command exit code
-	lastBashTool     string   // Track if last tool was bash
-	toolsUsedInTurn  []string // Track tools used in current turn
+	config             *config.Config
+	llmClient          *llm.Client
+	serverManager      *llm.ServerManager
+	toolRegistry       *tools.Registry
+	confirmSys         *confirmation.System
+	logger             *logging.Logger
+	promptMgr          *prompts.PromptManager
+	messages           []llm.Message
+	rl                 *readline.Instance
+	historyFile        string
+	todoTool           *tools.TodoWriteTool
+	bashTool           *tools.BashTool // for /shells - listing background shells across turns
+	selfCheck          *SelfCheckSystem
+	memory             *memory.LongTermMemory
+	checkpoints        *checkpoint.Manager             // nil unless checkpoint.enabled - auto-saves and backs /checkpoint
+	retriever          *retrieval.HybridRetriever      // nil unless retrieval.enabled - BM25+trigram index of the workspace
+	reranker           *retrieval.Reranker             // heuristic reranker applied to retriever results
+	lastBashExitCode   int                             // Track last bash command exit code
+	lastBashTool       string                          // Track if last tool was bash
+	toolsUsedInTurn    []string                        // Track tools used in current turn
+	filesTouchedInTurn []string                        // Files written/edited/deleted in current turn, for the end-of-turn diff summary
+	rollbackTool       *tools.RollbackFilesTool        // tracks pre-edit file content for /undo and the rollback_files tool
+	lastUserPrompt     string                          // Most recent user input, for /retry
+	lastFailedTool     string                          // Name of the last tool call that errored, for /retry
+	lastFailedArgs     string                          // Arguments of the last tool call that errored, for /retry
+	codeGraph          *codegraph.Graph                // nil unless LSP/codegraph is enabled
+	lspMgr             *lsp.Manager                    // nil unless LSP is enabled
+	projectAnalysis    *initialization.ProjectAnalysis // startup scan results, for /explain
+	turnDurations      []time.Duration                 // Wall-clock duration of recent turns, for /stats
+	dashboard          *tui.Dashboard                  // redraws panes after each turn when --tui is set
+	notifier           notify.Notifier                 // sends a desktop notification when a turn runs long
+	activityMu         sync.Mutex
+	lastActivity       time.Time               // updated whenever the user submits input
+	serverIdleStopped  bool                    // true when idle timeout stopped the managed llama-server
+	auditLog           *audit.Log              // nil unless audit.enabled - hash-chained tool-invocation log
+	telemetryProvider  *telemetry.Provider     // nil unless telemetry.enabled - exports spans to traces.db for `gocode dashboard`
+	auditActor         string                  // identity recorded against each audit entry
+	relatedTests       *tools.RelatedTestsTool // finds a source file's tests, for the post-edit context injection
+	wsSnapshot         *snapshot.Snapshot      // workspace hash manifest taken at session start, for /changes
+	promptTokens       int                     // cumulative prompt tokens across LLM calls this session, for /stats and the evaluation harness
+	completionTokens   int                     // cumulative completion tokens across LLM calls this session
+	turnTokens         int                     // prompt+completion tokens used by the current turn's LLM calls, for cost_guard.max_tokens_per_turn
+	turnNumber         int                     // count of user turns processed this session, for logging.LogContextAssembly's per-turn filename
+	costGuardAsked     bool                    // whether cost_guard already prompted during the current turn, so a long tool loop doesn't nag more than once
+	brokenRefsFlagged  bool                    // whether linkify's broken-file-reference feedback already fired this turn, so it's raised only once
+	capabilities       []Capability            // per-subsystem health captured at New (and refreshed by retryDegradedCapabilities), for /capabilities
+	fileWatcher        *filewatch.Watcher      // keeps codeGraph/retriever/embeddings fresh as files change
+	embedMgr           *embeddings.Manager     // nil unless embeddings.enabled - backs /index status and /index refresh
+	cancelWatcher      context.CancelFunc      // stops fileWatcher's background poll loop, called from Close
+	turnCancelMu       sync.Mutex
+	turnCancel         context.CancelFunc // cancels the in-flight LLM completion, if any; set by processInput, fired by a SIGINT during generation
+	memoriesInTurn     []string           // IDs of memories stored so far this turn, for /good and /bad
+	feedbackGood       int                // cumulative /good calls this session, for /stats
+	feedbackBad        int                // cumulative /bad calls this session, for /stats
+	events             *events.Bus        // typed turn/tool events; the terminal renderer subscribes by default so alternative frontends (--tui, a future HTTP/SSE endpoint) can subscribe instead
+	refactor           *RefactorSession   // nil unless a /refactor session is open - tracks the temp branch until /refactor merge or /refactor abandon
+}
+
+// setTurnCancel records the cancel func for the LLM completion currently in
+// flight (nil once it returns), so a SIGINT arriving mid-generation has
+// something to call instead of waiting for llama-server to finish an
+// abandoned response.
+func (a *Agent) setTurnCancel(cancel context.CancelFunc) {
+	a.turnCancelMu.Lock()
+	a.turnCancel = cancel
+	a.turnCancelMu.Unlock()
+}
+
+// cancelInFlightTurn fires the current completion's cancel func, if a
+// completion is in flight, and is a no-op otherwise (e.g. Ctrl-C at the
+// input prompt, which readline already handles on its own).
+func (a *Agent) cancelInFlightTurn() {
+	a.turnCancelMu.Lock()
+	cancel := a.turnCancel
+	a.turnCancelMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
 }
 
 func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*Agent, error) {
 	// Discover and add LSP binary paths to PATH
 	lsp.DiscoverAndAddLSPPaths()
 
+	eventBus := events.NewBus()
+	eventBus.Subscribe(events.NewTerminalRenderer().Handle)
+
 	// Initialize logger
 	logger, err := logging.New(&cfg.Logging, cfg.BaseDir)
 	if err != nil {
@@ -62,13 +164,37 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 	// Initialize LLM client
 	llmClient := llm.NewClient(&cfg.LLM)
 
+	// Route context-budget token counting through the same server generating
+	// completions, instead of the chars/3.5 heuristic, so pruning and budget
+	// reporting reflect true token counts.
+	if cfg.LLM.Endpoint != "" {
+		ctxbudget.SetDefaultTokenizer(ctxbudget.NewLlamaCppTokenizer(ctxbudget.ServerBaseURL(cfg.LLM.Endpoint)))
+	}
+
 	// Initialize tool registry
 	registry := tools.NewRegistry()
+	if cfg.Tools.MaxOutputBytes > 0 {
+		registry.SetMaxOutputBytes(cfg.Tools.MaxOutputBytes)
+	}
+	registry.SetArtifactsDir(filepath.Join(cfg.StateDir(), "artifacts"))
+	registry.SetWorkingDir(cfg.WorkingDir)
+
+	// Load a team-committed .gocode/policy.yaml if present; a missing file
+	// just means no guardrails beyond the confirmation config are enforced.
+	if pol, err := policy.Load(filepath.Join(cfg.WorkingDir, ".gocode", "policy.yaml")); err == nil {
+		registry.SetPolicy(pol)
+	} else if !os.IsNotExist(err) {
+		fmt.Printf("%s\n", theme.Warning("Failed to load .gocode/policy.yaml: %v", err))
+	}
+
+	var capabilities []Capability
 
 	// Initialize LSP manager and CodeGraph if LSP is enabled
 	var lspMgr *lsp.Manager
 	var codeGraph *codegraph.Graph
-	if cfg.LSP.Enabled {
+	if !cfg.LSP.Enabled {
+		capabilities = append(capabilities, capabilityDisabled("lsp"))
+	} else {
 		// Build LSP server configs from config
 		lspConfigs := make(map[string]lsp.LanguageServerConfig)
 		for lang, serverCfg := range cfg.LSP.Servers {
@@ -91,6 +217,9 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 					lang,
 					serverCfg.Command,
 					lang)
+				capabilities = append(capabilities, capabilityDegraded("lsp:"+lang, fmt.Errorf("%s not found in PATH", serverCfg.Command)))
+			} else {
+				capabilities = append(capabilities, capabilityOK("lsp:"+lang, "found in PATH"))
 			}
 		}
 
@@ -100,8 +229,16 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 
 	// Register enabled tools
 	bashTool := tools.NewBashTool()
+	bashTool.SetSandbox(cfg.Tools.Bash.Sandbox)
+	// The default session keeps TODO.md at the repo root so it stays
+	// visible/committable; named sessions get an isolated copy so
+	// concurrent streams (e.g. "bugfix" and "refactor") don't clobber it.
 	todoPath := filepath.Join(cfg.WorkingDir, "TODO.md")
+	if cfg.Session != "" {
+		todoPath = filepath.Join(cfg.StateDir(), "TODO.md")
+	}
 	todoTool := tools.NewTodoWriteTool(todoPath)
+	rollbackTool := tools.NewRollbackFilesTool()
 
 	for _, toolName := range cfg.Tools.Enabled {
 		switch toolName {
@@ -111,6 +248,8 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 			registry.Register(&tools.WriteTool{})
 		case "edit":
 			registry.Register(&tools.EditTool{})
+		case "edit_structured":
+			registry.Register(&tools.EditStructuredTool{})
 		case "glob":
 			registry.Register(&tools.GlobTool{})
 		case "grep":
@@ -123,8 +262,14 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 			registry.Register(tools.NewKillShellTool(bashTool))
 		case "todo_write":
 			registry.Register(todoTool)
+		case "delete_file":
+			registry.Register(tools.NewDeleteFileTool(filepath.Join(cfg.StateDir(), "trash")))
+		case "rollback_files":
+			registry.Register(rollbackTool)
+		case "list_directory":
+			registry.Register(&tools.ListDirectoryTool{})
 		case "web_fetch":
-			registry.Register(tools.NewWebFetchTool())
+			registry.Register(tools.NewWebFetchTool(cfg.Tools.WebFetch))
 		case "web_search":
 			registry.Register(tools.NewWebSearchTool())
 		case "find_definition":
@@ -139,11 +284,54 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 			if codeGraph != nil {
 				registry.Register(tools.NewListSymbolsTool(codeGraph))
 			}
+		case "rename_symbol":
+			if lspMgr != nil {
+				registry.Register(tools.NewRenameSymbolTool(lspMgr, registry))
+			}
+		case "code_action":
+			if lspMgr != nil {
+				registry.Register(tools.NewCodeActionTool(lspMgr, registry))
+			}
+		case "graph_query":
+			if codeGraph != nil {
+				registry.Register(tools.NewGraphQueryTool(codeGraph))
+			}
+		case "db_query":
+			registry.Register(tools.NewDBQueryTool(&cfg.Tools.Database))
+		case "write_long_document":
+			registry.Register(tools.NewWriteLongDocumentTool(llmClient))
+		case "api_spec":
+			if projectAnalysis != nil && projectAnalysis.APISpec != nil {
+				registry.Register(tools.NewAPISpecTool(projectAnalysis.APISpec))
+			}
+		case "list_tasks":
+			registry.Register(tools.NewListTasksTool(cfg.WorkingDir))
+		case "deps_audit":
+			registry.Register(tools.NewDepsAuditTool(cfg.WorkingDir))
+		case "note":
+			registry.Register(tools.NewNoteTool())
+		case "related_tests":
+			// Naming-convention matches work without a code graph; only the
+			// reference-based matches are skipped when codeGraph is nil.
+			registry.Register(tools.NewRelatedTestsTool(codeGraph, cfg.WorkingDir))
+		case "session_state":
+			registry.Register(tools.NewSessionStateTool(registry.Ledger()))
+		case "git_status":
+			registry.Register(tools.NewGitStatusTool(cfg.WorkingDir))
+		case "git_diff":
+			registry.Register(tools.NewGitDiffTool(cfg.WorkingDir))
+		case "git_log":
+			registry.Register(tools.NewGitLogTool(cfg.WorkingDir))
+		case "git_commit":
+			registry.Register(tools.NewGitCommitTool(cfg.WorkingDir, cfg.Tools.Git.AllowCommit))
 		}
 	}
 
 	// Initialize confirmation system
 	confirmSys := confirmation.New(&cfg.Confirmation)
+	if warning := confirmSys.RuleLoadWarning(); warning != "" {
+		fmt.Printf("%s\n", theme.Warning("Failed to load permission rules: %s", warning))
+	}
 
 	// Initialize prompt manager
 	promptMgr, err := prompts.NewPromptManager()
@@ -151,8 +339,8 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 		return nil, fmt.Errorf("failed to create prompt manager: %w", err)
 	}
 
-	// Ensure .gocode directory exists for history files
-	gocodeDir := filepath.Join(cfg.WorkingDir, ".gocode")
+	// Ensure the session's state directory exists for history files
+	gocodeDir := cfg.StateDir()
 	if err := os.MkdirAll(gocodeDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create .gocode directory: %w", err)
 	}
@@ -163,6 +351,7 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 		HistoryFile:     filepath.Join(gocodeDir, "history"),
 		InterruptPrompt: "^C",
 		EOFPrompt:       "exit",
+		Listener:        retryKeyListener{},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize readline: %w", err)
@@ -196,42 +385,291 @@ func New(cfg *config.Config, projectAnalysis *initialization.ProjectAnalysis) (*
 	// Initialize self-check system
 	selfCheck := NewSelfCheckSystem(registry)
 
-	// Initialize long-term memory if enabled
+	// Initialize OpenTelemetry tracing if enabled, and point the LLM client
+	// at it so llm.completion spans (token usage, finish reason) land in
+	// traces.db - `gocode dashboard` reads this database. Failure degrades
+	// rather than aborting startup, matching the other optional subsystems
+	// below.
+	var telemetryProvider *telemetry.Provider
+	if !cfg.Telemetry.Enabled {
+		capabilities = append(capabilities, capabilityDisabled("telemetry"))
+	} else {
+		telemetryDBPath := cfg.Telemetry.DBPath
+		if telemetryDBPath != "" && !filepath.IsAbs(telemetryDBPath) {
+			telemetryDBPath = filepath.Join(gocodeDir, telemetryDBPath)
+		}
+		provider, err := telemetry.NewProvider(telemetry.Config{
+			Enabled:     true,
+			ServiceName: cfg.Telemetry.ServiceName,
+			DBPath:      telemetryDBPath,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Failed to initialize telemetry: %v (continuing without it)", err))
+			capabilities = append(capabilities, capabilityDegraded("telemetry", err))
+		} else {
+			telemetryProvider = provider
+			llmClient.SetTracer(provider.Tracer())
+			registry.SetTracer(provider.Tracer())
+			capabilities = append(capabilities, capabilityOK("telemetry", telemetryDBPath))
+		}
+	}
+
+	// Initialize long-term memory if enabled. A corrupt or unreachable DB
+	// used to abort agent.New entirely; now it just degrades - the session
+	// starts without cross-session memory instead of not starting at all,
+	// and /capabilities surfaces it so it isn't a silent loss of features.
 	var ltm *memory.LongTermMemory
-	if cfg.Memory.Enabled {
+	if !cfg.Memory.Enabled {
+		capabilities = append(capabilities, capabilityDisabled("memory"))
+	} else {
 		ltm, err = memory.NewLongTermMemory(cfg.Memory.DBPath)
 		if err != nil {
-			logger.Close()
-			serverManager.Stop()
-			rl.Close()
-			return nil, fmt.Errorf("failed to initialize long-term memory: %w", err)
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Failed to initialize long-term memory: %v (continuing without it)", err))
+			capabilities = append(capabilities, capabilityDegraded("memory", err))
+		} else {
+			capabilities = append(capabilities, capabilityOK("memory", cfg.Memory.DBPath))
+		}
+	}
+	selfCheck.SetMemory(ltm)
+
+	// Initialize the checkpoint manager if enabled, and start a thread for
+	// this session so OnMessage has somewhere to auto-save to. Failure
+	// degrades rather than aborting startup, matching memory above.
+	var checkpoints *checkpoint.Manager
+	if !cfg.Checkpoint.Enabled {
+		capabilities = append(capabilities, capabilityDisabled("checkpoint"))
+	} else {
+		checkpointDBPath := cfg.Checkpoint.DBPath
+		if checkpointDBPath != "" && !filepath.IsAbs(checkpointDBPath) {
+			checkpointDBPath = filepath.Join(gocodeDir, checkpointDBPath)
+		}
+		mgr, err := checkpoint.NewManager(checkpoint.Config{
+			DBPath:       checkpointDBPath,
+			AutoSave:     cfg.Checkpoint.AutoSave,
+			SaveInterval: cfg.Checkpoint.SaveInterval,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Failed to initialize checkpoint manager: %v (continuing without it)", err))
+			capabilities = append(capabilities, capabilityDegraded("checkpoint", err))
+		} else if _, err := mgr.StartNewThread(fmt.Sprintf("session-%s", time.Now().Format("20060102-150405"))); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Failed to start checkpoint thread: %v (continuing without it)", err))
+			mgr.Close()
+			capabilities = append(capabilities, capabilityDegraded("checkpoint", err))
+		} else {
+			checkpoints = mgr
+			capabilities = append(capabilities, capabilityOK("checkpoint", checkpointDBPath))
+		}
+	}
+
+	// Initialize hybrid retrieval (BM25 + trigram fusion, reranked) if
+	// enabled, indexing the workspace up front. Semantic search stays off -
+	// embeddings aren't wired into the search path yet (see /stats) - but
+	// the vector store below is still kept fresh by the file watcher for
+	// whenever that changes.
+	var retriever *retrieval.HybridRetriever
+	if !cfg.Retrieval.Enabled {
+		capabilities = append(capabilities, capabilityDisabled("retrieval"))
+	} else {
+		weights := retrieval.FusionWeights{
+			BM25:     cfg.Retrieval.Weights.BM25,
+			Semantic: cfg.Retrieval.Weights.Semantic,
+			Trigram:  cfg.Retrieval.Weights.Trigram,
+		}
+		hr, docCount, err := buildRetriever(cfg, weights)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Failed to index workspace for retrieval: %v (continuing without it)", err))
+			capabilities = append(capabilities, capabilityDegraded("retrieval", err))
+		} else {
+			retriever = hr
+			capabilities = append(capabilities, capabilityOK("retrieval", fmt.Sprintf("%d files indexed", docCount)))
+		}
+	}
+
+	// Build an embeddings manager purely for the file watcher to keep the
+	// vector store's index current, independent of whether semantic search
+	// is wired into retrieval yet.
+	var embedMgr *embeddings.Manager
+	if cfg.Embeddings.Enabled {
+		embedDBPath := cfg.Embeddings.DBPath
+		if !filepath.IsAbs(embedDBPath) {
+			embedDBPath = filepath.Join(gocodeDir, embedDBPath)
+		}
+		chunkerCfg := embeddings.DefaultChunkerConfig()
+		chunkerCfg.AnnotateBlame = cfg.Embeddings.AnnotateBlame
+		mgr, err := embeddings.NewManager(embeddings.Config{
+			EmbeddingBackend:  cfg.Embeddings.Backend,
+			EmbeddingEndpoint: cfg.Embeddings.Endpoint,
+			EmbeddingAPIKey:   cfg.Embeddings.APIKey,
+			EmbeddingModel:    cfg.Embeddings.Model,
+			EmbeddingDim:      cfg.Embeddings.Dimension,
+			VectorDBPath:      embedDBPath,
+			ChunkerConfig:     chunkerCfg,
+			ANN: embeddings.ANNConfig{
+				Enabled:  cfg.Embeddings.ANN.Enabled,
+				Clusters: cfg.Embeddings.ANN.Clusters,
+				Probes:   cfg.Embeddings.ANN.Probes,
+			},
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Failed to open embeddings vector store: %v (continuing without it)", err))
+		} else {
+			embedMgr = mgr
+		}
+	}
+
+	// Start the file watcher, which keeps the code graph, BM25/trigram
+	// index, and embeddings vector store from going stale as files change,
+	// whether the write/edit/bash tools made the change or the user edited
+	// the file in another editor.
+	fileWatcher := filewatch.New(cfg.WorkingDir, codeGraph, retriever, embedMgr)
+	watcherCtx, cancelWatcher := context.WithCancel(context.Background())
+	go fileWatcher.Start(watcherCtx)
+
+	// Every successful write/edit/delete tool call schedules a debounced
+	// reindex through the same watcher, so retrieval stays consistent
+	// within the session without the turn loop needing to know which tools
+	// touch files.
+	registry.SetFileChangeHook(fileWatcher.Queue)
+
+	// Index the whole workspace in the background so list_symbols/graph_query
+	// work across files immediately instead of only over files opened
+	// on-demand during the session. Runs concurrently with the first turn;
+	// the file watcher above keeps it fresh afterward.
+	if codeGraph != nil {
+		go func() {
+			err := codeGraph.IndexWorkspace(watcherCtx, func(done, total int, eta time.Duration) {
+				eventBus.Publish(events.IndexProgress{Done: done, Total: total, ETA: eta})
+			})
+			if err != nil && err != context.Canceled {
+				fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Background workspace indexing failed: %v", err))
+			}
+		}()
+	}
+
+	// Initialize the audit log if enabled. Failure to open it is not fatal
+	// to starting the agent (unlike memory used to be), since compliance
+	// logging shouldn't be able to block ordinary work - it's surfaced as a
+	// warning instead.
+	var auditLog *audit.Log
+	if !cfg.Audit.Enabled {
+		capabilities = append(capabilities, capabilityDisabled("audit"))
+	} else {
+		auditPath := cfg.Audit.Path
+		if auditPath == "" {
+			auditPath = "audit.jsonl"
+		}
+		if !filepath.IsAbs(auditPath) {
+			auditPath = filepath.Join(gocodeDir, auditPath)
+		}
+		auditLog, err = audit.Open(auditPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", theme.Warning("Failed to open audit log: %v (continuing without it)", err))
+			capabilities = append(capabilities, capabilityDegraded("audit", err))
+		} else {
+			capabilities = append(capabilities, capabilityOK("audit", auditPath))
+		}
+	}
+
+	// Snapshot the workspace for /changes so the user has a git-independent
+	// "what did the agent touch this session" view. Failure isn't fatal -
+	// /changes just reports unavailable.
+	wsSnapshot, err := snapshot.Take(cfg.WorkingDir)
+	if err != nil {
+		fmt.Printf("%s\n", theme.Warning("Failed to snapshot workspace: %v (continuing without /changes)", err))
+		capabilities = append(capabilities, capabilityDegraded("changes", err))
+	} else {
+		capabilities = append(capabilities, capabilityOK("changes", cfg.WorkingDir))
+	}
+
+	// Check the embedding server if configured - semantic search isn't
+	// wired into retrieval yet (see /stats), but the file watcher above
+	// already keeps the vector store indexed, so reporting reachability
+	// here still tells a user whether the server side of it is up before
+	// they wonder why indexing fails later.
+	if !cfg.Embeddings.Enabled {
+		capabilities = append(capabilities, capabilityDisabled("embeddings"))
+	} else if embedClient, err := embeddings.NewEmbedder(cfg.Embeddings.Backend, cfg.Embeddings.Endpoint, cfg.Embeddings.Dimension, cfg.Embeddings.Model, cfg.Embeddings.APIKey); err != nil {
+		capabilities = append(capabilities, capabilityDegraded("embeddings", err))
+	} else {
+		healthCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		healthErr := embedClient.Health(healthCtx)
+		cancel()
+		if healthErr != nil {
+			capabilities = append(capabilities, capabilityDegraded("embeddings", healthErr))
+		} else {
+			capabilities = append(capabilities, capabilityOK("embeddings", cfg.Embeddings.Endpoint))
 		}
 	}
 
 	return &Agent{
-		config:        cfg,
-		llmClient:     llmClient,
-		serverManager: serverManager,
-		toolRegistry:  registry,
-		confirmSys:    confirmSys,
-		logger:        logger,
-		promptMgr:     promptMgr,
-		messages:      messages,
-		rl:            rl,
-		historyFile:   historyFile,
-		todoTool:      todoTool,
-		selfCheck:     selfCheck,
-		memory:        ltm,
+		config:            cfg,
+		llmClient:         llmClient,
+		serverManager:     serverManager,
+		toolRegistry:      registry,
+		confirmSys:        confirmSys,
+		logger:            logger,
+		promptMgr:         promptMgr,
+		messages:          messages,
+		rl:                rl,
+		historyFile:       historyFile,
+		todoTool:          todoTool,
+		bashTool:          bashTool,
+		rollbackTool:      rollbackTool,
+		selfCheck:         selfCheck,
+		memory:            ltm,
+		checkpoints:       checkpoints,
+		retriever:         retriever,
+		reranker:          retrieval.NewReranker(),
+		codeGraph:         codeGraph,
+		lspMgr:            lspMgr,
+		projectAnalysis:   projectAnalysis,
+		dashboard:         tui.NewDashboard(cfg.TUI),
+		notifier:          notify.NewNotifier(cfg.Notifications.Enabled),
+		lastActivity:      time.Now(),
+		auditLog:          auditLog,
+		telemetryProvider: telemetryProvider,
+		auditActor:        memory.CurrentUser(),
+		relatedTests:      tools.NewRelatedTestsTool(codeGraph, cfg.WorkingDir),
+		wsSnapshot:        wsSnapshot,
+		capabilities:      capabilities,
+		fileWatcher:       fileWatcher,
+		cancelWatcher:     cancelWatcher,
+		embedMgr:          embedMgr,
+		events:            eventBus,
 	}, nil
 }
 
-func (a *Agent) Run() error {
-	defer a.serverManager.Stop()
-	defer a.logger.Close()
-	defer a.rl.Close()
+// Close releases everything New acquired - the managed llama-server, log
+// file, readline instance, and (when enabled) the audit log and memory
+// database. Run defers it for interactive sessions; headless callers that
+// use RunOnce (e.g. the evaluation harness) must call it themselves once
+// they're done with the Agent.
+func (a *Agent) Close() {
+	if a.cancelWatcher != nil {
+		a.cancelWatcher()
+	}
+	a.serverManager.Stop()
+	a.logger.Close()
+	a.rl.Close()
+	if a.auditLog != nil {
+		a.auditLog.Close()
+	}
 	if a.memory != nil {
-		defer a.memory.Close()
+		a.memory.Close()
+	}
+	if a.checkpoints != nil {
+		a.checkpoints.Close()
+	}
+	if a.telemetryProvider != nil {
+		a.telemetryProvider.Shutdown(context.Background())
+	}
+	if a.embedMgr != nil {
+		a.embedMgr.Close()
 	}
+}
+
+func (a *Agent) Run() error {
+	defer a.Close()
 
 	fmt.Print(theme.SynthwaveBanner("v1.0"))
 
@@ -246,6 +684,30 @@ func (a *Agent) Run() error {
 		}
 	}
 
+	a.resumeInProgressTurn()
+	a.reconcileTodos()
+
+	if a.config.LLM.IdleTimeoutMinutes > 0 {
+		go a.watchIdle()
+	}
+
+	if a.config.ConfigPath != "" {
+		go a.watchConfig()
+	}
+
+	// Forward SIGINT to whatever completion is in flight, if any, so
+	// Ctrl-C during generation cancels the request (and frees its
+	// llama-server slot) instead of waiting for it to finish. Ctrl-C at the
+	// input prompt itself is handled separately by readline.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		for range sigCh {
+			a.cancelInFlightTurn()
+		}
+	}()
+
 	for {
 		line, err := a.rl.Readline()
 		if err != nil { // io.EOF, readline.ErrInterrupt
@@ -253,6 +715,9 @@ func (a *Agent) Run() error {
 			return nil
 		}
 
+		a.touchActivity()
+		a.resumeFromIdleIfNeeded()
+
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -262,18 +727,111 @@ func (a *Agent) Run() error {
 			return nil
 		}
 
+		if strings.HasPrefix(line, "!") {
+			a.cmdShellEscape(strings.TrimPrefix(line, "!"))
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			handled, err := a.handleSlashCommand(line)
+			if err != nil {
+				fmt.Printf("\n%s\n\n", theme.Error("Error: %v", err))
+			}
+			if handled {
+				continue
+			}
+		}
+
 		// Process user input
+		start := time.Now()
 		if err := a.processInput(line); err != nil {
 			fmt.Printf("\n%s\n\n", theme.Error("Error: %v", err))
 		}
+		elapsed := time.Since(start)
+		a.recordTurnDuration(elapsed)
+		a.events.Publish(events.TurnSummary{ToolCalls: len(a.toolsUsedInTurn), Duration: elapsed})
+		a.renderDashboard()
+		a.notifyIfSlow(elapsed)
 	}
 }
 
+// TokenUsage returns cumulative prompt/completion token counts across every
+// LLM call this Agent has made, for /stats and headless callers (e.g. the
+// evaluation harness) that need a cost proxy without re-deriving it.
+func (a *Agent) TokenUsage() (promptTokens, completionTokens int) {
+	return a.promptTokens, a.completionTokens
+}
+
+// enforceCostGuard checks the current turn's and session's cumulative token
+// usage against config.CostGuard's limits after each LLM call, and if
+// either is exceeded, asks the confirmation provider whether to keep going
+// - so a runaway tool loop or an unexpectedly large context injection on a
+// pay-per-token remote provider doesn't burn through a budget unattended.
+// It only asks once per turn: after the user answers (either way), later
+// calls this turn are let through so a long tool loop doesn't nag on every
+// iteration.
+func (a *Agent) enforceCostGuard() error {
+	cg := a.config.CostGuard
+	if a.costGuardAsked || a.config.Confirmation.Mode == "auto" {
+		return nil
+	}
+
+	var reason string
+	switch {
+	case cg.MaxTokensPerTurn > 0 && a.turnTokens >= cg.MaxTokensPerTurn:
+		reason = fmt.Sprintf("This turn has used %d tokens, at or above the configured per-turn limit of %d.", a.turnTokens, cg.MaxTokensPerTurn)
+	case cg.MaxTokensPerSession > 0 && a.promptTokens+a.completionTokens >= cg.MaxTokensPerSession:
+		reason = fmt.Sprintf("This session has used %d tokens, at or above the configured per-session limit of %d.", a.promptTokens+a.completionTokens, cg.MaxTokensPerSession)
+	default:
+		return nil
+	}
+
+	a.costGuardAsked = true
+	approved, _, err := a.confirmSys.RequestConfirmation("continue_turn", reason)
+	if err != nil {
+		return fmt.Errorf("cost guard confirmation failed: %w", err)
+	}
+	if !approved {
+		return fmt.Errorf("turn stopped by cost guard: %s", reason)
+	}
+	return nil
+}
+
+// RunOnce processes a single input non-interactively and returns, without
+// entering Run's Readline loop - for headless callers like the evaluation
+// harness that drive one turn against a freshly constructed Agent and then
+// inspect its result/token usage themselves.
+func (a *Agent) RunOnce(input string) error {
+	return a.processInput(input)
+}
+
 func (a *Agent) processInput(input string) error {
+	// Clear the crash-recovery snapshot whenever this turn finishes, however
+	// it finishes - only a hard crash mid-turn should leave one behind.
+	defer a.clearTurnInProgress()
+
 	a.logger.LogUserInput(input)
 
+	a.lastUserPrompt = input
+
 	// Reset tools used in this turn
 	a.toolsUsedInTurn = []string{}
+	a.filesTouchedInTurn = []string{}
+	a.rollbackTool.BeginTurn()
+	a.memoriesInTurn = []string{}
+	a.toolRegistry.ResetTurnCounters()
+	a.turnTokens = 0
+	a.costGuardAsked = false
+	a.brokenRefsFlagged = false
+	a.retryDegradedCapabilities()
+
+	// Auto-derive a TODO plan for multi-step requests, instead of relying on
+	// the model to remember to call todo_write itself.
+	if a.config.Plan.Auto && len(a.todoTool.GetTodos()) == 0 && looksMultiStep(input) {
+		if err := a.autoPlan(input); err != nil {
+			a.logger.LogToolResult("auto_plan", "", err)
+		}
+	}
 
 	// Inject current TODO state before processing
 	todos := a.todoTool.GetTodos()
@@ -293,8 +851,19 @@ func (a *Agent) processInput(input string) error {
 
 	// Append to conversation history
 	a.appendToConversationHistory("user", input)
+	a.snapshotTurnInProgress()
+
+	// Free up history budget by collapsing tool results from more than
+	// context.ToolResultCompressionTurns turns ago into a one-line summary
+	// plus an artifact reference the model can still ask to expand.
+	compressMgr := ctxbudget.NewManager(ctxbudget.DefaultBudgetConfig())
+	compressMgr.SetMessages(a.messages)
+	compressedBefore := countCompressedToolResults(a.messages)
+	a.messages = compressMgr.CompressOldToolResults(a.toolRegistry.SaveArtifact)
+	toolResultsPruned := countCompressedToolResults(a.messages) - compressedBefore
 
 	// Retrieve relevant memories if memory is enabled
+	var memoriesIncluded []string
 	if a.memory != nil {
 		memories, err := a.memory.Search(input, 3) // Get top 3 relevant memories
 		if err == nil && len(memories) > 0 {
@@ -307,6 +876,7 @@ func (a *Agent) processInput(input string) error {
 				} else {
 					memoryContext.WriteString(fmt.Sprintf("   %s\n", mem.Content))
 				}
+				memoriesIncluded = append(memoriesIncluded, mem.Summary)
 			}
 
 			// Inject memories as system message
@@ -317,17 +887,54 @@ func (a *Agent) processInput(input string) error {
 		}
 	}
 
+	// Inject hybrid-retrieved context (BM25 + trigram fusion, reranked)
+	// before the last user message, respecting the same context budget
+	// used for pruning.
+	var chunksRetrieved int
+	var chunksIncluded []string
+	if a.retriever != nil {
+		if chunks := a.retrieveContext(input); len(chunks) > 0 {
+			chunksRetrieved = len(chunks)
+			budgetCfg := ctxbudget.DefaultBudgetConfig()
+			if a.config.LLM.ContextWindow > 0 {
+				budgetCfg.MaxTokens = a.config.LLM.ContextWindow
+			}
+			ctxMgr := ctxbudget.NewManager(budgetCfg)
+			ctxMgr.SetMessages(a.messages)
+			filtered := ctxMgr.FilterContextByBudget(chunks)
+			chunksIncluded = filtered
+			a.messages = ctxMgr.PrepareMessagesForLLM(filtered)
+		}
+	}
+
+	a.turnNumber++
+	a.logger.LogContextAssembly(logging.ContextAssemblySnapshot{
+		TurnNumber:        a.turnNumber,
+		Messages:          messageDigests(a.messages),
+		MemoriesIncluded:  memoriesIncluded,
+		ChunksRetrieved:   chunksRetrieved,
+		ChunksIncluded:    chunksIncluded,
+		ToolResultsPruned: toolResultsPruned,
+	})
+
 	// Main conversation loop
 	for {
-		// Prepare tools for LLM
+		// Prepare tools for LLM, shortening descriptions and pruning enum
+		// lists once the context window is too small to afford the full
+		// schemas for every registered tool (see /context for the savings).
+		compact := a.config.LLM.ContextWindow > 0 && a.config.LLM.ContextWindow < tools.CompactContextThreshold
 		toolDefs := make([]llm.Tool, 0)
 		for _, tool := range a.toolRegistry.All() {
+			description, params := tool.Description(), tool.Parameters()
+			if compact {
+				description, params = tools.Compact(description, params)
+			}
 			toolDefs = append(toolDefs, llm.Tool{
 				Type: "function",
 				Function: llm.Function{
 					Name:        tool.Name(),
-					Description: tool.Description(),
-					Parameters:  tool.Parameters(),
+					Description: description,
+					Parameters:  params,
 				},
 			})
 		}
@@ -342,23 +949,61 @@ func (a *Agent) processInput(input string) error {
 
 		a.logger.LogLLMRequest(a.convertMessagesToInterface(), a.config.LLM.Model, a.config.LLM.Temperature)
 
-		// Show thinking indicator
-		fmt.Printf("\n%s", theme.Dim("🤔 Thinking...\r"))
+		var resp *llm.CompletionResponse
+		var err error
+		streamed := false
 
-		resp, err := a.llmClient.Complete(context.Background(), req)
+		completionCtx, cancelCompletion := context.WithCancel(context.Background())
+		a.setTurnCancel(cancelCompletion)
+
+		if a.config.LLM.Stream {
+			streamed = true
+			fmt.Println()
+			resp, err = a.llmClient.CompleteStream(completionCtx, req, func(delta string) {
+				fmt.Print(theme.Agent(delta))
+			})
+			if resp != nil && resp.Content != "" {
+				fmt.Println()
+			}
+		} else {
+			// Show thinking indicator
+			fmt.Printf("\n%s", theme.Dim("🤔 Thinking...\r"))
+			resp, err = a.llmClient.Complete(completionCtx, req)
+			// Clear thinking indicator
+			fmt.Printf("\r%s\r", strings.Repeat(" ", 20))
+		}
 
-		// Clear thinking indicator
-		fmt.Printf("\r%s\r", strings.Repeat(" ", 20))
+		cancelCompletion()
+		a.setTurnCancel(nil)
 
 		if err != nil {
+			if completionCtx.Err() == context.Canceled {
+				a.llmClient.CancelSlot()
+				return fmt.Errorf("generation cancelled")
+			}
 			return fmt.Errorf("LLM completion failed: %w", err)
 		}
 
+		a.promptTokens += resp.Usage.PromptTokens
+		a.completionTokens += resp.Usage.CompletionTokens
+		a.turnTokens += resp.Usage.PromptTokens + resp.Usage.CompletionTokens
+
+		if err := a.enforceCostGuard(); err != nil {
+			return err
+		}
+
 		a.logger.LogLLMResponse(resp.Content, a.convertToolCallsToInterface(resp.ToolCalls))
 
-		// Display assistant response
+		// Display assistant response (already rendered token-by-token above
+		// if streaming; non-streamed mode prints the full text at once here,
+		// with any recognized file references turned into clickable links).
+		var linkResult linkify.Result
 		if resp.Content != "" {
-			fmt.Printf("\n%s\n", theme.Agent(resp.Content))
+			linkResult = linkify.Find(resp.Content, a.config.WorkingDir)
+			if !streamed {
+				displayed := linkify.Hyperlink(resp.Content, a.config.WorkingDir, linkResult.References)
+				a.events.Publish(events.AssistantText{Content: displayed})
+			}
 			// Append assistant response to conversation history
 			a.appendToConversationHistory("assistant", resp.Content)
 		}
@@ -372,49 +1017,176 @@ func (a *Agent) processInput(input string) error {
 			}
 			a.messages = append(a.messages, assistantMsg)
 
+			// Decide up front which of this turn's calls need confirming, so a
+			// turn with several pending writes/commands can be confirmed as
+			// one group (see RequestGroupedConfirmation) instead of stopping
+			// the user with a prompt per call. A single pending call still
+			// goes through the plain one-at-a-time prompt.
+			needsConfirm := make([]bool, len(resp.ToolCalls))
+			denied := make([]string, len(resp.ToolCalls))
+			var pendingCalls []confirmation.PendingCall
+			for i, toolCall := range resp.ToolCalls {
+				allowed, confirmRequired, denyReason := a.confirmSys.Decide(toolCall.Function.Name, toolCall.Function.Arguments)
+				if !allowed {
+					denied[i] = denyReason
+					continue
+				}
+				if confirmRequired {
+					needsConfirm[i] = true
+					pendingCalls = append(pendingCalls, confirmation.PendingCall{
+						ToolName: toolCall.Function.Name,
+						Args:     toolCall.Function.Arguments,
+					})
+				}
+			}
+			var groupDecisions []confirmation.Decision
+			if len(pendingCalls) > 1 {
+				groupDecisions, err = a.confirmSys.RequestGroupedConfirmation(pendingCalls)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Read-only calls that don't need confirmation (e.g. several
+			// `read`/`grep` calls in one response) are independent, so run
+			// them concurrently ahead of the sequential loop below instead
+			// of waiting on each one in turn. Mutating calls still execute
+			// inline, in order, since that loop also handles confirmation
+			// and message ordering per call.
+			prefetched := make(map[int]tools.Result)
+			var prefetchCalls []tools.Call
+			var prefetchIdx []int
+			for i, toolCall := range resp.ToolCalls {
+				if denied[i] == "" && !needsConfirm[i] && tools.IsReadOnly(toolCall.Function.Name) {
+					prefetchCalls = append(prefetchCalls, tools.Call{Name: toolCall.Function.Name, Args: toolCall.Function.Arguments})
+					prefetchIdx = append(prefetchIdx, i)
+				}
+			}
+			if len(prefetchCalls) > 1 {
+				for k, res := range a.toolRegistry.ExecuteBatch(context.Background(), prefetchCalls) {
+					prefetched[prefetchIdx[k]] = res
+				}
+			}
+
 			allApproved := true
-			for _, toolCall := range resp.ToolCalls {
+			decisionIdx := 0
+			for i, toolCall := range resp.ToolCalls {
 				a.logger.LogToolCall(toolCall.Function.Name, toolCall.Function.Arguments)
 
+				// execArgs may be replaced below if the user edits the
+				// proposal during confirmation.
+				execArgs := toolCall.Function.Arguments
+
+				// approvalMode is recorded in the audit log alongside this
+				// call, so a compliance review can see how each call was
+				// authorized without re-deriving it from confirmation policy.
+				approvalMode := "auto-approved"
+
+				// A permission rule denied this call outright - it never
+				// runs and never prompts, unlike a plain confirmation
+				// rejection.
+				if denied[i] != "" {
+					resultContent := fmt.Sprintf("Error: %s", denied[i])
+					a.events.Publish(events.ToolFinished{Name: toolCall.Function.Name, Result: resultContent, Err: fmt.Errorf("%s", denied[i])})
+					a.messages = append(a.messages, llm.Message{
+						Role:    "tool",
+						Content: resultContent,
+						ToolID:  toolCall.ID,
+					})
+					a.snapshotTurnInProgress()
+					continue
+				}
+
 				// Check if confirmation needed
-				if a.confirmSys.ShouldConfirm(toolCall.Function.Name, toolCall.Function.Arguments) {
-					approved, err := a.confirmSys.RequestConfirmation(toolCall.Function.Name, toolCall.Function.Arguments)
-					if err != nil {
-						return err
+				if needsConfirm[i] {
+					var approved bool
+					var editedArgs string
+					if groupDecisions != nil {
+						decision := groupDecisions[decisionIdx]
+						decisionIdx++
+						approved, editedArgs = decision.Approved, decision.EditedArgs
+					} else {
+						approved, editedArgs, err = a.confirmSys.RequestConfirmation(toolCall.Function.Name, execArgs)
+						if err != nil {
+							return err
+						}
 					}
 					if !approved {
-						fmt.Println(theme.Error("❌ Tool execution rejected"))
+						a.events.Publish(events.ToolRejected{Name: toolCall.Function.Name, Args: execArgs})
 						allApproved = false
 						continue
 					}
+					if editedArgs != execArgs {
+						approvalMode = "user-edited"
+					} else {
+						approvalMode = "user-approved"
+					}
+					execArgs = editedArgs
 				}
 
-				// Execute tool - show command for bash
-				if toolCall.Function.Name == "bash" {
-					// Try to extract command from arguments
-					var bashArgs map[string]interface{}
-					if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &bashArgs); err == nil {
-						if cmd, ok := bashArgs["command"].(string); ok {
-							// Truncate long commands
-							displayCmd := cmd
-							if len(displayCmd) > 60 {
-								displayCmd = displayCmd[:57] + "..."
-							}
-							fmt.Printf("\n%s %s %s\n", theme.Tool("🔧 Executing:"), theme.ToolBold(toolCall.Function.Name), theme.Dim("(%s)", displayCmd))
-						} else {
-							fmt.Printf("\n%s %s\n", theme.Tool("🔧 Executing:"), theme.ToolBold(toolCall.Function.Name))
-						}
-					} else {
-						fmt.Printf("\n%s %s\n", theme.Tool("🔧 Executing:"), theme.ToolBold(toolCall.Function.Name))
+				if fileChangingTools[toolCall.Function.Name] {
+					var pathArgs struct {
+						FilePath string `json:"file_path"`
+					}
+					if json.Unmarshal([]byte(execArgs), &pathArgs) == nil && pathArgs.FilePath != "" {
+						a.rollbackTool.Snapshot(pathArgs.FilePath)
 					}
+				}
+
+				// Execute tool - the renderer shows the command for bash and
+				// keeps notes out of the normal tool-execution box.
+				a.events.Publish(events.ToolStarted{Name: toolCall.Function.Name, Args: execArgs})
+
+				var result string
+				var err error
+				if res, ok := prefetched[i]; ok {
+					result, err = res.Output, res.Err
 				} else {
-					fmt.Printf("\n%s %s\n", theme.Tool("🔧 Executing:"), theme.ToolBold(toolCall.Function.Name))
+					result, err = a.toolRegistry.Execute(context.Background(), toolCall.Function.Name, execArgs)
 				}
 
-				result, err := a.toolRegistry.Execute(context.Background(), toolCall.Function.Name, toolCall.Function.Arguments)
+				if a.auditLog != nil {
+					if auditErr := a.auditLog.Record(a.auditActor, toolCall.Function.Name, execArgs, result, err, true, approvalMode); auditErr != nil {
+						fmt.Printf("\n%s\n", theme.Warning("Failed to write audit log entry: %v", auditErr))
+					}
+				}
 
 				// Track tool usage
 				a.toolsUsedInTurn = append(a.toolsUsedInTurn, toolCall.Function.Name)
+				if err == nil && fileChangingTools[toolCall.Function.Name] {
+					var pathArgs struct {
+						FilePath string `json:"file_path"`
+					}
+					if json.Unmarshal([]byte(execArgs), &pathArgs) == nil && pathArgs.FilePath != "" {
+						a.filesTouchedInTurn = append(a.filesTouchedInTurn, pathArgs.FilePath)
+
+						// Reindexing itself is scheduled by the registry's
+						// file-change hook (see SetFileChangeHook in New),
+						// so it also covers batch-prefetched tool calls.
+
+						// Surface the file's test(s) right in the tool result so a
+						// source edit keeps its tests in mind without a separate
+						// related_tests call.
+						if sourceEditTools[toolCall.Function.Name] {
+							if related := a.relatedTests.Find(context.Background(), pathArgs.FilePath); len(related) > 0 {
+								result += fmt.Sprintf("\n\n📎 Related test file(s) - check whether this change needs a matching test update:\n- %s", strings.Join(related, "\n- "))
+							}
+
+							if diag := a.formatDiagnostics(pathArgs.FilePath); diag != "" {
+								result += diag
+							}
+						}
+					}
+				}
+
+				// Track the last failure for /retry
+				if err != nil {
+					a.lastFailedTool = toolCall.Function.Name
+					a.lastFailedArgs = execArgs
+				} else {
+					a.lastFailedTool = ""
+					a.lastFailedArgs = ""
+				}
 
 				a.logger.LogToolResult(toolCall.Function.Name, result, err)
 
@@ -423,8 +1195,10 @@ func (a *Agent) processInput(input string) error {
 					a.lastBashTool = toolCall.Function.Name
 					// Parse exit code from error if present
 					if err != nil {
-						// Error format: "command failed: exit status N"
-						if strings.Contains(err.Error(), "exit status") {
+						if tools.ClassOf(err) == tools.ErrorClassTimeout {
+							a.lastBashExitCode = -1 // Distinguish timeouts from a real exit code
+						} else if strings.Contains(err.Error(), "exit status") {
+							// Error format: "command failed: exit status N"
 							var exitCode int
 							fmt.Sscanf(err.Error(), "command failed: exit status %d", &exitCode)
 							a.lastBashExitCode = exitCode
@@ -445,24 +1219,25 @@ func (a *Agent) processInput(input string) error {
 					} else {
 						resultContent = fmt.Sprintf("Error: %v", err)
 					}
-					fmt.Printf("%s\n", theme.Error("❌ %s", resultContent))
-				} else {
-					fmt.Printf("%s\n", theme.Success("✓ Complete"))
+				}
 
-					// Display TODO status after todo_write execution
-					if toolCall.Function.Name == "todo_write" {
-						summary := a.todoTool.GetProgressSummary()
-						if summary != "" {
-							fmt.Printf("%s\n", theme.Dim(summary))
-						}
-					}
+				var toolFinishedExtra string
+				if err == nil && toolCall.Function.Name == "todo_write" {
+					toolFinishedExtra = a.todoTool.GetProgressSummary()
 				}
+				a.events.Publish(events.ToolFinished{
+					Name:   toolCall.Function.Name,
+					Result: resultContent,
+					Err:    err,
+					Extra:  toolFinishedExtra,
+				})
 
 				a.messages = append(a.messages, llm.Message{
 					Role:    "tool",
 					Content: resultContent,
 					ToolID:  toolCall.ID,
 				})
+				a.snapshotTurnInProgress()
 			}
 
 			if !allApproved {
@@ -481,6 +1256,19 @@ func (a *Agent) processInput(input string) error {
 				Content: resp.Content,
 			}
 
+			// Nudge the model once per turn if it cited a file that doesn't
+			// exist in this project, before falling through to self-check.
+			if !a.brokenRefsFlagged && len(linkResult.Broken) > 0 {
+				a.brokenRefsFlagged = true
+				correction := linkify.CorrectionMessage(linkResult.Broken)
+				fmt.Printf("\n%s\n", theme.Warning(correction))
+				a.messages = append(a.messages, llm.Message{
+					Role:    "system",
+					Content: correction,
+				})
+				continue
+			}
+
 			// Check if self-check should trigger
 			if a.selfCheck.ShouldTriggerCheck(assistantMsg) {
 				// Detect claims
@@ -530,6 +1318,19 @@ func (a *Agent) processInput(input string) error {
 			// Store important learnings to long-term memory
 			if a.memory != nil {
 				a.storeConversationMemories(input, resp.Content)
+				a.storeDiffSummaryMemory(input)
+			}
+
+			// Hand this turn's pre-edit snapshots off for /undo, now that the
+			// turn is done using them for its own rollback_files calls.
+			a.rollbackTool.EndTurn()
+
+			// Auto-save a checkpoint every save_interval messages, so a bad
+			// tool run can be rewound with /checkpoint restore.
+			if a.checkpoints != nil {
+				if err := a.checkpoints.OnMessage(a.messages); err != nil {
+					a.logger.LogToolResult("checkpoint_autosave", "", err)
+				}
 			}
 
 			// Display turn summary
@@ -637,20 +1438,36 @@ func (a *Agent) convertToolCallsToInterface(toolCalls []llm.ToolCall) []interfac
 // buildToolInfos creates tool information for the system prompt
 func buildToolInfos(registry *tools.Registry) []prompts.ToolInfo {
 	toolCategories := map[string]string{
-		"read":            "file",
-		"write":           "file",
-		"edit":            "file",
-		"glob":            "search",
-		"grep":            "search",
-		"bash":            "bash",
-		"bash_output":     "bash",
-		"kill_shell":      "bash",
-		"web_fetch":       "web",
-		"web_search":      "web",
-		"todo_write":      "task",
-		"find_definition": "lsp",
-		"find_references": "lsp",
-		"list_symbols":    "lsp",
+		"read":                "file",
+		"write":               "file",
+		"edit":                "file",
+		"edit_structured":     "file",
+		"delete_file":         "file",
+		"glob":                "search",
+		"grep":                "search",
+		"list_directory":      "search",
+		"bash":                "bash",
+		"bash_output":         "bash",
+		"kill_shell":          "bash",
+		"web_fetch":           "web",
+		"web_search":          "web",
+		"todo_write":          "task",
+		"find_definition":     "lsp",
+		"find_references":     "lsp",
+		"list_symbols":        "lsp",
+		"graph_query":         "lsp",
+		"db_query":            "database",
+		"api_spec":            "api",
+		"list_tasks":          "task",
+		"deps_audit":          "task",
+		"write_long_document": "file",
+		"note":                "task",
+		"related_tests":       "lsp",
+		"session_state":       "task",
+		"git_status":          "git",
+		"git_diff":            "git",
+		"git_log":             "git",
+		"git_commit":          "git",
 	}
 
 	var toolInfos []prompts.ToolInfo
@@ -713,9 +1530,23 @@ func buildProjectContext(analysis *initialization.ProjectAnalysis) *prompts.Proj
 		GitBranch:        gitBranch,
 		TechStack:        techStack,
 		Structure:        structure,
+		APISummary:       buildAPISummary(analysis),
 	}
 }
 
+// buildAPISummary describes the project's OpenAPI/Swagger spec, if any.
+func buildAPISummary(analysis *initialization.ProjectAnalysis) string {
+	if analysis.APISpec == nil {
+		return ""
+	}
+	spec := analysis.APISpec
+	title := spec.Title
+	if title == "" {
+		title = spec.Path
+	}
+	return fmt.Sprintf("- **%s** (%s, %d endpoints) — use the `api_spec` tool to query operations and schemas", title, spec.Format, len(spec.Endpoints))
+}
+
 // buildTechStackDescription creates a description of the tech stack
 func buildTechStackDescription(analysis *initialization.ProjectAnalysis) string {
 	var parts []string
@@ -749,6 +1580,94 @@ func buildStructureDescription(analysis *initialization.ProjectAnalysis) string
 	return strings.Join(parts, "\n")
 }
 
+// turnSnapshotPath is where the in-progress turn's messages are persisted
+// incrementally, so a crash mid-turn can be resumed from the last completed
+// tool result rather than losing the whole turn.
+func (a *Agent) turnSnapshotPath() string {
+	return filepath.Join(a.config.StateDir(), "checkpoints", "turn-in-progress.json")
+}
+
+// snapshotTurnInProgress writes the current messages to disk. It's called
+// after every message append within a turn (not just at SaveCheckpoint
+// intervals) so at most the in-flight LLM call is lost on a crash.
+func (a *Agent) snapshotTurnInProgress() {
+	path := a.turnSnapshotPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return // best-effort - a missed snapshot just narrows the resume window
+	}
+
+	data, err := json.MarshalIndent(a.messages, "", "  ")
+	if err != nil {
+		return
+	}
+
+	os.WriteFile(path, data, 0644)
+}
+
+// clearTurnInProgress removes the snapshot once a turn finishes normally
+// (successfully or with a surfaced error the user can see and react to);
+// only a hard crash should leave it behind for resumeInProgressTurn to find.
+func (a *Agent) clearTurnInProgress() {
+	os.Remove(a.turnSnapshotPath())
+}
+
+// reconcileTodos runs once at the start of an interactive session so a
+// finished TODO list from a prior run isn't silently re-injected into every
+// turn forever: a fully completed list is archived automatically, and a
+// list with leftover work is offered back to the user to carry over. Only
+// called from Run() - headless callers like RunOnce never prompt.
+func (a *Agent) reconcileTodos() {
+	archiveDir := filepath.Join(a.config.StateDir(), "todo-archive")
+	archived, carriedOver, err := a.todoTool.ReconcileSession(archiveDir, func(pending int) bool {
+		fmt.Printf("%s", theme.UserBold("Found %d pending TODO item(s) from a previous session - carry them over? [y/n]: ", pending))
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		response = strings.ToLower(strings.TrimSpace(response))
+		return response == "y" || response == "yes"
+	})
+	if err != nil {
+		fmt.Printf("%s\n", theme.Warning("Failed to reconcile TODO.md: %v", err))
+		return
+	}
+	if archived != "" {
+		fmt.Printf("%s\n", theme.Dim("Archived previous TODO list to %s", archived))
+	}
+	if carriedOver > 0 {
+		fmt.Printf("%s\n", theme.Success("✓ Carried over %d pending TODO item(s).", carriedOver))
+	}
+}
+
+// resumeInProgressTurn offers to restore messages left behind by a crash
+// mid-turn, before the REPL starts reading new input.
+func (a *Agent) resumeInProgressTurn() {
+	path := a.turnSnapshotPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return // nothing to resume
+	}
+
+	var messages []llm.Message
+	if err := json.Unmarshal(data, &messages); err != nil || len(messages) == 0 {
+		os.Remove(path)
+		return
+	}
+
+	fmt.Printf("\n%s\n", theme.Warning("Found an in-progress turn from a previous session (%d messages) - it looks like gocode didn't shut down cleanly.", len(messages)))
+	fmt.Printf("%s", theme.UserBold("Resume it? [y/n]: "))
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+
+	if response == "y" || response == "yes" {
+		a.messages = messages
+		fmt.Printf("%s\n", theme.Success("✓ Resumed - send a message to continue where it left off."))
+	} else {
+		fmt.Printf("%s\n", theme.Dim("Discarded."))
+	}
+	os.Remove(path)
+}
+
 // appendToConversationHistory appends a message to the conversation history file
 func (a *Agent) appendToConversationHistory(role, content string) {
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
@@ -767,6 +1686,31 @@ func (a *Agent) appendToConversationHistory(role, content string) {
 	f.WriteString(entry)
 }
 
+// countCompressedToolResults counts tool-result messages that
+// ctxbudget.CompressOldToolResults has already collapsed into a
+// summary-plus-artifact-reference, identified by the marker it stamps into
+// the compressed content.
+func countCompressedToolResults(messages []llm.Message) int {
+	count := 0
+	for _, msg := range messages {
+		if msg.Role == "tool" && strings.Contains(msg.Content, "bytes compressed") {
+			count++
+		}
+	}
+	return count
+}
+
+// messageDigests reduces the final message array to role/size pairs for
+// logging.LogContextAssembly, so a debug run can see section sizes without
+// dumping the full (often huge) prompt into every turn's digest file.
+func messageDigests(messages []llm.Message) []logging.ContextMessageDigest {
+	digests := make([]logging.ContextMessageDigest, len(messages))
+	for i, msg := range messages {
+		digests[i] = logging.ContextMessageDigest{Role: msg.Role, Chars: len(msg.Content)}
+	}
+	return digests
+}
+
 // formatTodoContext formats the current TODO list for injection into conversation
 func (a *Agent) formatTodoContext(todos []tools.TodoItem) string {
 	var parts []string
@@ -794,6 +1738,177 @@ func (a *Agent) formatTodoContext(todos []tools.TodoItem) string {
 	return strings.Join(parts, "\n")
 }
 
+// looksMultiStep is a cheap heuristic for "this request has several
+// imperative steps", used to gate the auto-plan call so a one-line question
+// doesn't burn an extra LLM round trip.
+func looksMultiStep(input string) bool {
+	markers := 0
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+			markers++
+			continue
+		}
+		if len(line) > 2 && line[0] >= '1' && line[0] <= '9' && (line[1] == '.' || line[1] == ')') {
+			markers++
+		}
+	}
+	if markers >= 2 {
+		return true
+	}
+
+	lower := strings.ToLower(input)
+	sequencers := []string{" then ", " after that", " and then", "; then"}
+	for _, s := range sequencers {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// autoPlan runs a lightweight, tool-free completion call to decompose a
+// multi-step request into a todo_write plan before execution starts,
+// instead of relying on the model to remember to seed one itself.
+func (a *Agent) autoPlan(input string) error {
+	req := llm.CompletionRequest{
+		Messages: []llm.Message{
+			{
+				Role: "system",
+				Content: "Decompose the user's request into a short list of imperative, sequential steps. " +
+					`Respond with ONLY a JSON array like [{"content":"Run tests","activeForm":"Running tests"}] and nothing else.`,
+			},
+			{Role: "user", Content: input},
+		},
+	}
+
+	resp, err := a.llmClient.Complete(context.Background(), req)
+	if err != nil {
+		return fmt.Errorf("auto-plan completion failed: %w", err)
+	}
+	a.promptTokens += resp.Usage.PromptTokens
+	a.completionTokens += resp.Usage.CompletionTokens
+
+	var steps []struct {
+		Content    string `json:"content"`
+		ActiveForm string `json:"activeForm"`
+	}
+	content := strings.TrimSpace(resp.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &steps); err != nil {
+		return fmt.Errorf("auto-plan returned unparseable JSON: %w", err)
+	}
+	if len(steps) < 2 {
+		return nil // not actually multi-step; leave it to the model
+	}
+
+	todos := make([]tools.TodoItem, len(steps))
+	for i, s := range steps {
+		todos[i] = tools.TodoItem{Content: s.Content, ActiveForm: s.ActiveForm, Status: "pending"}
+	}
+
+	argsJSON, err := json.Marshal(tools.TodoWriteArgs{Todos: todos})
+	if err != nil {
+		return err
+	}
+	if _, err := a.toolRegistry.Execute(context.Background(), "todo_write", string(argsJSON)); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s\n", theme.Dim(fmt.Sprintf("📋 Auto-planned %d steps", len(todos))))
+	return nil
+}
+
+// formatDiagnostics fetches the language server's diagnostics for path right
+// after an edit and renders any errors/warnings for injection into the tool
+// result, so the model sees compile errors immediately instead of only
+// after an explicit build. publishDiagnostics arrives asynchronously, so
+// this polls briefly rather than trusting whatever was cached before the
+// edit.
+func (a *Agent) formatDiagnostics(path string) string {
+	if a.lspMgr == nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var diags []lsp.Diagnostic
+	for {
+		var err error
+		diags, err = a.lspMgr.GetDiagnostics(ctx, path)
+		if err != nil {
+			return ""
+		}
+		if len(diags) > 0 || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-ctx.Done():
+		}
+	}
+
+	var problems []string
+	for _, d := range diags {
+		if d.Severity != 0 && d.Severity > lsp.DiagnosticSeverityWarning {
+			continue // skip information/hint-level diagnostics
+		}
+		problems = append(problems, fmt.Sprintf("%s:%d: %s: %s", path, d.Range.Start.Line+1, d.Severity, d.Message))
+	}
+	if len(problems) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("\n\n⚠️ LSP diagnostics for %s:\n- %s", path, strings.Join(problems, "\n- "))
+}
+
+// storeDiffSummaryMemory records a compact changelog entry for any turn that
+// touched files, so /memory list builds a searchable history of what the
+// agent changed and why without needing to dig through conversation_history.
+func (a *Agent) storeDiffSummaryMemory(input string) {
+	if len(a.filesTouchedInTurn) == 0 {
+		return
+	}
+
+	files := a.uniqueTools(a.filesTouchedInTurn) // generic string dedup, not tool-specific despite the name
+	intent := input
+	if len(intent) > 150 {
+		intent = intent[:147] + "..."
+	}
+
+	summary := fmt.Sprintf("Changed %d file(s) for: %s", len(files), intent)
+	content := fmt.Sprintf("%s\n\nFiles:\n- %s", summary, strings.Join(files, "\n- "))
+
+	if err := a.storeMemory(&memory.Memory{
+		Type:       memory.TypeArtifact,
+		Content:    content,
+		Summary:    summary,
+		Tags:       []string{"changelog", "diff-summary"},
+		Metadata:   map[string]interface{}{"files": files},
+		ArtifactID: strings.Join(files, ","),
+		Importance: 0.5,
+	}); err != nil {
+		a.logger.LogToolResult("diff_summary_memory", "", err)
+	}
+}
+
+// storeMemory stores mem and, on success, tracks its ID against the current
+// turn so /good and /bad have something to adjust - the hard-coded
+// Importance values set at each call site are just the starting point.
+func (a *Agent) storeMemory(mem *memory.Memory) error {
+	if err := a.memory.Store(mem); err != nil {
+		return err
+	}
+	a.memoriesInTurn = append(a.memoriesInTurn, mem.ID)
+	return nil
+}
+
 // storeConversationMemories extracts and stores important learnings from the conversation
 func (a *Agent) storeConversationMemories(userInput, assistantResponse string) {
 	// Extract key patterns to store
@@ -809,7 +1924,7 @@ func (a *Agent) storeConversationMemories(userInput, assistantResponse string) {
 			Tags:       []string{"architecture", "design"},
 			Importance: 0.8,
 		}
-		a.memory.Store(mem)
+		a.storeMemory(mem)
 	}
 
 	// 2. Store error resolutions
@@ -824,7 +1939,7 @@ func (a *Agent) storeConversationMemories(userInput, assistantResponse string) {
 			Tags:       []string{"error", "troubleshooting"},
 			Importance: 0.7,
 		}
-		a.memory.Store(mem)
+		a.storeMemory(mem)
 	}
 
 	// 3. Store project structure learnings (from read/glob/grep results)
@@ -844,7 +1959,7 @@ func (a *Agent) storeConversationMemories(userInput, assistantResponse string) {
 			Tags:       []string{"structure", "files"},
 			Importance: 0.6,
 		}
-		a.memory.Store(mem)
+		a.storeMemory(mem)
 	}
 
 	// 4. Store code patterns and best practices
@@ -858,6 +1973,6 @@ func (a *Agent) storeConversationMemories(userInput, assistantResponse string) {
 			Tags:       []string{"pattern", "best-practice"},
 			Importance: 0.7,
 		}
-		a.memory.Store(mem)
+		a.storeMemory(mem)
 	}
 }
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/jake/gocode/internal/embeddings"
+	"github.com/jake/gocode/internal/memory"
+)
+
+// Capability records one optional subsystem's health as of the last time it
+// was checked (startup, or a retryDegradedCapabilities pass), so
+// /capabilities can report a full picture without reaching into each
+// subsystem's internals.
+type Capability struct {
+	Name      string // e.g. "memory", "lsp:go", "embeddings"
+	Available bool
+	Detail    string // "disabled in config", the endpoint/path when healthy, or the error when degraded
+}
+
+func capabilityOK(name, detail string) Capability {
+	return Capability{Name: name, Available: true, Detail: detail}
+}
+
+func capabilityDegraded(name string, err error) Capability {
+	return Capability{Name: name, Available: false, Detail: err.Error()}
+}
+
+func capabilityDisabled(name string) Capability {
+	return Capability{Name: name, Available: false, Detail: "disabled in config"}
+}
+
+// Capabilities returns the current per-subsystem health snapshot, for the
+// /capabilities command.
+func (a *Agent) Capabilities() []Capability {
+	return a.capabilities
+}
+
+// retryDegradedCapabilities re-probes subsystems that were degraded at
+// startup (or a previous retry) and are cheap to re-check: a memory DB that
+// was locked or briefly unreachable, and an embedding server that was down.
+// LSP and audit degradation isn't retried here - a missing LSP binary or an
+// unwritable audit path won't fix itself between turns the way a
+// transient DB lock or a restarted embedding server will.
+func (a *Agent) retryDegradedCapabilities() {
+	for i, c := range a.capabilities {
+		if c.Available {
+			continue
+		}
+		switch {
+		case c.Name == "memory" && a.config.Memory.Enabled && a.memory == nil:
+			ltm, err := memory.NewLongTermMemory(a.config.Memory.DBPath)
+			if err != nil {
+				a.capabilities[i] = capabilityDegraded("memory", err)
+				continue
+			}
+			a.memory = ltm
+			a.capabilities[i] = capabilityOK("memory", a.config.Memory.DBPath)
+
+		case c.Name == "embeddings" && a.config.Embeddings.Enabled:
+			client, err := embeddings.NewEmbedder(a.config.Embeddings.Backend, a.config.Embeddings.Endpoint, a.config.Embeddings.Dimension, a.config.Embeddings.Model, a.config.Embeddings.APIKey)
+			if err != nil {
+				a.capabilities[i] = capabilityDegraded("embeddings", err)
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			err = client.Health(ctx)
+			cancel()
+			if err != nil {
+				a.capabilities[i] = capabilityDegraded("embeddings", err)
+				continue
+			}
+			a.capabilities[i] = capabilityOK("embeddings", a.config.Embeddings.Endpoint)
+		}
+	}
+}
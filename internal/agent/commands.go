@@ -0,0 +1,1377 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jake/gocode/internal/clipboard"
+	"github.com/jake/gocode/internal/config"
+	"github.com/jake/gocode/internal/confirmation"
+	ctxbudget "github.com/jake/gocode/internal/context"
+	"github.com/jake/gocode/internal/initialization"
+	"github.com/jake/gocode/internal/llm"
+	"github.com/jake/gocode/internal/memory"
+	"github.com/jake/gocode/internal/theme"
+	"github.com/jake/gocode/internal/tools"
+	"github.com/jake/gocode/internal/tui"
+)
+
+const maxTrackedTurnDurations = 50
+
+// handleSlashCommand dispatches a leading-"/" line to a REPL command
+// handler. An unrecognized command prints an "Unknown command" warning and
+// is still reported as handled, so it never falls through to the LLM;
+// handled=false is returned only for a blank line.
+func (a *Agent) handleSlashCommand(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	switch fields[0] {
+	case "/stats":
+		a.cmdStats()
+		return true, nil
+	case "/retry":
+		argsOverride := strings.TrimSpace(strings.TrimPrefix(line, "/retry"))
+		return true, a.cmdRetry(argsOverride)
+	case "/explain":
+		return true, a.cmdExplain()
+	case "/rewind":
+		n := 1
+		if len(fields) > 1 {
+			if parsed, err := strconv.Atoi(fields[1]); err == nil && parsed > 0 {
+				n = parsed
+			}
+		}
+		return true, a.cmdRewind(n)
+	case "/memory":
+		return true, a.cmdMemory(fields[1:])
+	case "/lsp":
+		return true, a.cmdLSP(fields[1:])
+	case "/changes":
+		return true, a.cmdChanges(fields[1:])
+	case "/context":
+		return true, a.cmdContext()
+	case "/capabilities":
+		return true, a.cmdCapabilities()
+	case "/checkpoint":
+		return true, a.cmdCheckpoint(fields[1:])
+	case "/setup":
+		return true, a.cmdSetup(fields[1:])
+	case "/model":
+		return true, a.cmdModel(fields[1:])
+	case "/good":
+		return true, a.cmdFeedback(true)
+	case "/bad":
+		return true, a.cmdFeedback(false)
+	case "/tools":
+		return true, a.cmdTools(fields[1:])
+	case "/shells":
+		return true, a.cmdShells(fields[1:])
+	case "/undo":
+		return true, a.cmdUndo()
+	case "/copy":
+		return true, a.cmdCopy(fields[1:])
+	case "/index":
+		return true, a.cmdIndex(fields[1:])
+	case "/refactor":
+		return true, a.cmdRefactor(fields[1:])
+	default:
+		fmt.Printf("\n%s\n\n", theme.Warning("Unknown command: %s", fields[0]))
+		return true, nil
+	}
+}
+
+// recordTurnDuration keeps a bounded window of recent turn latencies for
+// /stats' running average.
+func (a *Agent) recordTurnDuration(d time.Duration) {
+	a.turnDurations = append(a.turnDurations, d)
+	if len(a.turnDurations) > maxTrackedTurnDurations {
+		a.turnDurations = a.turnDurations[len(a.turnDurations)-maxTrackedTurnDurations:]
+	}
+}
+
+func (a *Agent) averageTurnDuration() time.Duration {
+	if len(a.turnDurations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range a.turnDurations {
+		total += d
+	}
+	return total / time.Duration(len(a.turnDurations))
+}
+
+// cmdStats prints live counters for the internal subsystems, useful for
+// debugging performance regressions without instrumenting a real Prometheus
+// endpoint.
+func (a *Agent) cmdStats() {
+	var lines []string
+
+	if a.codeGraph != nil {
+		lines = append(lines, fmt.Sprintf("Code graph symbols: %d", a.codeGraph.SymbolCount()))
+	} else {
+		lines = append(lines, "Code graph symbols: disabled")
+	}
+
+	if a.lspMgr != nil {
+		lines = append(lines, fmt.Sprintf("LSP clients alive: %d", a.lspMgr.ActiveClientCount()))
+	} else {
+		lines = append(lines, "LSP clients alive: disabled")
+	}
+
+	if a.memory != nil {
+		if count, err := a.memory.Count(); err == nil {
+			lines = append(lines, fmt.Sprintf("Memory rows: %d", count))
+		} else {
+			lines = append(lines, fmt.Sprintf("Memory rows: error (%v)", err))
+		}
+		if size, err := fileSize(a.config.Memory.DBPath); err == nil {
+			lines = append(lines, fmt.Sprintf("Memory DB size: %s", humanBytes(size)))
+		}
+	} else {
+		lines = append(lines, "Memory rows: disabled")
+	}
+
+	if a.retriever != nil {
+		lines = append(lines, fmt.Sprintf("BM25 docs: %d indexed", a.retriever.Count()))
+	} else {
+		lines = append(lines, "BM25 docs: disabled (retrieval.enabled: false in config.yaml)")
+	}
+	if a.embedMgr != nil {
+		lines = append(lines, fmt.Sprintf("Vector chunks: %v indexed (not used by retrieval search)", a.embedMgr.Stats()["total_chunks"]))
+	} else {
+		lines = append(lines, "Vector chunks: disabled")
+	}
+
+	lines = append(lines, fmt.Sprintf("Turns recorded: %d", len(a.turnDurations)))
+	lines = append(lines, fmt.Sprintf("Average turn latency: %s", a.averageTurnDuration().Round(time.Millisecond)))
+
+	promptTokens, completionTokens := a.TokenUsage()
+	lines = append(lines, fmt.Sprintf("Tokens used: %d prompt + %d completion", promptTokens, completionTokens))
+
+	lines = append(lines, fmt.Sprintf("Feedback: %d good, %d bad", a.feedbackGood, a.feedbackBad))
+
+	fmt.Printf("\n%s\n", theme.SummaryBox("📊 Subsystem Stats", lines))
+}
+
+// renderDashboard redraws the --tui panes after a turn completes. It's a
+// no-op when TUI mode isn't enabled.
+func (a *Agent) renderDashboard() {
+	if !a.dashboard.Enabled() {
+		return
+	}
+
+	var recent []string
+	for _, msg := range a.messages {
+		if msg.Role == "system" {
+			continue
+		}
+		recent = append(recent, fmt.Sprintf("[%s] %s", msg.Role, truncateLine(msg.Content, 100)))
+	}
+
+	var todoLines []string
+	for _, todo := range a.todoTool.GetTodos() {
+		todoLines = append(todoLines, fmt.Sprintf("[%s] %s", todo.Status, todo.Content))
+	}
+
+	ctxMgr := ctxbudget.NewManager(ctxbudget.DefaultBudgetConfig())
+	ctxMgr.SetMessages(a.messages)
+	usage := ctxMgr.CalculateCurrentUsage()
+
+	a.dashboard.Render(tui.Snapshot{
+		RecentMessages: recent,
+		TodoItems:      todoLines,
+		RunningTools:   a.toolsUsedInTurn,
+		ContextUsed:    usage.Total,
+		ContextWindow:  a.config.LLM.ContextWindow,
+	})
+}
+
+func truncateLine(s string, max int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
+
+// notifyIfSlow fires a desktop notification when a turn takes at least as
+// long as the configured threshold, for users who alt-tab away during long
+// local-model generations.
+func (a *Agent) notifyIfSlow(elapsed time.Duration) {
+	threshold := a.config.Notifications.ThresholdSeconds
+	if !a.config.Notifications.Enabled || threshold <= 0 {
+		return
+	}
+	if elapsed < time.Duration(threshold)*time.Second {
+		return
+	}
+	a.notifier.Notify("gocode", fmt.Sprintf("Turn finished after %s", elapsed.Round(time.Second)))
+}
+
+// retryKeyListener binds Ctrl-T to fill the input line with "/retry", so
+// the last failed command can be re-run with a keystroke instead of typing
+// it out.
+type retryKeyListener struct{}
+
+func (retryKeyListener) OnChange(line []rune, pos int, key rune) (newLine []rune, newPos int, ok bool) {
+	const ctrlT = 20
+	if key != ctrlT {
+		return nil, 0, false
+	}
+	retry := []rune("/retry")
+	return retry, len(retry), true
+}
+
+// cmdRetry re-issues the last failed tool call, or if none failed, re-sends
+// the last user prompt — useful after fixing something externally (e.g. a
+// missing file or a stopped service) without retyping the whole request.
+// Passing text after "/retry" replaces the failed tool's arguments before
+// re-running it.
+func (a *Agent) cmdRetry(argsOverride string) error {
+	if a.lastFailedTool != "" {
+		args := a.lastFailedArgs
+		if argsOverride != "" {
+			args = argsOverride
+		}
+
+		fmt.Printf("\n%s %s\n", theme.Tool("🔁 Retrying:"), theme.ToolBold(a.lastFailedTool))
+		result, err := a.toolRegistry.Execute(context.Background(), a.lastFailedTool, args)
+		if err != nil {
+			fmt.Printf("%s\n\n", theme.Error("❌ %v", err))
+			a.lastFailedArgs = args
+			return nil
+		}
+
+		fmt.Printf("%s\n%s\n\n", theme.Success("✓ Complete"), result)
+		a.lastFailedTool = ""
+		a.lastFailedArgs = ""
+		return nil
+	}
+
+	if a.lastUserPrompt != "" {
+		fmt.Printf("\n%s\n", theme.Dim("Re-sending last prompt..."))
+		return a.processInput(a.lastUserPrompt)
+	}
+
+	fmt.Printf("\n%s\n\n", theme.Warning("Nothing to retry yet"))
+	return nil
+}
+
+// cmdExplain runs a structured exploration of the project (map, entry
+// points, key types, dependencies) and writes the result to
+// <state_dir>/overview.md, so a fresh session can orient itself without
+// re-reading the whole tree. When long-term memory is enabled, the overview
+// is also stored there for retrieval in future sessions.
+func (a *Agent) cmdExplain() error {
+	if a.projectAnalysis == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("No project analysis available (project scan was skipped at startup)"))
+		return nil
+	}
+
+	overview := a.buildOverviewDoc()
+
+	outPath := filepath.Join(a.config.StateDir(), "overview.md")
+	if err := os.WriteFile(outPath, []byte(overview), 0644); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to write overview: %v", err))
+		return nil
+	}
+
+	if a.memory != nil {
+		err := a.memory.Store(&memory.Memory{
+			Type:       memory.TypeFact,
+			Content:    overview,
+			Summary:    fmt.Sprintf("Architecture overview for %s", a.projectAnalysis.ProjectName),
+			Tags:       []string{"overview", "architecture", "explain"},
+			Importance: 0.8,
+		})
+		if err != nil {
+			fmt.Printf("%s\n", theme.Warning("Overview saved to %s, but memory storage failed: %v", outPath, err))
+			return nil
+		}
+	}
+
+	fmt.Printf("\n%s\n\n", theme.Success("✓ Wrote architecture overview to %s", outPath))
+	return nil
+}
+
+// buildOverviewDoc assembles the /explain markdown document from the
+// startup project analysis and (if indexed) the code graph.
+func (a *Agent) buildOverviewDoc() string {
+	analysis := a.projectAnalysis
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s — Architecture Overview\n\n", analysis.ProjectName)
+
+	sb.WriteString("## Project Map\n\n")
+	for _, lang := range analysis.Languages {
+		primary := ""
+		if lang.Primary {
+			primary = " (primary)"
+		}
+		fmt.Fprintf(&sb, "- %s: %d files%s\n", lang.Name, lang.FileCount, primary)
+	}
+	for _, fw := range analysis.Frameworks {
+		fmt.Fprintf(&sb, "- Framework: %s (%s)\n", fw.Name, fw.Language)
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Entry Points\n\n")
+	if len(analysis.Structure.EntryPoints) == 0 {
+		sb.WriteString("(none detected)\n\n")
+	} else {
+		for _, ep := range analysis.Structure.EntryPoints {
+			fmt.Fprintf(&sb, "- %s\n", ep)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Key Types\n\n")
+	if a.codeGraph == nil {
+		sb.WriteString("(code graph not indexed — enable LSP to populate this section)\n\n")
+	} else {
+		keyTypes := a.codeGraph.SymbolsByKind("class", "interface", "struct")
+		if len(keyTypes) == 0 {
+			sb.WriteString("(no class/interface/struct symbols indexed)\n\n")
+		} else {
+			for _, sym := range keyTypes {
+				fmt.Fprintf(&sb, "- `%s` (%s) — %s\n", sym.Name, sym.Kind, sym.FilePath)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("## Dependencies\n\n")
+	if len(analysis.Dependencies) == 0 {
+		sb.WriteString("(none detected)\n\n")
+	} else {
+		for _, dep := range analysis.Dependencies {
+			fmt.Fprintf(&sb, "- %s: %d (%s)\n", dep.Name, dep.Count, dep.Type)
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// cmdRewind drops the last n exchange pairs (each starting at a "user"
+// message and running through the assistant's reply and any tool calls in
+// between) from the conversation, for backing out of an exchange that keeps
+// steering the model wrong without restarting the whole session. Before
+// truncating it snapshots the current messages to disk as a safeguard, since
+// there's no in-memory undo once the slice is cut.
+func (a *Agent) cmdRewind(n int) error {
+	var userIdx []int
+	for i, msg := range a.messages {
+		if msg.Role == "user" {
+			userIdx = append(userIdx, i)
+		}
+	}
+
+	if len(userIdx) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("Nothing to rewind yet"))
+		return nil
+	}
+
+	if n > len(userIdx) {
+		n = len(userIdx)
+	}
+	cutAt := userIdx[len(userIdx)-n]
+
+	if err := a.snapshotMessagesForRewind(); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to save rewind checkpoint, aborting: %v", err))
+		return nil
+	}
+
+	dropped := len(a.messages) - cutAt
+	a.messages = a.messages[:cutAt]
+
+	fmt.Printf("\n%s\n\n", theme.Success("✓ Rewound %d exchange(s) (%d messages dropped)", n, dropped))
+	return nil
+}
+
+// snapshotMessagesForRewind writes the current message history to
+// <state_dir>/checkpoints/rewind-<n>.json before /rewind truncates it, so a
+// bad rewind can be recovered by hand even though there's no /unrewind yet.
+func (a *Agent) snapshotMessagesForRewind() error {
+	dir := filepath.Join(a.config.StateDir(), "checkpoints")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(a.messages, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("rewind-%d.json", time.Now().Unix()))
+	return os.WriteFile(path, data, 0644)
+}
+
+// cmdMemory handles `/memory list [--mine]`, listing recently stored
+// long-term memories, optionally filtered to the current git/OS user's own
+// entries in a workspace shared by several developers.
+func (a *Agent) cmdMemory(args []string) error {
+	if a.memory == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("Long-term memory is disabled (memory.enabled: false in config.yaml)"))
+		return nil
+	}
+
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /memory list [--mine]"))
+		return nil
+	}
+
+	mine := false
+	for _, arg := range args[1:] {
+		if arg == "--mine" {
+			mine = true
+		}
+	}
+
+	const limit = 20
+	var mems []*memory.Memory
+	var err error
+	if mine {
+		mems, err = a.memory.GetByAuthor(memory.CurrentUser(), limit)
+	} else {
+		mems, err = a.memory.GetRecent(limit)
+	}
+	if err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to list memories: %v", err))
+		return nil
+	}
+
+	if len(mems) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Dim("No memories found"))
+		return nil
+	}
+
+	fmt.Println()
+	for _, mem := range mems {
+		fmt.Printf("%s %s\n", theme.ToolBold(fmt.Sprintf("[%s]", mem.Type)), mem.Summary)
+		fmt.Printf("  %s\n", theme.Dim("by %s - %s", mem.Author, mem.CreatedAt.Format("2006-01-02 15:04")))
+	}
+	fmt.Println()
+	return nil
+}
+
+// feedbackDelta is how far /good and /bad nudge a memory's importance -
+// small enough that it takes repeated agreement or disagreement to move a
+// memory across a retrieval threshold, rather than one click overriding the
+// storeConversationMemories constants outright.
+const feedbackDelta float32 = 0.15
+
+// cmdFeedback handles `/good` and `/bad`, adjusting the importance of every
+// memory stored during the current turn - the only calibration signal the
+// otherwise hard-coded importance constants get.
+func (a *Agent) cmdFeedback(good bool) error {
+	if a.memory == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("Long-term memory is disabled (memory.enabled: false in config.yaml)"))
+		return nil
+	}
+
+	if len(a.memoriesInTurn) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Dim("No memories were stored this turn"))
+		return nil
+	}
+
+	delta := feedbackDelta
+	if !good {
+		delta = -feedbackDelta
+	}
+
+	adjusted := 0
+	for _, id := range a.memoriesInTurn {
+		if _, err := a.memory.AdjustImportance(id, delta); err != nil {
+			fmt.Printf("%s\n", theme.Error("Failed to adjust memory %s: %v", id, err))
+			continue
+		}
+		adjusted++
+	}
+
+	if good {
+		a.feedbackGood++
+	} else {
+		a.feedbackBad++
+	}
+
+	label := "Bumped"
+	if !good {
+		label = "Lowered"
+	}
+	noun := "memories"
+	if adjusted == 1 {
+		noun = "memory"
+	}
+	fmt.Printf("\n%s\n\n", theme.Success("%s importance of %d %s from this turn", label, adjusted, noun))
+	return nil
+}
+
+// cmdCheckpoint handles `/checkpoint list`, `/checkpoint restore <id>`, and
+// `/checkpoint branch <id>`, backed by checkpoint.Manager's auto-saved
+// conversation snapshots - a durable, cross-restart complement to /rewind's
+// in-memory truncation.
+func (a *Agent) cmdCheckpoint(args []string) error {
+	if a.checkpoints == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("Checkpointing is disabled (checkpoint.enabled: false in config.yaml)"))
+		return nil
+	}
+	if len(args) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /checkpoint list|restore <id>|branch <id>"))
+		return nil
+	}
+
+	switch args[0] {
+	case "list":
+		history, err := a.checkpoints.GetThreadHistory()
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Failed to list checkpoints: %v", err))
+			return nil
+		}
+		if len(history) == 0 {
+			fmt.Printf("\n%s\n\n", theme.Dim("No checkpoints saved yet"))
+			return nil
+		}
+		fmt.Println()
+		for _, cp := range history {
+			fmt.Printf("%s %s\n", theme.ToolBold(cp.ID), cp.Description)
+			fmt.Printf("  %s\n", theme.Dim("%s - %d messages", cp.Timestamp.Format("2006-01-02 15:04:05"), len(cp.Messages)))
+		}
+		fmt.Println()
+		return nil
+
+	case "restore":
+		if len(args) < 2 {
+			fmt.Printf("\n%s\n\n", theme.Warning("Usage: /checkpoint restore <id>"))
+			return nil
+		}
+		messages, err := a.checkpoints.RestoreCheckpoint(args[1])
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Failed to restore checkpoint: %v", err))
+			return nil
+		}
+		a.messages = messages
+		fmt.Printf("\n%s\n\n", theme.Success("✓ Restored checkpoint %s (%d messages)", args[1], len(messages)))
+		return nil
+
+	case "branch":
+		if len(args) < 2 {
+			fmt.Printf("\n%s\n\n", theme.Warning("Usage: /checkpoint branch <id>"))
+			return nil
+		}
+		branchName := fmt.Sprintf("branch-%s", time.Now().Format("20060102-150405"))
+		thread, err := a.checkpoints.BranchThread(args[1], branchName)
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Failed to branch checkpoint: %v", err))
+			return nil
+		}
+		messages, err := a.checkpoints.RestoreCheckpoint(args[1])
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Branched into new thread %s but failed to load its messages: %v", thread.ID, err))
+			return nil
+		}
+		a.messages = messages
+		fmt.Printf("\n%s\n\n", theme.Success("✓ Branched checkpoint %s into new thread %s", args[1], thread.ID))
+		return nil
+
+	default:
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /checkpoint list|restore <id>|branch <id>"))
+		return nil
+	}
+}
+
+// cmdModel handles `/model list|switch <profile>`, letting the user swap the
+// active LLM backend mid-session via a named profile from config.yaml's
+// `providers` map, instead of restarting with a different config.yaml.
+func (a *Agent) cmdModel(args []string) error {
+	if len(args) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /model list|switch <profile>"))
+		return nil
+	}
+
+	switch args[0] {
+	case "list":
+		fmt.Println()
+		fmt.Printf("%s %s (%s)\n", theme.ToolBold("*"), a.llmClient.Profile(), a.config.LLM.Model)
+		for name, profile := range a.config.Providers {
+			fmt.Printf("  %s %s\n", theme.ToolBold(name), theme.Dim("%s (%s)", profile.Endpoint, profile.Model))
+		}
+		fmt.Println()
+		return nil
+
+	case "switch":
+		if len(args) < 2 {
+			fmt.Printf("\n%s\n\n", theme.Warning("Usage: /model switch <profile>"))
+			return nil
+		}
+		profile, ok := a.config.Providers[args[1]]
+		if !ok {
+			fmt.Printf("\n%s\n\n", theme.Error("Unknown provider profile: %s (see /model list)", args[1]))
+			return nil
+		}
+		a.llmClient.SwitchProfile(args[1], profile)
+		fmt.Printf("\n%s\n\n", theme.Success("✓ Switched to provider %s (%s)", args[1], profile.Model))
+		return nil
+
+	default:
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /model list|switch <profile>"))
+		return nil
+	}
+}
+
+// cmdShellEscape handles a leading "!<command>" line: runs the command
+// directly against the OS instead of asking the LLM to do it, which is
+// faster for trivial commands and doesn't burn a turn's tokens. The command
+// and its output are still recorded as a user-role observation so a later
+// LLM turn can reference what happened.
+func (a *Agent) cmdShellEscape(command string) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: !<command>"))
+		return
+	}
+
+	argsJSON, err := json.Marshal(tools.BashArgs{Command: command})
+	if err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to build command: %v", err))
+		return
+	}
+
+	fmt.Printf("\n%s %s\n", theme.Tool("🔧 Running:"), theme.Dim("%s", command))
+	output, execErr := a.toolRegistry.Execute(context.Background(), "bash", string(argsJSON))
+	if execErr != nil {
+		fmt.Printf("%s\n\n", theme.Error("%v", execErr))
+	} else {
+		fmt.Printf("%s\n\n", output)
+	}
+
+	if a.auditLog != nil {
+		if auditErr := a.auditLog.Record(a.auditActor, "bash", string(argsJSON), output, execErr, true, "shell-escape"); auditErr != nil {
+			fmt.Printf("%s\n", theme.Warning("Failed to write audit log entry: %v", auditErr))
+		}
+	}
+
+	observation := fmt.Sprintf("Ran `%s` directly (bypassing the model):\n```\n%s\n```", command, output)
+	a.messages = append(a.messages, llm.Message{Role: "user", Content: observation})
+	a.appendToConversationHistory("user", observation)
+}
+
+// cmdLSP handles `/lsp install <language>`, running the known install
+// command for a missing LSP server (from initialization.InstallCommandFor)
+// with confirmation, instead of only warning about it at startup.
+func (a *Agent) cmdLSP(args []string) error {
+	if len(args) < 2 || args[0] != "install" {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /lsp install <language>"))
+		return nil
+	}
+
+	lang := strings.ToLower(args[1])
+	serverCfg, configured := a.config.LSP.Servers[lang]
+	if !configured {
+		fmt.Printf("\n%s\n\n", theme.Warning("No LSP server configured for %q in config.yaml's lsp.servers", lang))
+		return nil
+	}
+
+	if _, err := exec.LookPath(serverCfg.Command); err == nil {
+		fmt.Printf("\n%s\n\n", theme.Success("✓ %s is already installed (%s)", lang, serverCfg.Command))
+		return nil
+	}
+
+	installCmd, known := initialization.InstallCommandFor(lang)
+	if !known {
+		fmt.Printf("\n%s\n\n", theme.Warning("No known install command for %q - install %s manually", lang, serverCfg.Command))
+		return nil
+	}
+
+	fmt.Printf("\n%s\n", theme.User("This will run:"))
+	fmt.Printf("  %s\n", theme.ToolBold(installCmd))
+	fmt.Printf("%s", theme.UserBold("Proceed? [y/n]: "))
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(response)) != "y" && strings.ToLower(strings.TrimSpace(response)) != "yes" {
+		fmt.Printf("%s\n", theme.Dim("Cancelled."))
+		return nil
+	}
+
+	cmd := exec.Command("sh", "-c", installCmd)
+	output, err := cmd.CombinedOutput()
+	fmt.Printf("%s\n", output)
+	if err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Install command failed: %v", err))
+		return nil
+	}
+
+	fmt.Printf("\n%s\n\n", theme.Success("✓ Installed %s - restart gocode to pick it up", lang))
+	return nil
+}
+
+// cmdIndex handles `/index status` (report how many workspace files are
+// unindexed or stale against the vector store's content-hash records) and
+// `/index refresh` (re-index whatever status reports as out of date).
+func (a *Agent) cmdIndex(args []string) error {
+	if a.embedMgr == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("Embeddings are disabled (embeddings.enabled: false in config.yaml)"))
+		return nil
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /index status | /index refresh"))
+		return nil
+	}
+
+	switch args[0] {
+	case "status":
+		status, err := a.embedMgr.Status(a.config.WorkingDir)
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Failed to check index status: %v", err))
+			return nil
+		}
+
+		fmt.Println()
+		fmt.Printf("  %d file(s) tracked, %d up to date\n", status.TotalFiles, status.UpToDate)
+		if len(status.NeverIndexed) > 0 {
+			fmt.Printf("  %s never indexed:\n", theme.Warning("%d", len(status.NeverIndexed)))
+			for _, path := range status.NeverIndexed {
+				fmt.Printf("    %s\n", theme.Dim(path))
+			}
+		}
+		if len(status.Stale) > 0 {
+			fmt.Printf("  %s stale (content changed since last indexed):\n", theme.Warning("%d", len(status.Stale)))
+			for _, path := range status.Stale {
+				fmt.Printf("    %s\n", theme.Dim(path))
+			}
+		}
+		if len(status.NeverIndexed) == 0 && len(status.Stale) == 0 {
+			fmt.Printf("  %s\n", theme.Success("index is fully up to date"))
+		}
+		fmt.Println()
+
+	case "refresh":
+		fmt.Printf("\n%s\n", theme.User("Refreshing index..."))
+		if err := a.embedMgr.Refresh(context.Background(), a.config.WorkingDir); err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Refresh failed: %v", err))
+			return nil
+		}
+		fmt.Printf("\n%s\n\n", theme.Success("✓ Index refreshed"))
+
+	default:
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /index status | /index refresh"))
+	}
+
+	return nil
+}
+
+// cmdTools handles `/tools` (list every registered tool and whether it's
+// enabled) and `/tools enable|disable <name>` (toggle one for the rest of
+// the session - e.g. disable web_fetch/web_search before pasting
+// proprietary code, or enable bash only for a task that needs it). A
+// disabled tool drops out of the schema offered to the LLM on the very next
+// turn, since toolDefs is rebuilt from the registry on every completion
+// request.
+func (a *Agent) cmdTools(args []string) error {
+	if len(args) == 0 {
+		fmt.Println()
+		for _, name := range a.toolRegistry.AllToolNames() {
+			if a.toolRegistry.IsToolEnabled(name) {
+				fmt.Printf("  %s %s\n", theme.Success("✓"), name)
+			} else {
+				fmt.Printf("  %s %s\n", theme.Error("✗"), theme.Dim(name))
+			}
+		}
+		fmt.Printf("\n%s\n\n", theme.Dim("Usage: /tools enable|disable <name>"))
+		return nil
+	}
+
+	if len(args) != 2 || (args[0] != "enable" && args[0] != "disable") {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /tools [enable|disable <name>]"))
+		return nil
+	}
+
+	if err := a.toolRegistry.SetToolEnabled(args[1], args[0] == "enable"); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("%v", err))
+		return nil
+	}
+
+	fmt.Printf("\n%s\n\n", theme.Success("✓ %sd %s", args[0], args[1]))
+	return nil
+}
+
+// cmdUndo handles `/undo` - reverts every file the last turn wrote, edited,
+// or deleted back to its content from just before that turn started.
+func (a *Agent) cmdUndo() error {
+	restored, err := a.rollbackTool.RestoreLastRun()
+	if err != nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("%v", err))
+		return nil
+	}
+	if len(restored) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Dim("Nothing to undo."))
+		return nil
+	}
+
+	fmt.Printf("\n%s\n", theme.Success("✓ Reverted %d file(s):", len(restored)))
+	for _, path := range restored {
+		fmt.Printf("  - %s\n", path)
+	}
+	fmt.Println()
+	return nil
+}
+
+// cmdShells handles `/shells` (list every background bash shell tracked
+// across turns - named via bash's `name` argument or auto-generated -
+// along with its running/exited status and originating command).
+func (a *Agent) cmdShells(args []string) error {
+	procs := a.bashTool.ListProcesses()
+	if len(procs) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Dim("No background shells."))
+		return nil
+	}
+
+	fmt.Println()
+	for _, proc := range procs {
+		status := theme.Success("running")
+		if !proc.IsRunning() {
+			status = theme.Dim("exited")
+		}
+		fmt.Printf("  %s  %s  %s\n", proc.ID, status, proc.Command)
+	}
+	fmt.Printf("\n%s\n\n", theme.Dim("Use bash_output/kill_shell with the ID shown above."))
+	return nil
+}
+
+// cmdCopy handles `/copy last-response`, `/copy last-diff`, and
+// `/copy file <path>`, placing text on the system clipboard so a user
+// doesn't have to select long colorized terminal output by hand.
+func (a *Agent) cmdCopy(args []string) error {
+	if len(args) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /copy last-response|last-diff|file <path>"))
+		return nil
+	}
+
+	var text string
+	switch args[0] {
+	case "last-response":
+		text = a.lastAssistantMessage()
+		if text == "" {
+			fmt.Printf("\n%s\n\n", theme.Dim("No assistant response yet this session."))
+			return nil
+		}
+	case "last-diff":
+		diff, err := a.lastTurnDiff()
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Warning("%v", err))
+			return nil
+		}
+		if diff == "" {
+			fmt.Printf("\n%s\n\n", theme.Dim("No files were changed last turn."))
+			return nil
+		}
+		text = diff
+	case "file":
+		if len(args) < 2 {
+			fmt.Printf("\n%s\n\n", theme.Warning("Usage: /copy file <path>"))
+			return nil
+		}
+		content, err := os.ReadFile(args[1])
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Warning("%v", err))
+			return nil
+		}
+		text = string(content)
+	default:
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /copy last-response|last-diff|file <path>"))
+		return nil
+	}
+
+	if err := clipboard.Write(text); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("%v", err))
+		return nil
+	}
+
+	fmt.Printf("\n%s\n\n", theme.Success("✓ Copied to clipboard (%d bytes)", len(text)))
+	return nil
+}
+
+// lastAssistantMessage returns the most recent assistant message's content,
+// or "" if the conversation has none yet.
+func (a *Agent) lastAssistantMessage() string {
+	for i := len(a.messages) - 1; i >= 0; i-- {
+		if a.messages[i].Role == "assistant" && a.messages[i].Content != "" {
+			return a.messages[i].Content
+		}
+	}
+	return ""
+}
+
+// lastTurnDiff concatenates the unified diff for every file touched in the
+// most recently finished turn, using the session-start workspace snapshot
+// as the baseline.
+func (a *Agent) lastTurnDiff() (string, error) {
+	if a.wsSnapshot == nil || len(a.filesTouchedInTurn) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	for _, path := range a.uniqueTools(a.filesTouchedInTurn) {
+		diff, err := a.wsSnapshot.FileDiff(path)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "--- %s ---\n%s\n", path, diff)
+	}
+	return b.String(), nil
+}
+
+// recommendationSections maps a Recommendation.Type to the config.yaml
+// top-level section its "enabled:" flag lives under - everything /setup
+// apply can flip besides running an install command.
+var recommendationSections = map[string]string{
+	"retrieval":  "retrieval",
+	"checkpoint": "checkpoint",
+	"memory":     "memory",
+	"lsp":        "lsp",
+}
+
+// cmdSetup handles `/setup` (list outstanding recommendations from project
+// initialization) and `/setup apply <n>` (act on one - run its install
+// command and/or flip the relevant config.yaml section, with confirmation),
+// so recommendations shown once at startup aren't simply forgotten.
+func (a *Agent) cmdSetup(args []string) error {
+	if a.projectAnalysis == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("No project analysis available - it's produced on first run or cached under .gocode/"))
+		return nil
+	}
+
+	recs := a.projectAnalysis.Recommendations
+	if len(recs) == 0 {
+		// A cached analysis (not first run) doesn't persist recommendations,
+		// so regenerate them against the current config instead of just
+		// showing nothing.
+		recs = initialization.NewFeatureDetector(a.projectAnalysis, a.config).GenerateRecommendations()
+	}
+
+	if len(recs) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Success("✓ No outstanding recommendations"))
+		return nil
+	}
+
+	if len(args) == 0 {
+		fmt.Println()
+		for i, rec := range recs {
+			fmt.Printf("%s %s %s\n", theme.ToolBold(fmt.Sprintf("[%d]", i+1)), theme.Dim("(%s)", rec.Priority), rec.Title)
+			fmt.Printf("    %s\n", rec.Description)
+		}
+		fmt.Printf("\n%s\n\n", theme.Dim("Usage: /setup apply <n>"))
+		return nil
+	}
+
+	if args[0] != "apply" || len(args) < 2 {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /setup [apply <n>]"))
+		return nil
+	}
+
+	idx, err := strconv.Atoi(args[1])
+	if err != nil || idx < 1 || idx > len(recs) {
+		fmt.Printf("\n%s\n\n", theme.Warning("Invalid recommendation number - run /setup to list them"))
+		return nil
+	}
+
+	return a.applyRecommendation(recs[idx-1])
+}
+
+// applyRecommendation runs a Recommendation's install command (if any) and
+// flips its config.yaml section on, prompting for confirmation before each.
+func (a *Agent) applyRecommendation(rec initialization.Recommendation) error {
+	if rec.Action != "" {
+		fmt.Printf("\n%s\n", theme.User("This will run:"))
+		fmt.Printf("  %s\n", theme.ToolBold(rec.Action))
+		fmt.Printf("%s", theme.UserBold("Proceed? [y/n]: "))
+
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(response)) != "y" && strings.ToLower(strings.TrimSpace(response)) != "yes" {
+			fmt.Printf("%s\n\n", theme.Dim("Cancelled."))
+			return nil
+		}
+
+		cmd := exec.Command("sh", "-c", rec.Action)
+		output, err := cmd.CombinedOutput()
+		fmt.Printf("%s\n", output)
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Install command failed: %v", err))
+			return nil
+		}
+	}
+
+	section, ok := recommendationSections[rec.Type]
+	if !ok {
+		fmt.Printf("\n%s\n\n", theme.Success("✓ Applied %q", rec.Title))
+		return nil
+	}
+	if a.config.ConfigPath == "" {
+		fmt.Printf("\n%s\n\n", theme.Warning("Config file path unknown - enable %q manually in config.yaml", section))
+		return nil
+	}
+
+	fmt.Printf("\n%s %s %s\n", theme.User("This will set"), theme.ToolBold(section+".enabled: true"), theme.User("in config.yaml. Proceed? [y/n]:"))
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(response)) != "y" && strings.ToLower(strings.TrimSpace(response)) != "yes" {
+		fmt.Printf("%s\n\n", theme.Dim("Cancelled."))
+		return nil
+	}
+
+	if err := config.SetEnabled(a.config.ConfigPath, section, true); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to update config.yaml: %v", err))
+		return nil
+	}
+
+	fmt.Printf("\n%s\n\n", theme.Success("✓ Applied %q - restart gocode to pick it up", rec.Title))
+	return nil
+}
+
+// cmdChanges handles `/changes` (a summary of every file added, modified, or
+// deleted since the session's workspace snapshot was taken) and `/changes
+// <file>` (a line diff for one of those files), independent of git so it
+// still works on unstaged or untracked changes.
+func (a *Agent) cmdChanges(args []string) error {
+	if a.wsSnapshot == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("No workspace snapshot available for this session"))
+		return nil
+	}
+
+	if len(args) > 0 {
+		diff, err := a.wsSnapshot.FileDiff(args[0])
+		if err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("%v", err))
+			return nil
+		}
+		fmt.Printf("\n%s\n%s\n", theme.HeaderBold(args[0]), diff)
+		return nil
+	}
+
+	changes, err := a.wsSnapshot.Diff()
+	if err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to diff workspace: %v", err))
+		return nil
+	}
+	if changes.Empty() {
+		fmt.Printf("\n%s\n\n", theme.Dim("No changes since session start (%s)", a.wsSnapshot.TakenAt.Format("15:04:05")))
+		return nil
+	}
+
+	fmt.Println()
+	for _, f := range changes.Added {
+		fmt.Printf("  %s %s\n", theme.Success("+"), f)
+	}
+	for _, f := range changes.Modified {
+		fmt.Printf("  %s %s\n", theme.Warning("~"), f)
+	}
+	for _, f := range changes.Deleted {
+		fmt.Printf("  %s %s\n", theme.Error("-"), f)
+	}
+	fmt.Printf("\n%s\n\n", theme.Dim("Use /changes <file> to see a line diff for one file"))
+	return nil
+}
+
+// contextSectionOrder lists the message categories cmdContext breaks the
+// prompt into, in the order they typically appear in a.messages.
+var contextSectionOrder = []string{"System prompt", "Retrieved context", "TODO state", "Conversation history"}
+
+// classifyContextMessage buckets a message into one of contextSectionOrder
+// by the same content markers the rest of the agent package uses to build
+// it: buildContextMessageSimple/context_injection.tmpl always open with "#
+// Retrieved Context", and formatTodoContext always opens with the TODO
+// header.
+func classifyContextMessage(msg llm.Message) string {
+	switch {
+	case strings.HasPrefix(msg.Content, "# Retrieved Context"):
+		return "Retrieved context"
+	case strings.HasPrefix(msg.Content, "📋 **Current TODO List:**"):
+		return "TODO state"
+	case msg.Role == "system":
+		return "System prompt"
+	default:
+		return "Conversation history"
+	}
+}
+
+// cmdContext handles `/context`, breaking down the current prompt into
+// per-section token counts (system prompt, retrieved context, TODO state,
+// conversation history) plus which history messages would be dropped first
+// if the context manager had to prune, so budget issues are debuggable
+// without dumping the raw message slice.
+func (a *Agent) cmdContext() error {
+	if len(a.messages) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("No messages in context yet"))
+		return nil
+	}
+
+	budgetCfg := ctxbudget.DefaultBudgetConfig()
+	if a.config.LLM.ContextWindow > 0 {
+		budgetCfg.MaxTokens = a.config.LLM.ContextWindow
+	}
+	ctxMgr := ctxbudget.NewManager(budgetCfg)
+	ctxMgr.SetMessages(a.messages)
+
+	sectionTokens := make(map[string]int, len(contextSectionOrder))
+	sectionCounts := make(map[string]int, len(contextSectionOrder))
+	for _, msg := range a.messages {
+		section := classifyContextMessage(msg)
+		sectionTokens[section] += ctxbudget.EstimateTokens(msg.Content)
+		sectionCounts[section]++
+	}
+
+	total := 0
+	for _, tokens := range sectionTokens {
+		total += tokens
+	}
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Context window: %d tokens (%d used, %d%% full)",
+		budgetCfg.MaxTokens, total, total*100/budgetCfg.MaxTokens))
+	for _, section := range contextSectionOrder {
+		if sectionCounts[section] == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d tokens (%d message(s))", section, sectionTokens[section], sectionCounts[section]))
+	}
+
+	fullBytes, compactBytes := 0, 0
+	for _, tool := range a.toolRegistry.All() {
+		description, params := tool.Description(), tool.Parameters()
+		fullBytes += len(description) + len(fmt.Sprintf("%v", params))
+		compactDescription, compactParams := tools.Compact(description, params)
+		compactBytes += len(compactDescription) + len(fmt.Sprintf("%v", compactParams))
+	}
+	compactActive := a.config.LLM.ContextWindow > 0 && a.config.LLM.ContextWindow < tools.CompactContextThreshold
+	toolTokens, compactToolTokens := ctxbudget.EstimateTokens(strings.Repeat("x", fullBytes)), ctxbudget.EstimateTokens(strings.Repeat("x", compactBytes))
+	lines = append(lines, fmt.Sprintf("Tool schemas: ~%d tokens full, ~%d tokens compact (%s)",
+		toolTokens, compactToolTokens, map[bool]string{true: "compact mode active", false: "compact mode inactive"}[compactActive]))
+
+	if ctxMgr.NeedsPruning() {
+		pruneCandidates := ctxbudget.NewManager(budgetCfg)
+		pruneCandidates.SetMessages(a.messages)
+		survivors := pruneCandidates.PruneMessages()
+
+		survivingNonSystem := 0
+		for _, msg := range survivors {
+			if msg.Role != "system" {
+				survivingNonSystem++
+			}
+		}
+		totalNonSystem := 0
+		for _, msg := range a.messages {
+			if msg.Role != "system" {
+				totalNonSystem++
+			}
+		}
+		droppedCount := totalNonSystem - survivingNonSystem
+
+		var dropped []string
+		seen := 0
+		for _, msg := range a.messages {
+			if msg.Role == "system" {
+				continue
+			}
+			if seen >= droppedCount {
+				break
+			}
+			seen++
+			if len(dropped) < 3 {
+				dropped = append(dropped, fmt.Sprintf("[%s] %s", msg.Role, truncateLine(msg.Content, 80)))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("Over prune threshold (%.0f%%) - %d message(s) next to be pruned:", budgetCfg.PruneThreshold*100, droppedCount))
+		lines = append(lines, dropped...)
+	}
+
+	fmt.Printf("\n%s\n", theme.SummaryBox("🪟 Context Breakdown", lines))
+	return nil
+}
+
+// cmdCapabilities handles `/capabilities`, printing the startup capability
+// report - which optional subsystems (memory, audit, workspace snapshot,
+// embeddings, per-language LSP) are available, disabled, or degraded, and
+// why. Degraded memory and embeddings entries are re-probed every turn (see
+// retryDegradedCapabilities), so those reflect current state; every other
+// entry still shows what New saw at startup.
+func (a *Agent) cmdCapabilities() error {
+	if len(a.capabilities) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("No capability data recorded"))
+		return nil
+	}
+
+	var lines []string
+	for _, c := range a.capabilities {
+		switch {
+		case c.Available:
+			lines = append(lines, fmt.Sprintf("%s %s - %s", theme.Success("✓"), c.Name, c.Detail))
+		case c.Detail == "disabled in config":
+			lines = append(lines, fmt.Sprintf("%s %s - %s", theme.Dim("-"), c.Name, c.Detail))
+		default:
+			lines = append(lines, fmt.Sprintf("%s %s - %s", theme.Warning("⚠"), c.Name, c.Detail))
+		}
+	}
+
+	fmt.Printf("\n%s\n", theme.SummaryBox("🩺 Capabilities", lines))
+	return nil
+}
+
+// touchActivity records that the user just submitted input, resetting the
+// idle clock watched by watchIdle.
+func (a *Agent) touchActivity() {
+	a.activityMu.Lock()
+	a.lastActivity = time.Now()
+	a.activityMu.Unlock()
+}
+
+// watchConfig polls config.yaml for changes and hot-reloads the settings
+// that are safe to change mid-session: confirmation policy and logging
+// level. Settings that only take effect at startup (the LLM server's model
+// path and the rest of llm.server, and retrieval weights, which the running
+// retriever copied when it was built) are flagged so the user knows a
+// restart is needed to pick them up. It
+// runs for the lifetime of the REPL whenever the config file's path is
+// known.
+func (a *Agent) watchConfig() {
+	info, err := os.Stat(a.config.ConfigPath)
+	if err != nil {
+		return
+	}
+	lastMod := info.ModTime()
+
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		info, err := os.Stat(a.config.ConfigPath)
+		if err != nil || !info.ModTime().After(lastMod) {
+			continue
+		}
+		lastMod = info.ModTime()
+
+		newCfg, err := config.Load(a.config.ConfigPath)
+		if err != nil {
+			fmt.Printf("\n%s\n", theme.Warning("config.yaml changed but failed to reload: %v", err))
+			continue
+		}
+
+		var applied, flagged []string
+		if !reflect.DeepEqual(a.config.Confirmation, newCfg.Confirmation) {
+			a.config.Confirmation = newCfg.Confirmation
+			a.confirmSys = confirmation.New(&a.config.Confirmation)
+			applied = append(applied, "confirmation policy")
+		}
+		if a.config.Retrieval.Weights != newCfg.Retrieval.Weights {
+			a.config.Retrieval.Weights = newCfg.Retrieval.Weights
+			flagged = append(flagged, "retrieval.weights (the retriever keeps the weights it was built with)")
+		}
+		if a.config.Logging.Level != newCfg.Logging.Level {
+			applied = append(applied, fmt.Sprintf("logging level (%s -> %s)", a.config.Logging.Level, newCfg.Logging.Level))
+			a.config.Logging.Level = newCfg.Logging.Level
+		}
+
+		if a.config.LLM.Server != newCfg.LLM.Server {
+			flagged = append(flagged, "llm.server (model path/runtime flags)")
+		}
+
+		if len(applied) == 0 && len(flagged) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s\n", theme.Dim("config.yaml changed:"))
+		for _, s := range applied {
+			fmt.Printf("  %s %s\n", theme.Success("✓ reloaded"), theme.Dim(s))
+		}
+		for _, s := range flagged {
+			fmt.Printf("  %s %s\n", theme.Warning("⚠ needs restart"), theme.Dim(s))
+		}
+	}
+}
+
+// watchIdle polls for inactivity and releases the managed llama-server's
+// VRAM once the configured idle timeout elapses. It runs for the lifetime
+// of the REPL when idle_timeout_minutes is set.
+func (a *Agent) watchIdle() {
+	threshold := time.Duration(a.config.LLM.IdleTimeoutMinutes) * time.Minute
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		a.activityMu.Lock()
+		idleFor := time.Since(a.lastActivity)
+		alreadyStopped := a.serverIdleStopped
+		a.activityMu.Unlock()
+
+		if alreadyStopped || idleFor < threshold {
+			continue
+		}
+
+		a.todoTool.Save()
+		if a.config.LLM.AutoManage {
+			if err := a.serverManager.Stop(); err == nil {
+				a.activityMu.Lock()
+				a.serverIdleStopped = true
+				a.activityMu.Unlock()
+				fmt.Printf("\n%s\n", theme.Dim("Idle timeout reached — stopped llama-server to free VRAM. It will restart on your next message."))
+			}
+		}
+	}
+}
+
+// resumeFromIdleIfNeeded transparently restarts the managed server after an
+// idle-triggered stop, so the next turn just pays the startup cost instead
+// of failing.
+func (a *Agent) resumeFromIdleIfNeeded() {
+	a.activityMu.Lock()
+	stopped := a.serverIdleStopped
+	a.activityMu.Unlock()
+
+	if !stopped {
+		return
+	}
+
+	fmt.Printf("\n%s\n", theme.Dim("Resuming llama-server after idle timeout..."))
+	if err := a.serverManager.Start(); err != nil {
+		fmt.Printf("%s\n", theme.Error("Failed to restart llama-server: %v", err))
+		return
+	}
+
+	a.activityMu.Lock()
+	a.serverIdleStopped = false
+	a.activityMu.Unlock()
+}
+
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for n1 := n / unit; n1 >= unit; n1 /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jake/gocode/internal/theme"
+)
+
+// RefactorSession tracks an in-progress /refactor branch: the branch the
+// user was on when it started (to return to on merge/abandon) and the
+// temporary branch the agent's changes landed on.
+type RefactorSession struct {
+	BaseBranch string
+	Branch     string
+}
+
+// runRefactorGit runs a git subcommand rooted at workingDir, mirroring
+// tools.runGit's error-wrapping (that helper is unexported to internal/tools,
+// so /refactor - which lives in internal/agent - gets its own copy rather
+// than exporting one just for this).
+func runRefactorGit(ctx context.Context, workingDir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = workingDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// cmdRefactor drives /refactor's three forms:
+//
+//	/refactor <description>  - branches off the current commit, lets the
+//	                            agent make the requested multi-file change
+//	                            there, self-checks it, and prints a diff
+//	/refactor merge           - merges the branch back and cleans it up
+//	/refactor abandon         - discards the branch and returns as if the
+//	                            session never happened
+//
+// The working tree stays on the temporary branch between start and
+// merge/abandon, so ordinary editing continues to work normally; only the
+// final decision (keep or discard) touches the user's original branch.
+func (a *Agent) cmdRefactor(args []string) error {
+	if len(args) == 0 {
+		fmt.Printf("\n%s\n\n", theme.Warning("Usage: /refactor <description> | /refactor merge | /refactor abandon"))
+		return nil
+	}
+
+	switch args[0] {
+	case "merge":
+		return a.refactorFinish(true)
+	case "abandon":
+		return a.refactorFinish(false)
+	default:
+		return a.refactorStart(strings.Join(args, " "))
+	}
+}
+
+func (a *Agent) refactorStart(description string) error {
+	if a.refactor != nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("A /refactor session is already open on branch %s - run /refactor merge or /refactor abandon first", a.refactor.Branch))
+		return nil
+	}
+
+	ctx := context.Background()
+	workingDir := a.config.WorkingDir
+
+	base, err := runRefactorGit(ctx, workingDir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to determine current branch: %v", err))
+		return nil
+	}
+	if base == "HEAD" {
+		fmt.Printf("\n%s\n\n", theme.Warning("Refusing to start /refactor from a detached HEAD - check out a branch first"))
+		return nil
+	}
+
+	branch := fmt.Sprintf("refactor/%d", time.Now().Unix())
+	if _, err := runRefactorGit(ctx, workingDir, "checkout", "-b", branch); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to create branch %s: %v", branch, err))
+		return nil
+	}
+
+	a.refactor = &RefactorSession{BaseBranch: base, Branch: branch}
+	fmt.Printf("\n%s\n\n", theme.Agent("🌿 Working on %s (off %s) - your working tree stays here until /refactor merge or /refactor abandon", branch, base))
+
+	if err := a.processInput(description); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Refactor turn failed: %v", err))
+	}
+
+	a.refactorSelfCheck(ctx, workingDir)
+	a.refactorShowDiff(ctx, workingDir)
+
+	fmt.Printf("\n%s\n\n", theme.Dim("Review the diff above, then run /refactor merge to keep it or /refactor abandon to discard it."))
+	return nil
+}
+
+// refactorSelfCheck runs the same build/test verification the self-check
+// system applies to completion claims, unconditionally, since a refactor's whole
+// point is to be validated before it's offered for merge.
+func (a *Agent) refactorSelfCheck(ctx context.Context, workingDir string) {
+	projectContext := workingDir
+	if a.projectAnalysis != nil {
+		for _, lang := range a.projectAnalysis.Languages {
+			projectContext += " " + lang.Name
+		}
+	}
+
+	claims := []CompletionClaim{
+		{ClaimType: "build_success", Content: "refactor build check"},
+		{ClaimType: "tests_passed", Content: "refactor test check"},
+	}
+	verified, err := a.selfCheck.VerifyClaims(ctx, claims, projectContext)
+	if err != nil {
+		fmt.Printf("%s\n", theme.Error("Self-check error: %v", err))
+		return
+	}
+	if feedback := a.selfCheck.GenerateFeedbackMessage(verified); feedback != "" {
+		fmt.Printf("\n%s\n", feedback)
+	}
+}
+
+func (a *Agent) refactorShowDiff(ctx context.Context, workingDir string) {
+	// The agent's edits are uncommitted; stage them (new files included) so
+	// the diff against the base branch shows everything the session changed.
+	if _, err := runRefactorGit(ctx, workingDir, "add", "-A"); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to stage changes on %s: %v", a.refactor.Branch, err))
+		return
+	}
+	diff, err := runRefactorGit(ctx, workingDir, "diff", "--cached", a.refactor.BaseBranch)
+	if err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to diff %s against %s: %v", a.refactor.Branch, a.refactor.BaseBranch, err))
+		return
+	}
+	if diff == "" {
+		fmt.Printf("\n%s\n", theme.Dim("No changes yet."))
+		return
+	}
+	fmt.Printf("\n%s\n%s\n", theme.ToolBold(fmt.Sprintf("Diff (%s vs %s):", a.refactor.Branch, a.refactor.BaseBranch)), diff)
+}
+
+func (a *Agent) refactorFinish(merge bool) error {
+	if a.refactor == nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("No /refactor session is open"))
+		return nil
+	}
+
+	ctx := context.Background()
+	workingDir := a.config.WorkingDir
+	session := a.refactor
+
+	// Commit the session's changes onto the temporary branch, so merge has
+	// them to bring back and abandon leaves none behind in the working tree.
+	if _, err := runRefactorGit(ctx, workingDir, "add", "-A"); err == nil {
+		if status, _ := runRefactorGit(ctx, workingDir, "status", "--porcelain"); status != "" {
+			if _, err := runRefactorGit(ctx, workingDir, "commit", "-m", "refactor: "+session.Branch); err != nil {
+				fmt.Printf("\n%s\n\n", theme.Error("Failed to commit changes on %s: %v", session.Branch, err))
+				return nil
+			}
+		}
+	}
+
+	if _, err := runRefactorGit(ctx, workingDir, "checkout", session.BaseBranch); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Error("Failed to check out %s: %v", session.BaseBranch, err))
+		return nil
+	}
+
+	if merge {
+		if _, err := runRefactorGit(ctx, workingDir, "merge", "--no-ff", session.Branch); err != nil {
+			fmt.Printf("\n%s\n\n", theme.Error("Merge failed - branch %s left in place for manual resolution: %v", session.Branch, err))
+			return nil
+		}
+	}
+
+	if _, err := runRefactorGit(ctx, workingDir, "branch", "-D", session.Branch); err != nil {
+		fmt.Printf("\n%s\n\n", theme.Warning("Checked out %s, but couldn't delete %s: %v", session.BaseBranch, session.Branch, err))
+	}
+
+	a.refactor = nil
+
+	if merge {
+		fmt.Printf("\n%s\n\n", theme.Success("✓ Merged %s into %s", session.Branch, session.BaseBranch))
+	} else {
+		fmt.Printf("\n%s\n\n", theme.Success("✓ Abandoned %s - back on %s", session.Branch, session.BaseBranch))
+	}
+	return nil
+}
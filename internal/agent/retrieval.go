@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jake/gocode/internal/config"
+	"github.com/jake/gocode/internal/gitignore"
+	"github.com/jake/gocode/internal/retrieval"
+)
+
+// retrievalSkipDirs mirrors initialization.Analyzer's hard-coded skip list -
+// kept as a small local copy since that package's list is unexported and
+// this walk indexes file content rather than gathering statistics.
+var retrievalSkipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, ".gocode": true,
+	"__pycache__": true, ".venv": true, "venv": true, "dist": true,
+	"build": true, "target": true, ".next": true, ".nuxt": true,
+}
+
+// retrievalExtensions lists the file extensions worth indexing for hybrid
+// search - source and doc files, not binaries, images, or lockfiles.
+var retrievalExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".ts": true, ".tsx": true, ".jsx": true,
+	".java": true, ".c": true, ".h": true, ".cpp": true, ".hpp": true, ".rs": true,
+	".rb": true, ".php": true, ".cs": true, ".md": true, ".yaml": true, ".yml": true,
+	".json": true, ".sh": true, ".sql": true,
+}
+
+// maxRetrievalFileBytes skips indexing files larger than this, so one huge
+// generated or vendored file doesn't dominate the index or the walk time.
+const maxRetrievalFileBytes = 256 * 1024
+
+// buildRetriever walks the workspace and indexes source files into a new
+// HybridRetriever for per-turn context injection. Semantic search is left
+// disabled (embeddingsMgr nil): the embeddings vector store is not wired
+// into this search path (see /stats).
+func buildRetriever(cfg *config.Config, weights retrieval.FusionWeights) (*retrieval.HybridRetriever, int, error) {
+	hr := retrieval.NewHybridRetriever(weights, nil)
+	ignorePatterns := gitignore.LoadPatterns(cfg.WorkingDir)
+
+	err := filepath.Walk(cfg.WorkingDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries, keep walking
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if name != "." && (retrievalSkipDirs[name] || gitignore.IsIgnored(name, ignorePatterns)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !retrievalExtensions[filepath.Ext(name)] || info.Size() > maxRetrievalFileBytes || gitignore.IsIgnored(name, ignorePatterns) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(cfg.WorkingDir, path)
+		if err != nil {
+			relPath = path
+		}
+
+		_ = hr.AddDocument(context.Background(), retrieval.Document{
+			ID:       relPath,
+			Content:  string(data),
+			FilePath: relPath,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return hr, hr.Count(), nil
+}
+
+// retrieveContext runs a hybrid search for input, reranks the fused
+// results, and returns the raw chunk text ready for context injection.
+func (a *Agent) retrieveContext(input string) []string {
+	const topK = 5
+
+	results, err := a.retriever.Search(context.Background(), input, topK)
+	if err != nil || len(results) == 0 {
+		return nil
+	}
+
+	results = a.reranker.Rerank(results, input, topK)
+
+	chunks := make([]string, 0, len(results))
+	for _, r := range results {
+		chunks = append(chunks, fmt.Sprintf("# %s\n%s", r.Document.FilePath, r.Document.Content))
+	}
+	return chunks
+}
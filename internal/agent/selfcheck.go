@@ -3,14 +3,17 @@ package agent
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/jake/gocode/internal/llm"
+	"github.com/jake/gocode/internal/memory"
 )
 
 // SelfCheckSystem validates agent's claims before task completion
 type SelfCheckSystem struct {
 	bashTool ToolExecutor
+	memory   *memory.LongTermMemory // nil unless memory.enabled - records/looks up flaky test names
 }
 
 // ToolExecutor interface for executing bash commands
@@ -25,12 +28,20 @@ func NewSelfCheckSystem(bashTool ToolExecutor) *SelfCheckSystem {
 	}
 }
 
+// SetMemory wires up long-term memory once it's constructed, since it's
+// initialized after the self-check system during agent startup. A nil ltm
+// (memory disabled or failed to open) just leaves flaky-test tracking off.
+func (s *SelfCheckSystem) SetMemory(ltm *memory.LongTermMemory) {
+	s.memory = ltm
+}
+
 // CompletionClaim represents a claim made by the agent
 type CompletionClaim struct {
 	ClaimType string // "tests_passed", "build_success", "task_complete"
 	Content   string // The actual claim text
 	Verified  bool
 	Evidence  string // Evidence from actual execution
+	Flaky     bool   // Verified became true despite a failing run: every failing test was already known-flaky, or an immediate re-run passed
 }
 
 // DetectCompletionClaims analyzes agent response for completion claims
@@ -117,6 +128,10 @@ func (s *SelfCheckSystem) VerifyClaims(ctx context.Context, claims []CompletionC
 
 				// Check if tests actually passed
 				claim.Verified = s.checkTestSuccess(result, err)
+
+				if !claim.Verified {
+					claim = s.recheckFlaky(ctx, claim, testCmd, result)
+				}
 			}
 		case "build_success":
 			// Try to detect and run build command
@@ -139,6 +154,99 @@ func (s *SelfCheckSystem) VerifyClaims(ctx context.Context, claims []CompletionC
 	return verified, nil
 }
 
+// recheckFlaky handles a failing test claim by first checking whether every
+// failing test named in the output is already a known flaky test (in which
+// case it re-reports success without spending a rerun), and otherwise
+// re-running the same command once - if that rerun passes, the failure
+// didn't reproduce, so the tests are reported as flaky (not broken) and the
+// failing names are recorded to memory so future runs can skip straight to
+// this same shortcut.
+func (s *SelfCheckSystem) recheckFlaky(ctx context.Context, claim CompletionClaim, testCmd, firstResult string) CompletionClaim {
+	failed := extractFailedTestNames(testCmd, firstResult)
+
+	if len(failed) > 0 && s.allKnownFlaky(failed) {
+		claim.Verified = true
+		claim.Flaky = true
+		claim.Evidence += fmt.Sprintf("\n\n(Every failing test above is already known-flaky: %s - not re-run.)", strings.Join(failed, ", "))
+		return claim
+	}
+
+	rerunResult, err := s.bashTool.Execute(ctx, "bash", fmt.Sprintf(`{"command":"%s"}`, testCmd))
+	if !s.checkTestSuccess(rerunResult, err) {
+		// Failed twice in a row - a genuine failure, not flakiness.
+		return claim
+	}
+
+	claim.Verified = true
+	claim.Flaky = true
+	claim.Evidence += "\n\n(Failed once, then passed on an immediate re-run - reporting as flaky, not broken.)"
+	s.recordFlaky(failed)
+	return claim
+}
+
+// allKnownFlaky reports whether every name in names has a recorded
+// "flaky-test" memory, so recheckFlaky can skip the rerun entirely.
+func (s *SelfCheckSystem) allKnownFlaky(names []string) bool {
+	if s.memory == nil {
+		return false
+	}
+	for _, name := range names {
+		matches, err := s.memory.GetByTags([]string{"flaky-test:" + name}, 1)
+		if err != nil || len(matches) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// recordFlaky stores each newly-confirmed flaky test name as its own
+// memory entry, tagged for allKnownFlaky's lookup.
+func (s *SelfCheckSystem) recordFlaky(names []string) {
+	if s.memory == nil {
+		return
+	}
+	for _, name := range names {
+		_ = s.memory.Store(&memory.Memory{
+			Type:       memory.TypePattern,
+			Content:    fmt.Sprintf("Test %q failed once then passed on immediate re-run - flaky, not a real regression.", name),
+			Summary:    "flaky test: " + name,
+			Tags:       []string{"flaky-test", "flaky-test:" + name},
+			Importance: 0.3,
+			Author:     memory.CurrentUser(),
+		})
+	}
+}
+
+// goTestFailurePattern and pytestFailurePattern extract the names of
+// individually failing tests from the two test runners detectTestCommand
+// recognizes as producing per-test failure lines. Runners without a
+// recognized format simply yield no names, so flaky tracking degrades to
+// "always re-run once" for them rather than erroring.
+var (
+	goTestFailurePattern = regexp.MustCompile(`(?m)^--- FAIL: (\S+)`)
+	pytestFailurePattern = regexp.MustCompile(`(?m)^FAILED (\S+)`)
+)
+
+// extractFailedTestNames pulls individual failing test names out of output,
+// using the format the given test command is expected to produce.
+func extractFailedTestNames(testCmd, output string) []string {
+	var pattern *regexp.Regexp
+	switch {
+	case strings.Contains(testCmd, "go test"):
+		pattern = goTestFailurePattern
+	case strings.Contains(testCmd, "pytest"):
+		pattern = pytestFailurePattern
+	default:
+		return nil
+	}
+
+	var names []string
+	for _, match := range pattern.FindAllStringSubmatch(output, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
 // detectTestCommand tries to determine the appropriate test command
 func (s *SelfCheckSystem) detectTestCommand(projectContext string) string {
 	lowerContext := strings.ToLower(projectContext)
@@ -274,9 +382,12 @@ func (s *SelfCheckSystem) GenerateFeedbackMessage(claims []CompletionClaim) stri
 	hasFailures := false
 
 	for _, claim := range claims {
-		if claim.Verified {
+		switch {
+		case claim.Flaky:
+			parts = append(parts, fmt.Sprintf("🎲 %s: **VERIFIED (flaky - failed once, then known-flaky or passed on re-run)**", claim.ClaimType))
+		case claim.Verified:
 			parts = append(parts, fmt.Sprintf("✅ %s: **VERIFIED**", claim.ClaimType))
-		} else {
+		default:
 			hasFailures = true
 			parts = append(parts, fmt.Sprintf("❌ %s: **NOT VERIFIED**", claim.ClaimType))
 			if claim.Evidence != "" {
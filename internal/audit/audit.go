@@ -0,0 +1,191 @@
+// Package audit provides an append-only, hash-chained record of every tool
+// call gocode executes - who ran it, when, and whether it was approved -
+// kept separate from the debug logs in internal/logging so it can be
+// retained and inspected independently of normal log rotation.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entry is one hash-chained record of a tool invocation. Args and results
+// are stored as hashes rather than raw content, so the log stays small and
+// doesn't duplicate potentially sensitive tool output in a second place -
+// an investigator with the original args/result can still confirm a match
+// by hashing them the same way.
+type Entry struct {
+	Seq          int       `json:"seq"`
+	Timestamp    time.Time `json:"timestamp"`
+	Actor        string    `json:"actor"`
+	ToolName     string    `json:"tool_name"`
+	ArgsHash     string    `json:"args_hash"`
+	ResultHash   string    `json:"result_hash"`
+	Approved     bool      `json:"approved"`
+	ApprovalMode string    `json:"approval_mode"` // e.g. "auto-approved", "user-approved", "user-edited", "shell-escape"
+	Error        string    `json:"error,omitempty"`
+	PrevHash     string    `json:"prev_hash"`
+	Hash         string    `json:"hash"`
+}
+
+// computeHash commits to every field except Hash itself, chained onto
+// PrevHash so tampering with an earlier entry invalidates every hash after
+// it.
+func (e Entry) computeHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%s|%s|%s|%s|%s|%t|%s|%s|%s",
+		e.Seq, e.Timestamp.Format(time.RFC3339Nano), e.Actor, e.ToolName,
+		e.ArgsHash, e.ResultHash, e.Approved, e.ApprovalMode, e.Error, e.PrevHash)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Log is the append-only audit file for one gocode installation. Multiple
+// sessions append to the same file across time, each continuing the prior
+// session's hash chain and sequence number.
+type Log struct {
+	file     *os.File
+	lastHash string
+	seq      int
+}
+
+// Open appends to (or creates) the audit log at path, replaying it first to
+// recover the running hash chain and sequence number left by prior
+// sessions.
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	lastHash, seq, err := tailChainState(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	return &Log{file: file, lastHash: lastHash, seq: seq}, nil
+}
+
+// tailChainState reads the last entry (if any) of an existing audit log to
+// recover the hash chain and sequence number, so new entries continue the
+// same chain instead of starting a fresh one.
+func tailChainState(path string) (string, int, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	var last Entry
+	found := false
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(line), &last); err != nil {
+			return "", 0, fmt.Errorf("failed to parse audit log: %w", err)
+		}
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return "", 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if !found {
+		return "", 0, nil
+	}
+	return last.Hash, last.Seq, nil
+}
+
+// Record appends one hash-chained entry for a completed tool call.
+func (l *Log) Record(actor, toolName, args, result string, execErr error, approved bool, mode string) error {
+	l.seq++
+	entry := Entry{
+		Seq:          l.seq,
+		Timestamp:    time.Now(),
+		Actor:        actor,
+		ToolName:     toolName,
+		ArgsHash:     hashString(args),
+		ResultHash:   hashString(result),
+		Approved:     approved,
+		ApprovalMode: mode,
+		PrevHash:     l.lastHash,
+	}
+	if execErr != nil {
+		entry.Error = execErr.Error()
+	}
+	entry.Hash = entry.computeHash()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	l.lastHash = entry.Hash
+	return nil
+}
+
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+// Verify replays the audit log at path, recomputing and checking each
+// entry's hash chain, and returns the number of valid entries found. It
+// returns an error identifying the first broken link if the log has been
+// tampered with (an edited field, a deleted or reordered entry).
+func Verify(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	prevHash := ""
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return count, fmt.Errorf("entry %d: invalid JSON: %w", count+1, err)
+		}
+		if entry.PrevHash != prevHash {
+			return count, fmt.Errorf("entry %d (seq %d): prev_hash does not match preceding entry - chain broken", count+1, entry.Seq)
+		}
+		if entry.Hash != entry.computeHash() {
+			return count, fmt.Errorf("entry %d (seq %d): hash does not match its contents - entry was tampered with", count+1, entry.Seq)
+		}
+		prevHash = entry.Hash
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,259 @@
+package checkpoint
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jake/gocode/internal/llm"
+)
+
+// exportManifest describes the contents of a checkpoint archive so Import
+// can reconstruct a thread without relying on the source machine's IDs.
+type exportManifest struct {
+	Thread      *Thread       `json:"thread"`
+	Checkpoints []*Checkpoint `json:"checkpoints"`
+}
+
+// artifactsEntryPrefix namespaces bundled tool-output artifacts within the
+// archive, keeping them separate from manifest.json.
+const artifactsEntryPrefix = "artifacts/"
+
+// artifactRefPattern matches the "Full output saved to <path>" notice
+// (*tools.Registry).truncate appends to an oversized tool result, so Export
+// can find every artifact a checkpoint's messages reference.
+var artifactRefPattern = regexp.MustCompile(`Full output saved to (\S+)`)
+
+// referencedArtifacts returns the deduplicated, in-order set of artifact
+// paths mentioned across checkpoints' messages via the "Full output saved
+// to" notice.
+func referencedArtifacts(checkpoints []*Checkpoint) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, cp := range checkpoints {
+		for _, msg := range cp.Messages {
+			for _, match := range artifactRefPattern.FindAllStringSubmatch(msg.Content, -1) {
+				path := match[1]
+				if !seen[path] {
+					seen[path] = true
+					paths = append(paths, path)
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// Export bundles a thread and all of its checkpoints, plus any tool-output
+// artifacts their messages reference (see referencedArtifacts), into a
+// single gzip-compressed tar archive at destPath, so it can be copied to
+// another machine and picked up with Import. An artifact that's since been
+// cleaned up or moved is skipped rather than failing the whole export - the
+// manifest is what matters most, and Import degrades gracefully to a
+// dangling reference for anything that couldn't be bundled.
+func (m *Manager) Export(threadID string, destPath string) error {
+	thread, err := m.store.GetThread(threadID)
+	if err != nil {
+		return err
+	}
+
+	checkpoints, err := m.store.GetThreadCheckpoints(threadID)
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	manifest := exportManifest{Thread: thread, Checkpoints: checkpoints}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	defer gw.Close()
+
+	tw := tar.NewWriter(gw)
+	defer tw.Close()
+
+	hdr := &tar.Header{
+		Name: "manifest.json",
+		Mode: 0644,
+		Size: int64(len(data)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write archive header: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	for _, path := range referencedArtifacts(checkpoints) {
+		artifact, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		hdr := &tar.Header{
+			Name: artifactsEntryPrefix + filepath.Base(path),
+			Mode: 0644,
+			Size: int64(len(artifact)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write archive header for artifact %s: %w", path, err)
+		}
+		if _, err := tw.Write(artifact); err != nil {
+			return fmt.Errorf("failed to write artifact %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// Import loads a thread and its checkpoints from an archive produced by
+// Export, assigning fresh IDs so it doesn't collide with existing threads.
+// Bundled artifacts are restored under m.artifactsDir (if configured) and
+// checkpoint messages are rewritten to point at their new location, so a
+// "Full output saved to <path>" reference still resolves on this machine.
+func (m *Manager) Import(srcPath string) (*Thread, error) {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	var manifest *exportManifest
+	artifacts := make(map[string][]byte) // basename -> content
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive entry: %w", err)
+		}
+
+		switch {
+		case hdr.Name == "manifest.json":
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read manifest: %w", err)
+			}
+			manifest = &exportManifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+		case strings.HasPrefix(hdr.Name, artifactsEntryPrefix):
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read artifact %s: %w", hdr.Name, err)
+			}
+			artifacts[strings.TrimPrefix(hdr.Name, artifactsEntryPrefix)] = data
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("archive does not contain a manifest")
+	}
+
+	pathRemap, err := m.restoreArtifacts(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	newThread, err := m.store.CreateThread(manifest.Thread.Name, manifest.Thread.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create imported thread: %w", err)
+	}
+
+	// The manifest lists checkpoints newest-first (GetThreadCheckpoints'
+	// order), so walk it backwards to import oldest-first and let parent
+	// links resolve as we go.
+	idRemap := make(map[string]string, len(manifest.Checkpoints))
+	for i := len(manifest.Checkpoints) - 1; i >= 0; i-- {
+		src := manifest.Checkpoints[i]
+		cp := &Checkpoint{
+			ID:          generateID(),
+			ThreadID:    newThread.ID,
+			ParentID:    idRemap[src.ParentID],
+			Timestamp:   src.Timestamp,
+			Messages:    remapArtifactPaths(src.Messages, pathRemap),
+			Metadata:    src.Metadata,
+			Description: src.Description,
+		}
+		idRemap[src.ID] = cp.ID
+		if err := m.store.SaveCheckpoint(cp); err != nil {
+			return nil, fmt.Errorf("failed to import checkpoint %s: %w", src.ID, err)
+		}
+	}
+
+	m.currentThread = newThread
+	return newThread, nil
+}
+
+// restoreArtifacts writes each bundled artifact under m.artifactsDir,
+// returning a basename -> new-path map for remapArtifactPaths. Bundled
+// artifacts are dropped (not an error) if no artifacts directory is
+// configured, matching (*tools.Registry).saveArtifact treating "" as
+// "disabled" rather than a misconfiguration.
+func (m *Manager) restoreArtifacts(artifacts map[string][]byte) (map[string]string, error) {
+	if len(artifacts) == 0 || m.artifactsDir == "" {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(m.artifactsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	pathRemap := make(map[string]string, len(artifacts))
+	for name, data := range artifacts {
+		newPath := filepath.Join(m.artifactsDir, name)
+		if err := os.WriteFile(newPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to restore artifact %s: %w", name, err)
+		}
+		pathRemap[name] = newPath
+	}
+	return pathRemap, nil
+}
+
+// remapArtifactPaths rewrites each message's "Full output saved to <path>"
+// reference to the artifact's restored location, keyed by pathRemap's
+// basename. A reference whose artifact wasn't bundled (or couldn't be
+// restored) is left as-is, since that dangling path is still meaningful
+// context for a human reading the imported thread.
+func remapArtifactPaths(messages []llm.Message, pathRemap map[string]string) []llm.Message {
+	if len(pathRemap) == 0 {
+		return messages
+	}
+
+	out := make([]llm.Message, len(messages))
+	for i, msg := range messages {
+		out[i] = msg
+		out[i].Content = artifactRefPattern.ReplaceAllStringFunc(msg.Content, func(full string) string {
+			match := artifactRefPattern.FindStringSubmatch(full)
+			newPath, ok := pathRemap[filepath.Base(match[1])]
+			if !ok {
+				return full
+			}
+			return "Full output saved to " + newPath
+		})
+	}
+	return out
+}
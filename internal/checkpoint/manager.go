@@ -14,13 +14,15 @@ type Manager struct {
 	autoSave      bool
 	saveInterval  int // Save every N messages
 	messageCount  int
+	artifactsDir  string // where Export/Import read/write referenced tool-output artifacts; "" disables bundling them
 }
 
 // Config holds configuration for the checkpoint manager
 type Config struct {
 	DBPath       string
 	AutoSave     bool
-	SaveInterval int // Auto-save every N messages (0 = manual only)
+	SaveInterval int    // Auto-save every N messages (0 = manual only)
+	ArtifactsDir string // tools.Registry's artifacts directory, so Export can bundle referenced output alongside the manifest; "" disables it
 }
 
 // DefaultConfig returns default configuration
@@ -44,6 +46,7 @@ func NewManager(config Config) (*Manager, error) {
 		autoSave:     config.AutoSave,
 		saveInterval: config.SaveInterval,
 		messageCount: 0,
+		artifactsDir: config.ArtifactsDir,
 	}, nil
 }
 
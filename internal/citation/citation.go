@@ -0,0 +1,47 @@
+// Package citation defines the canonical "path:startLine-endLine" format
+// used to reference a location in a file, so every tool and prompt that
+// points the model (or a human) at a piece of code produces something
+// downstream tooling - editor jump-to, HTML export links - can parse the
+// same way.
+package citation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Format renders a location as its canonical citation string. Line numbers
+// are printed exactly as given, with no index conversion; pass the
+// 1-indexed line number the tool already reports to users. A single-line
+// span (startLine == endLine) is rendered without the range, e.g. "a.go:12"
+// rather than "a.go:12-12".
+func Format(path string, startLine, endLine int) string {
+	if startLine == endLine {
+		return fmt.Sprintf("%s:%d", path, startLine)
+	}
+	return fmt.Sprintf("%s:%d-%d", path, startLine, endLine)
+}
+
+var pattern = regexp.MustCompile(`^(.+):(\d+)(?:-(\d+))?$`)
+
+// Parse reverses Format, so a citation printed by one tool can be resolved
+// back to a location by another. ok is false if s isn't a citation.
+func Parse(s string) (path string, startLine, endLine int, ok bool) {
+	m := pattern.FindStringSubmatch(s)
+	if m == nil {
+		return "", 0, 0, false
+	}
+	startLine, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, 0, false
+	}
+	endLine = startLine
+	if m[3] != "" {
+		endLine, err = strconv.Atoi(m[3])
+		if err != nil {
+			return "", 0, 0, false
+		}
+	}
+	return m[1], startLine, endLine, true
+}
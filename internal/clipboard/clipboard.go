@@ -0,0 +1,51 @@
+// Package clipboard writes text to the system clipboard by shelling out to
+// whatever clipboard utility ships with (or is commonly installed on) the
+// platform, so /copy works without pulling in a cross-platform clipboard
+// library or its cgo/X11 dependencies.
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Write places text on the system clipboard.
+func Write(text string) error {
+	cmd, err := copyCommand()
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdin = bytes.NewBufferString(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("clipboard write failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// copyCommand picks the platform's clipboard-writing command. Linux has no
+// single standard clipboard tool, so it tries xclip first and falls back to
+// xsel/wl-copy if xclip isn't on PATH.
+func copyCommand() (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("pbcopy"), nil
+	case "windows":
+		return exec.Command("clip"), nil
+	case "linux":
+		for _, candidate := range [][]string{
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+			{"wl-copy"},
+		} {
+			if _, err := exec.LookPath(candidate[0]); err == nil {
+				return exec.Command(candidate[0], candidate[1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("no clipboard utility found (install xclip, xsel, or wl-copy)")
+	default:
+		return nil, fmt.Errorf("clipboard not supported on %s", runtime.GOOS)
+	}
+}
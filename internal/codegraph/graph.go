@@ -5,80 +5,90 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/jake/gocode/internal/gitignore"
+	"github.com/jake/gocode/internal/langdetect"
 	"github.com/jake/gocode/internal/lsp"
 	"github.com/jake/gocode/internal/parser"
 )
 
+// indexWorkspaceWorkers bounds how many files IndexWorkspace indexes
+// concurrently, mirroring embeddings.Manager's IndexDirectory worker pool.
+const indexWorkspaceWorkers = 4
+
 // Graph represents a code graph with symbols, definitions, references, and calls
 type Graph struct {
 	rootPath string
 	lspMgr   *lsp.Manager
 
 	// Graph data
-	symbols     map[string]*SymbolNode      // symbol ID -> node
-	files       map[string]*FileNode        // file path -> file node
-	edges       map[string][]*Edge          // source symbol ID -> edges
-	mu          sync.RWMutex
+	symbols map[string]*SymbolNode // symbol ID -> node
+	files   map[string]*FileNode   // file path -> file node
+	edges   map[string][]*Edge     // source symbol ID -> edges
+	mu      sync.RWMutex
 
 	// Cache
-	cacheValid  map[string]bool             // file path -> is cache valid
-	cacheMu     sync.RWMutex
+	cacheValid map[string]bool // file path -> is cache valid
+	cacheMu    sync.RWMutex
 }
 
 // SymbolNode represents a symbol in the code graph
 type SymbolNode struct {
-	ID          string           // Unique identifier
-	Name        string           // Symbol name
-	Kind        string           // function, class, variable, etc.
-	FilePath    string           // File containing the symbol
-	Line        int              // Line number
-	Column      int              // Column number
-	Signature   string           // Full signature/declaration
-	DocString   string           // Documentation
-	ParentID    string           // Parent symbol (for methods in classes, etc.)
+	ID        string // Unique identifier
+	Name      string // Symbol name
+	Kind      string // function, class, variable, etc.
+	FilePath  string // File containing the symbol
+	Line      int    // Line number
+	Column    int    // Column number
+	Signature string // Full signature/declaration
+	DocString string // Documentation
+	ParentID  string // Parent symbol (for methods in classes, etc.)
 }
 
 // FileNode represents a file in the code graph
 type FileNode struct {
 	Path         string
 	Language     string
-	SymbolIDs    []string         // Symbols defined in this file
-	Imports      []string         // Imported packages/modules
-	LastModified int64            // Unix timestamp
+	SymbolIDs    []string // Symbols defined in this file
+	Imports      []string // Imported packages/modules
+	LastModified int64    // Unix timestamp
 }
 
 // Edge represents a relationship between symbols
 type Edge struct {
-	From     string     // Source symbol ID
-	To       string     // Target symbol ID
-	Type     EdgeType   // Type of relationship
-	FilePath string     // File where this edge occurs
-	Line     int        // Line where this edge occurs
+	From     string   // Source symbol ID
+	To       string   // Target symbol ID
+	Type     EdgeType // Type of relationship
+	FilePath string   // File where this edge occurs
+	Line     int      // Line where this edge occurs
 }
 
 // EdgeType represents the type of relationship
 type EdgeType string
 
 const (
-	EdgeDefinition   EdgeType = "definition"   // A defines B
-	EdgeReference    EdgeType = "reference"    // A references B
-	EdgeCall         EdgeType = "call"         // A calls B
-	EdgeInherits     EdgeType = "inherits"     // A inherits from B
-	EdgeImplements   EdgeType = "implements"   // A implements B
-	EdgeImports      EdgeType = "imports"      // A imports B
+	EdgeDefinition EdgeType = "definition" // A defines B
+	EdgeReference  EdgeType = "reference"  // A references B
+	EdgeCall       EdgeType = "call"       // A calls B
+	EdgeInherits   EdgeType = "inherits"   // A inherits from B
+	EdgeImplements EdgeType = "implements" // A implements B
+	EdgeImports    EdgeType = "imports"    // A imports B
 )
 
 // NewGraph creates a new code graph
 func NewGraph(rootPath string, lspMgr *lsp.Manager) *Graph {
 	return &Graph{
-		rootPath:    rootPath,
-		lspMgr:      lspMgr,
-		symbols:     make(map[string]*SymbolNode),
-		files:       make(map[string]*FileNode),
-		edges:       make(map[string][]*Edge),
-		cacheValid:  make(map[string]bool),
+		rootPath:   rootPath,
+		lspMgr:     lspMgr,
+		symbols:    make(map[string]*SymbolNode),
+		files:      make(map[string]*FileNode),
+		edges:      make(map[string][]*Edge),
+		cacheValid: make(map[string]bool),
 	}
 }
 
@@ -123,6 +133,93 @@ func (g *Graph) IndexFile(ctx context.Context, filePath string) error {
 	return nil
 }
 
+// IndexWorkspace walks the whole project under rootPath (skipping .gitignore
+// matches and the usual noise directories) and indexes every recognized
+// source file, so tools like list_symbols and graph_query work across the
+// project instead of only over files opened so far. Files are indexed
+// concurrently by a small worker pool; progress, if non-nil, is called after
+// each file completes with the running count, the total, and an ETA based
+// on the average time per file so far.
+func (g *Graph) IndexWorkspace(ctx context.Context, progress func(done, total int, eta time.Duration)) error {
+	skipDirs := map[string]bool{
+		".git": true, "node_modules": true, "vendor": true, ".gocode": true, "logs": true,
+	}
+	ignorePatterns := gitignore.LoadPatterns(g.rootPath)
+
+	var paths []string
+	err := filepath.Walk(g.rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if skipDirs[name] || gitignore.IsIgnored(name, ignorePatterns) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if gitignore.IsIgnored(name, ignorePatterns) {
+			return nil
+		}
+		if langdetect.DetectFile(path) == "unknown" {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk workspace: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	var (
+		mu        sync.Mutex
+		completed int
+		firstErr  error
+		start     = time.Now()
+	)
+	jobs := make(chan string)
+
+	var wg sync.WaitGroup
+	for w := 0; w < indexWorkspaceWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				indexErr := g.IndexFile(ctx, path)
+
+				mu.Lock()
+				if indexErr != nil && firstErr == nil {
+					firstErr = fmt.Errorf("failed to index %s: %w", path, indexErr)
+				}
+				completed++
+				elapsed := time.Since(start)
+				eta := time.Duration(float64(elapsed) / float64(completed) * float64(len(paths)-completed))
+				if progress != nil {
+					progress(completed, len(paths), eta)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, path := range paths {
+		select {
+		case jobs <- path:
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			return ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
+
 // indexWithLSP indexes a file using LSP
 func (g *Graph) indexWithLSP(ctx context.Context, filePath string, fileNode *FileNode) error {
 	docSymbols, symInfo, err := g.lspMgr.GetDocumentSymbols(ctx, filePath)
@@ -167,12 +264,31 @@ func (g *Graph) processDocumentSymbol(filePath string, docSym *lsp.DocumentSymbo
 		fileNode.SymbolIDs = append(fileNode.SymbolIDs, symbolID)
 	}
 
+	// Record the containment relationship (method -> class, etc.) as a
+	// definition edge so TraverseFrom/graph export has something to walk;
+	// LSP is the only indexing path with real parent/child structure today.
+	if parentID != "" {
+		g.addEdge(parentID, symbolID, EdgeDefinition, filePath, docSym.Range.Start.Line)
+	}
+
 	// Process children recursively
 	for i := range docSym.Children {
 		g.processDocumentSymbol(filePath, &docSym.Children[i], symbolID)
 	}
 }
 
+// addEdge records a directed relationship between two symbols. Callers must
+// hold g.mu (all current call sites are already inside a locked section).
+func (g *Graph) addEdge(from, to string, edgeType EdgeType, filePath string, line int) {
+	g.edges[from] = append(g.edges[from], &Edge{
+		From:     from,
+		To:       to,
+		Type:     edgeType,
+		FilePath: filePath,
+		Line:     line,
+	})
+}
+
 // processSymbolInformation processes a flat symbol
 func (g *Graph) processSymbolInformation(filePath string, sym *lsp.SymbolInformation) {
 	symbolID := fmt.Sprintf("%s:%d:%d:%s", filePath, sym.Location.Range.Start.Line, sym.Location.Range.Start.Character, sym.Name)
@@ -200,8 +316,10 @@ func (g *Graph) indexWithParser(filePath string, fileNode *FileNode) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Determine language from extension
-	language := getLanguageFromPath(filePath)
+	// Determine language from extension, falling back to filename/shebang
+	// detection for extensionless scripts (Dockerfile, Makefile, ...).
+	language := langdetect.Detect(filePath, string(content))
+	fileNode.Language = language
 	p := parser.NewSimpleParser(language)
 
 	symbols := p.Parse(string(content))
@@ -224,9 +342,83 @@ func (g *Graph) indexWithParser(filePath string, fileNode *FileNode) error {
 		fileNode.SymbolIDs = append(fileNode.SymbolIDs, symbolID)
 	}
 
+	g.buildReferenceEdges(filePath, string(content), fileNode)
+
 	return nil
 }
 
+// identifierPattern matches a single identifier token, for
+// buildReferenceEdges' line-by-line scan.
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// buildReferenceEdges scans a parser-indexed file's content for occurrences
+// of every symbol name known to the graph so far and records them as
+// EdgeReference edges (EdgeCall when the occurrence is immediately followed
+// by "(") from the enclosing symbol to the referenced one. This is
+// identifier-occurrence matching against the flat symbol table, not real
+// semantic resolution - two unrelated symbols sharing a name will collide -
+// but it's enough to populate find_references/TraverseFrom in fallback mode,
+// where LSP isn't available to do it properly.
+func (g *Graph) buildReferenceEdges(filePath, content string, fileNode *FileNode) {
+	byName := make(map[string][]string) // symbol name -> IDs, across the whole graph
+	for id, sym := range g.symbols {
+		byName[sym.Name] = append(byName[sym.Name], id)
+	}
+	if len(byName) == 0 {
+		return
+	}
+
+	fileSymbols := make([]*SymbolNode, 0, len(fileNode.SymbolIDs))
+	for _, id := range fileNode.SymbolIDs {
+		if sym, ok := g.symbols[id]; ok {
+			fileSymbols = append(fileSymbols, sym)
+		}
+	}
+	sort.Slice(fileSymbols, func(i, j int) bool { return fileSymbols[i].Line < fileSymbols[j].Line })
+
+	lines := strings.Split(content, "\n")
+	for lineNum, line := range lines {
+		// Parser symbol lines are 1-based; lineNum is a 0-based index.
+		enclosing := enclosingSymbolID(fileSymbols, lineNum+1)
+		if enclosing == "" {
+			continue
+		}
+		for _, match := range identifierPattern.FindAllStringIndex(line, -1) {
+			name := line[match[0]:match[1]]
+			ids, ok := byName[name]
+			if !ok {
+				continue
+			}
+			isCall := match[1] < len(line) && line[match[1]] == '('
+			for _, id := range ids {
+				if id == enclosing {
+					continue // the symbol's own declaration, not a reference to it
+				}
+				edgeType := EdgeReference
+				if isCall {
+					edgeType = EdgeCall
+				}
+				g.addEdge(enclosing, id, edgeType, filePath, lineNum)
+			}
+		}
+	}
+}
+
+// enclosingSymbolID returns the ID of the last symbol (sorted by line)
+// starting at or before lineNum, approximating "which function/method is
+// this line inside" without full scope tracking - the simple parser
+// exposes only a symbol's start line, not its end.
+func enclosingSymbolID(sorted []*SymbolNode, lineNum int) string {
+	id := ""
+	for _, sym := range sorted {
+		if sym.Line > lineNum {
+			break
+		}
+		id = sym.ID
+	}
+	return id
+}
+
 // FindDefinitions finds definitions of a symbol at a location
 func (g *Graph) FindDefinitions(ctx context.Context, filePath string, line, column int) ([]*SymbolNode, error) {
 	if g.lspMgr != nil {
@@ -253,6 +445,14 @@ func (g *Graph) FindReferences(ctx context.Context, filePath string, line, colum
 	return g.findReferencesInGraph(filePath, line, column), nil
 }
 
+// SymbolCount returns the total number of symbols currently indexed, for
+// diagnostics/stats reporting.
+func (g *Graph) SymbolCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.symbols)
+}
+
 // GetSymbolsByFile returns all symbols in a file
 func (g *Graph) GetSymbolsByFile(filePath string) []*SymbolNode {
 	g.mu.RLock()
@@ -273,6 +473,56 @@ func (g *Graph) GetSymbolsByFile(filePath string) []*SymbolNode {
 	return symbols
 }
 
+// SymbolsByKind returns all indexed symbols matching one of the given kinds
+// (e.g. "class", "interface", "struct"), for callers that want a "key types"
+// overview without walking every file individually.
+func (g *Graph) SymbolsByKind(kinds ...string) []*SymbolNode {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	want := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		want[k] = true
+	}
+
+	result := make([]*SymbolNode, 0)
+	for _, sym := range g.symbols {
+		if want[sym.Kind] {
+			result = append(result, sym)
+		}
+	}
+	return result
+}
+
+// FindByName returns every indexed symbol with an exact name match, across
+// all files. There is no uniqueness guarantee (e.g. overloaded methods,
+// same-named types in different packages), so callers that need a single
+// symbol should disambiguate by FilePath themselves.
+func (g *Graph) FindByName(name string) []*SymbolNode {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := make([]*SymbolNode, 0)
+	for _, sym := range g.symbols {
+		if sym.Name == name {
+			result = append(result, sym)
+		}
+	}
+	return result
+}
+
+// LookupName returns the human-readable name of a symbol ID, or the ID
+// itself if it isn't indexed, for callers rendering edges without wanting
+// to carry SymbolNode pointers around.
+func (g *Graph) LookupName(symbolID string) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if sym, ok := g.symbols[symbolID]; ok {
+		return sym.Name
+	}
+	return symbolID
+}
+
 // TraverseFrom performs graph traversal from a symbol
 func (g *Graph) TraverseFrom(symbolID string, edgeType EdgeType, maxDepth int) []*SymbolNode {
 	g.mu.RLock()
@@ -405,24 +655,36 @@ func (g *Graph) findDefinitionsInGraph(filePath string, line, column int) []*Sym
 	return []*SymbolNode{}
 }
 
+// findReferencesInGraph looks up the symbol at filePath:line and returns
+// every symbol whose edges (built by buildReferenceEdges during fallback
+// parser indexing) reference it.
 func (g *Graph) findReferencesInGraph(filePath string, line, column int) []*SymbolNode {
-	// Simple implementation - return empty for now
-	// Could be enhanced to track references in the graph
-	return []*SymbolNode{}
+	symbolID := g.findSymbolIDAtLocation(filePath, line, column)
+	if symbolID == "" {
+		return []*SymbolNode{}
+	}
+
+	seen := make(map[string]bool)
+	var refs []*SymbolNode
+	for from, edges := range g.edges {
+		for _, edge := range edges {
+			if edge.To != symbolID || seen[from] {
+				continue
+			}
+			if sym, ok := g.symbols[from]; ok {
+				refs = append(refs, sym)
+				seen[from] = true
+			}
+		}
+	}
+	return refs
 }
 
+// getLanguageFromPath guesses a file's language before its content has been
+// read (e.g. when first creating its FileNode). indexWithParser refines
+// this afterwards with langdetect.Detect, which also sees shebang lines.
 func getLanguageFromPath(filePath string) string {
-	ext := filepath.Ext(filePath)
-	switch ext {
-	case ".go":
-		return "go"
-	case ".py":
-		return "python"
-	case ".js", ".ts", ".jsx", ".tsx":
-		return "javascript"
-	default:
-		return "unknown"
-	}
+	return langdetect.DetectFile(filePath)
 }
 
 func symbolKindToString(kind lsp.SymbolKind) string {
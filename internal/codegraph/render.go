@@ -0,0 +1,93 @@
+package codegraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SubgraphEdges returns every recorded edge whose endpoints are both in
+// nodes, so a caller that already picked a set of symbols (e.g. via
+// TraverseFrom) can render just the connections between them.
+func (g *Graph) SubgraphEdges(nodes []*SymbolNode) []*Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	in := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		in[n.ID] = true
+	}
+
+	edges := make([]*Edge, 0)
+	for from, out := range g.edges {
+		if !in[from] {
+			continue
+		}
+		for _, e := range out {
+			if in[e.To] {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+// nodeLabel renders a short, diagram-friendly label for a symbol: its name
+// plus kind, since the full ID (file:line:col:name) is too noisy to read on
+// a graph node.
+func nodeLabel(n *SymbolNode) string {
+	return fmt.Sprintf("%s (%s)", n.Name, n.Kind)
+}
+
+// RenderMermaid renders nodes/edges as a Mermaid flowchart, suitable for
+// embedding directly in Markdown docs.
+func RenderMermaid(nodes []*SymbolNode, edges []*Edge) string {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+
+	ids := make([]string, 0, len(nodes))
+	byID := make(map[string]*SymbolNode, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+		byID[n.ID] = n
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(&b, "  %s[%q]\n", mermaidID(id), nodeLabel(byID[id]))
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", mermaidID(e.From), e.Type, mermaidID(e.To))
+	}
+	return b.String()
+}
+
+// RenderDOT renders nodes/edges as Graphviz DOT, for `dot -Tsvg` or similar.
+func RenderDOT(nodes []*SymbolNode, edges []*Edge) string {
+	var b strings.Builder
+	b.WriteString("digraph codegraph {\n")
+
+	ids := make([]string, 0, len(nodes))
+	byID := make(map[string]*SymbolNode, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+		byID[n.ID] = n
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", id, nodeLabel(byID[id]))
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.From, e.To, e.Type)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidID sanitizes a symbol ID (file:line:col:name) into a Mermaid node
+// identifier, which can't contain most punctuation.
+func mermaidID(id string) string {
+	replacer := strings.NewReplacer(
+		"/", "_", ".", "_", ":", "_", "-", "_", " ", "_",
+	)
+	return "n" + replacer.Replace(id)
+}
@@ -2,37 +2,70 @@ package config
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	LLM          LLMConfig          `yaml:"llm"`
-	Tools        ToolsConfig        `yaml:"tools"`
-	Confirmation ConfirmationConfig `yaml:"confirmation"`
-	Logging      LoggingConfig      `yaml:"logging"`
-	Embeddings   EmbeddingsConfig   `yaml:"embeddings"`
-	Retrieval    RetrievalConfig    `yaml:"retrieval"`
-	LSP          LSPConfig          `yaml:"lsp"`
-	Checkpoint   CheckpointConfig   `yaml:"checkpoint"`
-	Memory       MemoryConfig       `yaml:"memory"`
-	Telemetry    TelemetryConfig    `yaml:"telemetry"`
-	Evaluation   EvaluationConfig   `yaml:"evaluation"`
-	BaseDir      string             `yaml:"-"` // Set at runtime to config file's directory (for logs)
-	WorkingDir   string             `yaml:"-"` // Set at runtime to current working directory (for TODO.md)
+	LLM            LLMConfig            `yaml:"llm"`
+	Tools          ToolsConfig          `yaml:"tools"`
+	Confirmation   ConfirmationConfig   `yaml:"confirmation"`
+	Logging        LoggingConfig        `yaml:"logging"`
+	Embeddings     EmbeddingsConfig     `yaml:"embeddings"`
+	Retrieval      RetrievalConfig      `yaml:"retrieval"`
+	LSP            LSPConfig            `yaml:"lsp"`
+	Checkpoint     CheckpointConfig     `yaml:"checkpoint"`
+	Memory         MemoryConfig         `yaml:"memory"`
+	Telemetry      TelemetryConfig      `yaml:"telemetry"`
+	Evaluation     EvaluationConfig     `yaml:"evaluation"`
+	Notifications  NotificationConfig   `yaml:"notifications"`
+	Locale         LocaleConfig         `yaml:"locale"`
+	Plan           PlanConfig           `yaml:"plan"`
+	Audit          AuditConfig          `yaml:"audit"`
+	CostGuard      CostGuardConfig      `yaml:"cost_guard"`
+	Initialization InitializationConfig `yaml:"initialization"`
+	Sync           SyncConfig           `yaml:"sync"`
+	Team           TeamConfig           `yaml:"team"`
+	Providers      map[string]LLMConfig `yaml:"providers"` // named backend profiles /model switch can swap to; keys are profile names, values replace the whole llm section
+	BaseDir        string               `yaml:"-"`         // Set at runtime to config file's directory (for logs)
+	ConfigPath     string               `yaml:"-"`         // Set at runtime to the loaded config file's path (for hot-reload)
+	WorkingDir     string               `yaml:"-"`         // Set at runtime to current working directory (for TODO.md)
+	Session        string               `yaml:"-"`         // Set at runtime from --session (for named session profiles)
+	TUI            bool                 `yaml:"-"`         // Set at runtime from --tui (for dashboard-style rendering)
+}
+
+// StateDir returns the directory that holds this session's mutable state
+// (history, TODO, checkpoints). Named sessions get their own subdirectory
+// under .gocode/sessions so independent conversation streams don't collide.
+func (c *Config) StateDir() string {
+	if c.Session == "" {
+		return filepath.Join(c.WorkingDir, ".gocode")
+	}
+	return filepath.Join(c.WorkingDir, ".gocode", "sessions", c.Session)
 }
 
 type LLMConfig struct {
-	Endpoint       string             `yaml:"endpoint"`
-	APIKey         string             `yaml:"api_key"`
-	Model          string             `yaml:"model"`
-	Temperature    float32            `yaml:"temperature"`
-	MaxTokens      int                `yaml:"max_tokens"`
-	ContextWindow  int                `yaml:"context_window"`
-	AutoManage     bool               `yaml:"auto_manage"`
-	StartupTimeout int                `yaml:"startup_timeout"`
-	Server         ServerConfig       `yaml:"server"`
+	Endpoint           string            `yaml:"endpoint"`
+	APIKey             string            `yaml:"api_key"`
+	Model              string            `yaml:"model"`
+	Temperature        float32           `yaml:"temperature"`
+	MaxTokens          int               `yaml:"max_tokens"`
+	Seed               int               `yaml:"seed"` // 0 = non-deterministic; set to pin sampling for reproducible runs
+	ContextWindow      int               `yaml:"context_window"`
+	AutoManage         bool              `yaml:"auto_manage"`
+	StartupTimeout     int               `yaml:"startup_timeout"`
+	Server             ServerConfig      `yaml:"server"`
+	IdleTimeoutMinutes int               `yaml:"idle_timeout_minutes"` // 0 = disabled; stop the managed server after this much inactivity to free VRAM
+	Stream             bool              `yaml:"stream"`               // render assistant text token-by-token via SSE instead of waiting for the full response
+	Headers            map[string]string `yaml:"headers,omitempty"`    // extra HTTP headers sent with every request, for providers that need more than a bearer token (e.g. Anthropic's anthropic-version)
+	ToolCalling        string            `yaml:"tool_calling"`         // "auto" (default), "native", or "textual"; see llm.Client's ReAct-style fallback
+	MockScript         string            `yaml:"mock_script"`          // path to a mockllm YAML script; when set, ServerManager serves scripted responses instead of spawning llama-server
 }
 
 type ServerConfig struct {
@@ -54,33 +87,136 @@ type ServerConfig struct {
 }
 
 type ToolsConfig struct {
-	Enabled []string `yaml:"enabled"`
+	Enabled        []string       `yaml:"enabled"`
+	MaxOutputBytes int            `yaml:"max_output_bytes"` // 0 = use registry default
+	Database       DBConfig       `yaml:"database"`
+	WebFetch       WebFetchConfig `yaml:"web_fetch"`
+	Git            GitConfig      `yaml:"git"`
+	Bash           BashConfig     `yaml:"bash"`
+}
+
+// BashConfig configures the sandbox BashTool commands run inside.
+type BashConfig struct {
+	Sandbox SandboxConfig `yaml:"sandbox"`
+}
+
+// SandboxConfig constrains what a bash command can do: which directory it's
+// jailed to, which environment variables it inherits, and how much output
+// it may produce. On Linux, UseNamespaces additionally runs the command
+// under `unshare` for filesystem/process isolation when that binary is
+// available; every other platform (and a Linux host without unshare)
+// degrades gracefully to the jail/env/output-size limits alone.
+type SandboxConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	WorkingDirJail string   `yaml:"working_dir_jail"` // commands (and any `cd`) may only run inside this directory; "" defaults to the workspace root
+	EnvAllowlist   []string `yaml:"env_allowlist"`    // environment variables passed through to the command; empty = inherit the agent's full environment unfiltered
+	MaxOutputBytes int      `yaml:"max_output_bytes"` // 0 = use BashTool's existing 30000-byte truncation
+	UseNamespaces  bool     `yaml:"use_namespaces"`   // Linux only: run under `unshare --mount --pid --fork` for filesystem/process isolation; ignored elsewhere
+}
+
+// GitConfig gates the git_commit tool, which is a stronger permission than
+// gocode's other file-editing tools since it writes to shared history.
+type GitConfig struct {
+	AllowCommit bool `yaml:"allow_commit"`
+}
+
+// WebFetchConfig constrains what web_fetch is allowed to reach, for
+// enterprise deployments that want to permit internal docs sites while
+// blocking arbitrary exfiltration targets. AllowDomains, when non-empty, is
+// an allowlist - any domain not on it is rejected regardless of DenyDomains.
+// DenyDomains always applies. Domain matching includes subdomains (an entry
+// of "example.com" also matches "docs.example.com").
+type WebFetchConfig struct {
+	AllowDomains   []string `yaml:"allow_domains"`
+	DenyDomains    []string `yaml:"deny_domains"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"` // 0 = use the tool's default (30s)
+	RespectRobots  bool     `yaml:"respect_robots"`  // fetch and honor robots.txt before requesting the page
+}
+
+// DBConfig configures the db_query tool's connection. Only "sqlite3" is
+// wired up today (matching the driver already vendored for checkpoints);
+// "postgres"/"mysql" are reserved for when those drivers are added.
+type DBConfig struct {
+	Driver   string `yaml:"driver"`    // sqlite3, postgres, mysql
+	DSN      string `yaml:"dsn"`       // connection string
+	ReadOnly bool   `yaml:"read_only"` // reject non-SELECT statements (default true)
 }
 
 type ConfirmationConfig struct {
-	Mode               string   `yaml:"mode"`
-	AutoApproveTools   []string `yaml:"auto_approve_tools"`
-	AlwaysConfirmTools []string `yaml:"always_confirm_tools"`
+	Mode               string           `yaml:"mode"`
+	AutoApproveTools   []string         `yaml:"auto_approve_tools"`
+	AlwaysConfirmTools []string         `yaml:"always_confirm_tools"`
+	Bell               bool             `yaml:"bell"`  // Ring the terminal bell when a confirmation is pending
+	Rules              []PermissionRule `yaml:"rules"` // allow/deny/ask rules, checked before the Mode-based heuristic above; see internal/confirmation
+}
+
+// PermissionRule is one allow/deny/ask rule matched against a tool call by
+// tool name, file path glob, and/or bash command pattern. The first rule
+// (across this config's Rules and the project's .gocode/permissions.yaml,
+// project rules taking precedence) whose conditions all match wins; a call
+// matching no rule falls back to ConfirmationConfig.Mode. See
+// internal/confirmation for how rules are compiled and evaluated.
+type PermissionRule struct {
+	Tools    []string `yaml:"tools"`    // tool names this rule applies to; empty matches any tool
+	Paths    []string `yaml:"paths"`    // filepath.Match globs checked against a write/edit/delete's file_path; empty matches any path (or tools with no file_path)
+	Commands []string `yaml:"commands"` // regexes checked against a bash command; empty matches any command (or non-bash tools)
+	Action   string   `yaml:"action"`   // "allow", "deny", or "ask"
+}
+
+// NotificationConfig controls OS-level notifications for long-running turns,
+// for users who switch windows during long local-model generations.
+type NotificationConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	ThresholdSeconds int  `yaml:"threshold_seconds"` // Notify when a turn takes at least this long
+}
+
+// LocaleConfig controls the language and formatting conventions the agent
+// uses when responding, for non-English teams. All fields default to empty,
+// which leaves the prompt templates' English-only instructions unchanged.
+type LocaleConfig struct {
+	Language     string `yaml:"language"`      // e.g. "Japanese", "fr"; empty = English
+	DateFormat   string `yaml:"date_format"`   // e.g. "DD/MM/YYYY"
+	NumberFormat string `yaml:"number_format"` // e.g. "1.234,56" (comma decimal separator)
+}
+
+// PlanConfig controls automatic TODO decomposition of multi-step requests.
+type PlanConfig struct {
+	Auto bool `yaml:"auto"` // If true, seed todo_write from a planning call before execution
 }
 
 type LoggingConfig struct {
-	Format          string `yaml:"format"`
-	Directory       string `yaml:"directory"`
-	Level           string `yaml:"level"`
-	LogToolResults  bool   `yaml:"log_tool_results"`
-	LogReasoning    bool   `yaml:"log_reasoning"`
+	Format         string `yaml:"format"`
+	Directory      string `yaml:"directory"`
+	Level          string `yaml:"level"`
+	LogToolResults bool   `yaml:"log_tool_results"`
+	LogReasoning   bool   `yaml:"log_reasoning"`
 }
 
 type EmbeddingsConfig struct {
-	Enabled   bool   `yaml:"enabled"`
-	Endpoint  string `yaml:"endpoint"`
-	Dimension int    `yaml:"dimension"`
-	DBPath    string `yaml:"db_path"`
+	Enabled       bool      `yaml:"enabled"`
+	Backend       string    `yaml:"backend"` // "llamacpp" (default), "ollama", or "openai" - see embeddings.NewEmbedder
+	Endpoint      string    `yaml:"endpoint"`
+	APIKey        string    `yaml:"api_key"` // only used by the "openai" backend
+	Model         string    `yaml:"model"`   // model name to request; unused by "llamacpp" (the server has one model loaded), required by "ollama" and "openai"
+	Dimension     int       `yaml:"dimension"`
+	DBPath        string    `yaml:"db_path"`
+	AnnotateBlame bool      `yaml:"annotate_blame"` // Add git blame author/date to indexed chunk metadata (requires the workspace to be a git repo)
+	ANN           ANNConfig `yaml:"ann"`
+}
+
+// ANNConfig controls the vector store's approximate nearest-neighbor search.
+// Left at its zero value (Enabled: false), Search does an exact brute-force
+// scan of every chunk, which is the only mode worth using below a few
+// thousand chunks anyway.
+type ANNConfig struct {
+	Enabled  bool `yaml:"enabled"`  // Use an IVF index over int8-quantized embeddings instead of exact brute-force search
+	Clusters int  `yaml:"clusters"` // Number of IVF partitions (k-means clusters)
+	Probes   int  `yaml:"probes"`   // Number of nearest partitions to scan per query
 }
 
 type RetrievalConfig struct {
-	Enabled bool              `yaml:"enabled"`
-	Weights RetrievalWeights  `yaml:"weights"`
+	Enabled bool             `yaml:"enabled"`
+	Weights RetrievalWeights `yaml:"weights"`
 }
 
 type RetrievalWeights struct {
@@ -90,7 +226,7 @@ type RetrievalWeights struct {
 }
 
 type LSPConfig struct {
-	Enabled bool                      `yaml:"enabled"`
+	Enabled bool                       `yaml:"enabled"`
 	Servers map[string]LSPServerConfig `yaml:"servers"`
 }
 
@@ -117,11 +253,113 @@ type TelemetryConfig struct {
 	DBPath      string `yaml:"db_path"`
 }
 
+// AuditConfig controls the hash-chained tool-invocation audit log, kept
+// separate from the debug logs in internal/logging for compliance
+// retention. DBPath is not used here since the log is a plain append-only
+// JSONL file (see internal/audit) rather than a database.
+type AuditConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // relative paths resolve under .gocode/
+}
+
 type EvaluationConfig struct {
 	Enabled      bool `yaml:"enabled"`
 	TrackMetrics bool `yaml:"track_metrics"`
 }
 
+// CostGuardConfig caps token spend per turn and per session so a runaway
+// tool loop or an unexpectedly large context injection on a remote,
+// pay-per-token provider doesn't burn through a budget unattended. 0
+// disables the corresponding limit.
+type CostGuardConfig struct {
+	MaxTokensPerTurn    int `yaml:"max_tokens_per_turn"`
+	MaxTokensPerSession int `yaml:"max_tokens_per_session"`
+}
+
+// InitializationConfig controls project analysis on startup/`gocode init`.
+// ExcludeDirs supplements the analyzer's hard-coded skip list (vendor,
+// node_modules, etc.) and whatever the project's own .gitignore already
+// covers, for directories that are neither - a generated docs site, a data
+// fixtures directory, and so on.
+type InitializationConfig struct {
+	ExcludeDirs []string `yaml:"exclude_dirs"`
+}
+
+// SyncConfig controls pushing/pulling the memory DB and .gocode/overview.md
+// to a user-provided remote location, encrypted client-side, so a
+// developer's accumulated project knowledge follows them across machines.
+// Only "webdav" is wired up today (a plain HTTP PUT/GET/MKCOL client);
+// "s3" is reserved for when that SDK is added.
+type SyncConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Provider      string `yaml:"provider"` // "webdav" (only backend implemented today)
+	URL           string `yaml:"url"`      // base URL of the remote directory
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+	EncryptionKey string `yaml:"encryption_key"` // passphrase; files are encrypted client-side before upload
+}
+
+// TeamConfig lets an organization publish a shared base config.yaml (default
+// model, confirmation policies, enabled tool sets) that every developer's
+// local config.yaml layers personal tweaks on top of, instead of hand-syncing
+// config.yaml changes across a team. ConfigURL is fetched fresh on every
+// Load and cached alongside the local config file, so a developer without
+// network access still starts from the last-known-good team defaults. Only
+// a plain http(s) URL is supported today; a git-path source (e.g. a repo
+// path resolved through the user's own git checkout) is not wired up yet.
+type TeamConfig struct {
+	ConfigURL      string `yaml:"config_url"`      // http(s) URL serving a base config.yaml; empty disables this feature
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // 0 = use the 5s default
+}
+
+// teamConfigCachePath returns where a team's fetched base config is cached
+// for offline fallback, next to the local config file rather than under
+// WorkingDir, since Load runs before WorkingDir is set.
+func teamConfigCachePath(localConfigPath string) string {
+	return filepath.Join(filepath.Dir(localConfigPath), ".gocode", "team-config-cache.yaml")
+}
+
+// fetchTeamConfig retrieves the team's base config.yaml over HTTP(S),
+// refreshing the local cache on success and falling back to it on failure
+// (network down, server error) so a missing connection doesn't block
+// startup entirely.
+func fetchTeamConfig(team TeamConfig, localConfigPath string) ([]byte, error) {
+	cachePath := teamConfigCachePath(localConfigPath)
+
+	timeout := time.Duration(team.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(team.ConfigURL)
+	if err != nil {
+		if cached, cacheErr := os.ReadFile(cachePath); cacheErr == nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if cached, cacheErr := os.ReadFile(cachePath); cacheErr == nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+		_ = os.WriteFile(cachePath, data, 0644)
+	}
+
+	return data, nil
+}
+
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -129,6 +367,22 @@ func Load(path string) (*Config, error) {
 	}
 
 	var cfg Config
+
+	// A team's shared defaults are unmarshalled first, so anything the local
+	// config.yaml doesn't set explicitly falls back to the org-wide policy;
+	// unmarshalling the local document on top then simply overwrites
+	// whichever fields it does set (the individual's personal tweaks).
+	var probe struct {
+		Team TeamConfig `yaml:"team"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err == nil && probe.Team.ConfigURL != "" {
+		if teamData, fetchErr := fetchTeamConfig(probe.Team, path); fetchErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not fetch team config from %s: %v\n", probe.Team.ConfigURL, fetchErr)
+		} else if unmarshalErr := yaml.Unmarshal(teamData, &cfg); unmarshalErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: team config from %s is invalid, ignoring: %v\n", probe.Team.ConfigURL, unmarshalErr)
+		}
+	}
+
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
@@ -144,6 +398,55 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// SetEnabled flips the `enabled:` flag under a top-level section (e.g.
+// "retrieval", "checkpoint") in the config.yaml at path, leaving every other
+// line - including comments - untouched. config.yaml is hand-maintained and
+// heavily commented, so a full yaml.Marshal round-trip would silently throw
+// that away; used by /setup to apply a recommendation without one.
+func SetEnabled(path, section string, enabled bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	inSection := false
+	changed := false
+	for i, line := range lines {
+		if line == section+":" {
+			inSection = true
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			break // reached the next top-level key without finding "enabled:"
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "enabled:") {
+			continue
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		rest := strings.TrimPrefix(trimmed, "enabled:")
+		comment := ""
+		if idx := strings.Index(rest, "#"); idx != -1 {
+			comment = "  " + rest[idx:]
+		}
+		lines[i] = fmt.Sprintf("%senabled: %v%s", indent, enabled, comment)
+		changed = true
+		break
+	}
+
+	if !changed {
+		return fmt.Errorf("could not find an enabled: flag under %q in %s", section, path)
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
 // Interface methods for feature detection
 
 func (c *Config) IsMemoryEnabled() bool {
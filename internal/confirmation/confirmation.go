@@ -9,22 +9,85 @@ import (
 	"strings"
 
 	"github.com/jake/gocode/internal/config"
-	"github.com/jake/gocode/internal/theme"
 )
 
 type System struct {
-	config     *config.ConfirmationConfig
-	reader     *bufio.Reader
-	workingDir string
+	config        *config.ConfirmationConfig
+	provider      Provider
+	workingDir    string
+	configRules   []compiledRule // from config.yaml's confirmation.rules
+	projectRules  []compiledRule // from .gocode/permissions.yaml, checked first
+	ruleLoadError error          // set if confirmation.rules or permissions.yaml failed to compile, for New's caller to warn about
 }
 
+// RuleLoadWarning returns a message describing why the permission rules
+// didn't fully load (e.g. an invalid regex in a "commands" pattern), or ""
+// if they loaded cleanly. A load failure isn't fatal - the System just runs
+// with no rules from the failing source, falling back to Mode's heuristic.
+func (s *System) RuleLoadWarning() string {
+	if s.ruleLoadError == nil {
+		return ""
+	}
+	return s.ruleLoadError.Error()
+}
+
+// New creates a System backed by a TerminalProvider, matching the original
+// stdin-driven behavior for interactive REPL sessions.
 func New(cfg *config.ConfirmationConfig) *System {
 	workingDir, _ := os.Getwd()
-	return &System{
+	return newSystem(cfg, NewTerminalProviderWithBell(bufio.NewReader(os.Stdin), cfg.Bell), workingDir)
+}
+
+// NewWithProvider creates a System backed by an arbitrary Provider, for
+// serve/one-shot/API contexts that have no controlling terminal to read
+// approvals from.
+func NewWithProvider(cfg *config.ConfirmationConfig, provider Provider) *System {
+	workingDir, _ := os.Getwd()
+	return newSystem(cfg, provider, workingDir)
+}
+
+func newSystem(cfg *config.ConfirmationConfig, provider Provider, workingDir string) *System {
+	s := &System{
 		config:     cfg,
-		reader:     bufio.NewReader(os.Stdin),
+		provider:   provider,
 		workingDir: workingDir,
 	}
+
+	if rules, err := compileRules(cfg.Rules); err != nil {
+		s.ruleLoadError = fmt.Errorf("confirmation.rules: %w", err)
+	} else {
+		s.configRules = rules
+	}
+
+	if projectRules, err := loadProjectRules(filepath.Join(workingDir, PermissionsFileName)); err == nil {
+		if compiled, err := compileRules(projectRules); err != nil {
+			s.ruleLoadError = err
+		} else {
+			s.projectRules = compiled
+		}
+	}
+
+	return s
+}
+
+// Decide checks toolName/args against the configured allow/deny/ask rules
+// (project rules from .gocode/permissions.yaml first, then config.yaml's
+// confirmation.rules), before ShouldConfirm's Mode-based heuristic ever
+// runs. denyReason is set only when allowed is false - the caller should
+// surface it instead of executing the tool or prompting for confirmation.
+// When no rule matches, allowed is true and confirmRequired falls through
+// to whatever ShouldConfirm(toolName, args) would return.
+func (s *System) Decide(toolName, args string) (allowed bool, confirmRequired bool, denyReason string) {
+	switch decision, reason := s.evaluateRules(toolName, args); decision {
+	case permissionDeny:
+		return false, false, reason
+	case permissionAllow:
+		return true, false, ""
+	case permissionAsk:
+		return true, true, ""
+	default:
+		return true, s.ShouldConfirm(toolName, args), ""
+	}
 }
 
 func (s *System) ShouldConfirm(toolName string, args string) bool {
@@ -133,32 +196,44 @@ func (s *System) accessesExternalPaths(args string) bool {
 	return false
 }
 
-func (s *System) RequestConfirmation(toolName string, args string) (bool, error) {
-	fmt.Printf("\n%s\n", theme.UserBold("╭─────────────────────────────────────────╮"))
-	fmt.Printf("%s\n", theme.UserBold("│ Tool Execution Request                 │"))
-	fmt.Printf("%s\n", theme.UserBold("╰─────────────────────────────────────────╯"))
-	fmt.Printf("\n%s %s\n", theme.User("Tool:"), theme.ToolBold(toolName))
-	fmt.Printf("\n%s\n%s\n", theme.User("Arguments:"), theme.HighlightJSON(args))
-	fmt.Printf("\n%s\n", theme.Dim("───────────────────────────────────────────"))
-	fmt.Printf("%s", theme.UserBold("Approve execution? [y/n/m]: "))
+// RequestConfirmation asks the provider to approve toolName/args, returning
+// the (possibly edited) arguments to execute with alongside the decision.
+func (s *System) RequestConfirmation(toolName string, args string) (bool, string, error) {
+	return s.provider.Confirm(toolName, args)
+}
 
-	response, err := s.reader.ReadString('\n')
-	if err != nil {
-		return false, fmt.Errorf("failed to read confirmation: %w", err)
-	}
+// PendingCall is one tool call awaiting confirmation, passed to
+// RequestGroupedConfirmation so a provider can render all of a turn's
+// pending calls together instead of one at a time.
+type PendingCall struct {
+	ToolName string
+	Args     string
+}
 
-	response = strings.ToLower(strings.TrimSpace(response))
+// Decision is the outcome of confirming a single PendingCall.
+type Decision struct {
+	Approved   bool
+	EditedArgs string
+}
 
-	switch response {
-	case "y", "yes":
-		return true, nil
-	case "n", "no":
-		return false, nil
-	case "m", "modify":
-		fmt.Println(theme.Warning("Modification not yet implemented - treating as reject"))
-		return false, nil
-	default:
-		fmt.Println(theme.Warning("Invalid response - treating as reject"))
-		return false, nil
+// RequestGroupedConfirmation confirms several pending calls from the same
+// turn at once, so e.g. five writes and two bash calls prompt once with a
+// grouped summary instead of seven sequential prompts. Providers that
+// support batching (see BatchProvider) render that grouped prompt; other
+// providers (AutoProvider, CallbackProvider) fall back to one Confirm call
+// per pending call, preserving their existing per-call behavior.
+func (s *System) RequestGroupedConfirmation(calls []PendingCall) ([]Decision, error) {
+	if batch, ok := s.provider.(BatchProvider); ok {
+		return batch.ConfirmBatch(calls)
+	}
+
+	decisions := make([]Decision, len(calls))
+	for i, call := range calls {
+		approved, editedArgs, err := s.provider.Confirm(call.ToolName, call.Args)
+		if err != nil {
+			return nil, err
+		}
+		decisions[i] = Decision{Approved: approved, EditedArgs: editedArgs}
 	}
+	return decisions, nil
 }
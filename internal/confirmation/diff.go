@@ -0,0 +1,110 @@
+package confirmation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jake/gocode/internal/snapshot"
+	"github.com/jake/gocode/internal/theme"
+)
+
+// diffPreview renders what a pending tool call would change, so a user
+// confirming it sees more than raw JSON args. For "write"/"edit" it's a
+// unified diff comparing the file's current on-disk content against the
+// proposed result - a write to a path that doesn't exist yet has no "old"
+// side and renders as an all-additions diff rather than being skipped. For
+// "rename_symbol"/"code_action" it's a plain-English description, since the
+// actual files/lines touched come from a WorkspaceEdit the language server
+// only produces once the tool runs, so no diff can be computed up front.
+// Returns "" for any other tool, or if the preview can't be computed -
+// callers fall back to the raw-args rendering in that case.
+func diffPreview(toolName, args string) string {
+	switch toolName {
+	case "write":
+		var a struct {
+			FilePath string `json:"file_path"`
+			Content  string `json:"content"`
+		}
+		if err := json.Unmarshal([]byte(args), &a); err != nil || a.FilePath == "" {
+			return ""
+		}
+		old, _ := os.ReadFile(a.FilePath)
+		return snapshot.UnifiedDiff(old, []byte(a.Content))
+
+	case "edit":
+		var a struct {
+			FilePath   string `json:"file_path"`
+			OldString  string `json:"old_string"`
+			NewString  string `json:"new_string"`
+			ReplaceAll bool   `json:"replace_all"`
+		}
+		if err := json.Unmarshal([]byte(args), &a); err != nil || a.FilePath == "" {
+			return ""
+		}
+		current, err := os.ReadFile(a.FilePath)
+		if err != nil {
+			return ""
+		}
+		var updated string
+		if a.ReplaceAll {
+			updated = strings.ReplaceAll(string(current), a.OldString, a.NewString)
+		} else {
+			updated = strings.Replace(string(current), a.OldString, a.NewString, 1)
+		}
+		return snapshot.UnifiedDiff(current, []byte(updated))
+
+	case "rename_symbol":
+		var a struct {
+			FilePath string `json:"file_path"`
+			Line     int    `json:"line"`
+			Column   int    `json:"column"`
+			NewName  string `json:"new_name"`
+		}
+		if err := json.Unmarshal([]byte(args), &a); err != nil || a.FilePath == "" || a.NewName == "" {
+			return ""
+		}
+		return fmt.Sprintf("Rename the symbol at %s:%d:%d to %q.\n(the exact files and lines touched are resolved by the language server when this runs, so no diff is available yet)",
+			a.FilePath, a.Line, a.Column, a.NewName)
+
+	case "code_action":
+		var a struct {
+			FilePath  string `json:"file_path"`
+			StartLine int    `json:"start_line"`
+			EndLine   int    `json:"end_line"`
+			Apply     int    `json:"apply"`
+		}
+		if err := json.Unmarshal([]byte(args), &a); err != nil || a.FilePath == "" {
+			return ""
+		}
+		if a.Apply <= 0 {
+			return "" // just listing actions, nothing pending to preview
+		}
+		return fmt.Sprintf("Apply code action #%d to %s (lines %d-%d).\n(the exact files and lines touched are resolved by the language server when this runs, so no diff is available yet)",
+			a.Apply, a.FilePath, a.StartLine, a.EndLine)
+
+	default:
+		return ""
+	}
+}
+
+// colorizeDiff colors a snapshot.UnifiedDiff's "+ "/"- " lines green/red and
+// dims unchanged context lines, matching the theme package's other
+// diff-adjacent rendering (e.g. /changes).
+func colorizeDiff(diff string) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+ "):
+			b.WriteString(theme.Success("%s", line))
+		case strings.HasPrefix(line, "- "):
+			b.WriteString(theme.Error("%s", line))
+		default:
+			b.WriteString(theme.Dim("%s", line))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
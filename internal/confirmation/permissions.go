@@ -0,0 +1,175 @@
+package confirmation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/jake/gocode/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// stringArg pulls a string field out of a tool call's JSON args, reporting
+// whether it was present - used to check "paths"/"commands" rule conditions
+// against tools that don't carry the field at all (e.g. a "paths" rule
+// against a tool with no file_path never matches).
+func stringArg(args, field string) (string, bool) {
+	var m map[string]interface{}
+	if json.Unmarshal([]byte(args), &m) != nil {
+		return "", false
+	}
+	v, ok := m[field].(string)
+	return v, ok
+}
+
+// PermissionsFileName is the project-level rules file checked alongside
+// config.yaml's confirmation.rules, mirroring how internal/policy reads
+// .gocode/policy.yaml - a team can commit permission rules to the repo
+// instead of every contributor configuring the same rules locally.
+const PermissionsFileName = ".gocode/permissions.yaml"
+
+// permissionDecision is the outcome of matching a tool call against the
+// configured rules.
+type permissionDecision int
+
+const (
+	// noDecision means no rule matched; the caller falls back to
+	// ConfirmationConfig.Mode's heuristic.
+	noDecision permissionDecision = iota
+	permissionAllow
+	permissionDeny
+	permissionAsk
+)
+
+// compiledRule is a config.PermissionRule with its command patterns
+// pre-compiled, and its tool list turned into a set for O(1) lookup.
+type compiledRule struct {
+	tools    map[string]bool // nil = matches any tool
+	paths    []string
+	commands []*regexp.Regexp
+	action   string
+}
+
+func compileRules(rules []config.PermissionRule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, r := range rules {
+		cr := compiledRule{paths: r.Paths, action: r.Action}
+		if len(r.Tools) > 0 {
+			cr.tools = make(map[string]bool, len(r.Tools))
+			for _, t := range r.Tools {
+				cr.tools[t] = true
+			}
+		}
+		for _, pattern := range r.Commands {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule command pattern %q: %w", pattern, err)
+			}
+			cr.commands = append(cr.commands, re)
+		}
+		compiled = append(compiled, cr)
+	}
+	return compiled, nil
+}
+
+// loadProjectRules reads a project-level permissions.yaml. A missing file
+// is not an error - callers should treat it as "no project rules".
+func loadProjectRules(path string) ([]config.PermissionRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Rules []config.PermissionRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return parsed.Rules, nil
+}
+
+// matches reports whether toolName/args satisfies every condition on r
+// (tools, paths, commands) - conditions r doesn't set are treated as
+// wildcards, but a path/command condition that r does set requires args to
+// actually carry a matching file_path/command field.
+func (r compiledRule) matches(toolName, args string) bool {
+	if r.tools != nil && !r.tools[toolName] {
+		return false
+	}
+
+	if len(r.paths) > 0 {
+		path, ok := stringArg(args, "file_path")
+		if !ok {
+			return false
+		}
+		if !matchesAnyPath(r.paths, path) {
+			return false
+		}
+	}
+
+	if len(r.commands) > 0 {
+		command, ok := stringArg(args, "command")
+		if !ok {
+			return false
+		}
+		matched := false
+		for _, re := range r.commands {
+			if re.MatchString(command) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesAnyPath(globs []string, path string) bool {
+	for _, pattern := range globs {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+		// filepath.Match doesn't cross path separators; also check the base
+		// name so patterns like "*.env" catch nested files.
+		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateRules returns the decision (and, for a deny, a human-readable
+// reason) of the first matching rule across project rules (checked first,
+// so they take precedence) and config.yaml rules.
+func (s *System) evaluateRules(toolName, args string) (permissionDecision, string) {
+	for _, r := range s.projectRules {
+		if r.matches(toolName, args) {
+			return actionDecision(r.action), fmt.Sprintf("denied by %s: %s %s is not permitted", PermissionsFileName, toolName, args)
+		}
+	}
+	for _, r := range s.configRules {
+		if r.matches(toolName, args) {
+			return actionDecision(r.action), fmt.Sprintf("denied by confirmation.rules in config.yaml: %s %s is not permitted", toolName, args)
+		}
+	}
+	return noDecision, ""
+}
+
+func actionDecision(action string) permissionDecision {
+	switch action {
+	case "allow":
+		return permissionAllow
+	case "deny":
+		return permissionDeny
+	case "ask":
+		return permissionAsk
+	default:
+		return noDecision
+	}
+}
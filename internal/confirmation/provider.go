@@ -0,0 +1,236 @@
+package confirmation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jake/gocode/internal/theme"
+)
+
+// Provider decides whether a proposed tool call is approved. System owns the
+// policy (which calls need confirming); Provider owns how the human (or a
+// stand-in for one) actually answers, so serve/one-shot modes that have no
+// controlling terminal can swap in something other than stdin. Confirm
+// returns the (possibly edited) arguments to execute with, so a provider
+// that lets the user tweak a near-miss proposal can hand back the fixed
+// version instead of forcing an outright reject.
+type Provider interface {
+	Confirm(toolName string, args string) (approved bool, editedArgs string, err error)
+}
+
+// TerminalProvider prompts on stdin/stdout, matching the original System
+// behavior.
+type TerminalProvider struct {
+	reader *bufio.Reader
+	bell   bool
+}
+
+func NewTerminalProvider(reader *bufio.Reader) *TerminalProvider {
+	return &TerminalProvider{reader: reader}
+}
+
+// NewTerminalProviderWithBell is like NewTerminalProvider but rings the
+// terminal bell on each prompt, for users who alt-tab away during long
+// local-model generations and might otherwise miss a pending confirmation.
+func NewTerminalProviderWithBell(reader *bufio.Reader, bell bool) *TerminalProvider {
+	return &TerminalProvider{reader: reader, bell: bell}
+}
+
+func (p *TerminalProvider) Confirm(toolName string, args string) (bool, string, error) {
+	if p.bell {
+		fmt.Print("\a")
+	}
+	fmt.Printf("\n%s\n", theme.UserBold("╭─────────────────────────────────────────╮"))
+	fmt.Printf("%s\n", theme.UserBold("│ Tool Execution Request                 │"))
+	fmt.Printf("%s\n", theme.UserBold("╰─────────────────────────────────────────╯"))
+	fmt.Printf("\n%s %s\n", theme.User("Tool:"), theme.ToolBold(toolName))
+	if diff := diffPreview(toolName, args); diff != "" {
+		fmt.Printf("\n%s\n%s", theme.User("Proposed change:"), colorizeDiff(diff))
+	} else {
+		fmt.Printf("\n%s\n%s\n", theme.User("Arguments:"), theme.HighlightJSON(args))
+	}
+	fmt.Printf("\n%s\n", theme.Dim("───────────────────────────────────────────"))
+	fmt.Printf("%s", theme.UserBold("Approve execution? [y/n/e(dit)]: "))
+
+	response, err := p.reader.ReadString('\n')
+	if err != nil {
+		return false, args, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+
+	switch response {
+	case "y", "yes":
+		return true, args, nil
+	case "n", "no":
+		return false, args, nil
+	case "e", "edit":
+		edited, err := p.editArgs(args)
+		if err != nil {
+			fmt.Println(theme.Warning("Edit failed (%v) - treating as reject", err))
+			return false, args, nil
+		}
+		return true, edited, nil
+	default:
+		fmt.Println(theme.Warning("Invalid response - treating as reject"))
+		return false, args, nil
+	}
+}
+
+// BatchProvider is implemented by providers that can present several
+// pending calls at once (see System.RequestGroupedConfirmation). Providers
+// with no notion of "at once" (AutoProvider, CallbackProvider) simply don't
+// implement it and fall back to being asked one call at a time.
+type BatchProvider interface {
+	ConfirmBatch(calls []PendingCall) ([]Decision, error)
+}
+
+// ConfirmBatch renders every pending call as a single grouped summary (tool
+// name plus the file path or command it acts on) and lets the user approve
+// or reject the whole group in one round-trip, or fall through to the usual
+// per-call y/n/e prompt when they want to pick calls individually.
+func (p *TerminalProvider) ConfirmBatch(calls []PendingCall) ([]Decision, error) {
+	if p.bell {
+		fmt.Print("\a")
+	}
+	fmt.Printf("\n%s\n", theme.UserBold("╭─────────────────────────────────────────╮"))
+	fmt.Printf("%s\n", theme.UserBold("│ Tool Execution Request (batch)         │"))
+	fmt.Printf("%s\n", theme.UserBold("╰─────────────────────────────────────────╯"))
+	fmt.Printf("\n%s\n", theme.User("This turn wants to run:"))
+	for i, call := range calls {
+		fmt.Printf("  %s %s %s\n", theme.Dim("%d.", i+1), theme.ToolBold(call.ToolName), theme.Dim(summarizeCallArgs(call.Args)))
+	}
+	fmt.Printf("\n%s\n", theme.Dim("───────────────────────────────────────────"))
+	fmt.Printf("%s", theme.UserBold("Approve [a]ll / [n]one / [s]elect individually: "))
+
+	response, err := p.reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+
+	decisions := make([]Decision, len(calls))
+	switch response {
+	case "a", "all", "y", "yes":
+		for i, call := range calls {
+			decisions[i] = Decision{Approved: true, EditedArgs: call.Args}
+		}
+		return decisions, nil
+	case "n", "none", "no":
+		for i, call := range calls {
+			decisions[i] = Decision{Approved: false, EditedArgs: call.Args}
+		}
+		return decisions, nil
+	default:
+		// "s"/"select" and anything else falls back to the individual
+		// y/n/e(dit) prompt per call, so editing a near-miss still works.
+		for i, call := range calls {
+			approved, editedArgs, err := p.Confirm(call.ToolName, call.Args)
+			if err != nil {
+				return nil, err
+			}
+			decisions[i] = Decision{Approved: approved, EditedArgs: editedArgs}
+		}
+		return decisions, nil
+	}
+}
+
+// summarizeCallArgs renders a one-line description of a pending call's
+// arguments for the grouped confirmation prompt - the file path for
+// file-editing tools, the command for bash, or the raw args as a fallback.
+func summarizeCallArgs(args string) string {
+	var argsMap map[string]interface{}
+	if err := json.Unmarshal([]byte(args), &argsMap); err != nil {
+		return args
+	}
+	if path, ok := argsMap["file_path"].(string); ok {
+		return path
+	}
+	if command, ok := argsMap["command"].(string); ok {
+		if len(command) > 60 {
+			command = command[:57] + "..."
+		}
+		return command
+	}
+	return args
+}
+
+// editArgs opens the proposed arguments in $EDITOR (falling back to vi) via
+// a temp file, so a near-miss proposal (wrong path, one bad flag) can be
+// fixed and run instead of rejected outright.
+func (p *TerminalProvider) editArgs(args string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "gocode-confirm-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.WriteString(args); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tmp.Close()
+
+	editorParts := strings.Fields(editor)
+	if len(editorParts) == 0 {
+		editorParts = []string{"vi"}
+	}
+	cmd := exec.Command(editorParts[0], append(editorParts[1:], tmpPath)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run $EDITOR (%s): %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited arguments: %w", err)
+	}
+
+	return strings.TrimSpace(string(edited)), nil
+}
+
+// AutoProvider approves or rejects every request without prompting, for
+// non-interactive/API contexts that have already decided their policy
+// (e.g. --yolo one-shot runs, or a caller that pre-approved everything).
+type AutoProvider struct {
+	Approve bool
+}
+
+func NewAutoProvider(approve bool) *AutoProvider {
+	return &AutoProvider{Approve: approve}
+}
+
+func (p *AutoProvider) Confirm(toolName string, args string) (bool, string, error) {
+	return p.Approve, args, nil
+}
+
+// CallbackFunc lets an external integration (HTTP/SSE endpoint, webhook)
+// answer a confirmation request programmatically. It may return edited
+// arguments alongside its approval, mirroring the terminal [e]dit flow.
+type CallbackFunc func(toolName string, args string) (approved bool, editedArgs string, err error)
+
+// CallbackProvider delegates the approval decision to a caller-supplied
+// function, e.g. one that blocks on an SSE round-trip to a remote client.
+type CallbackProvider struct {
+	callback CallbackFunc
+}
+
+func NewCallbackProvider(callback CallbackFunc) *CallbackProvider {
+	return &CallbackProvider{callback: callback}
+}
+
+func (p *CallbackProvider) Confirm(toolName string, args string) (bool, string, error) {
+	return p.callback(toolName, args)
+}
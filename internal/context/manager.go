@@ -10,25 +10,25 @@ import (
 
 // BudgetConfig defines context window budget allocation
 type BudgetConfig struct {
-	MaxTokens         int     // Total context window size
-	SystemTokens      int     // Reserved for system prompt
-	UserInputTokens   int     // Reserved for latest user input
-	ContextTokens     int     // Reserved for retrieved context
-	HistoryTokens     int     // Reserved for conversation history
-	ResponseTokens    int     // Reserved for model response
-	PruneThreshold    float64 // Prune when this % of budget is used (0.8 = 80%)
+	MaxTokens       int     // Total context window size
+	SystemTokens    int     // Reserved for system prompt
+	UserInputTokens int     // Reserved for latest user input
+	ContextTokens   int     // Reserved for retrieved context
+	HistoryTokens   int     // Reserved for conversation history
+	ResponseTokens  int     // Reserved for model response
+	PruneThreshold  float64 // Prune when this % of budget is used (0.8 = 80%)
 }
 
 // DefaultBudgetConfig returns sensible defaults for 100K context window
 func DefaultBudgetConfig() BudgetConfig {
 	return BudgetConfig{
-		MaxTokens:       102400,  // 100K context
-		SystemTokens:    2000,    // System prompt
-		UserInputTokens: 4000,    // Latest user message
-		ContextTokens:   30000,   // Retrieved context (largest portion)
-		HistoryTokens:   60000,   // Conversation history
-		ResponseTokens:  4096,    // Model response
-		PruneThreshold:  0.8,     // Prune at 80% capacity
+		MaxTokens:       102400, // 100K context
+		SystemTokens:    2000,   // System prompt
+		UserInputTokens: 4000,   // Latest user message
+		ContextTokens:   30000,  // Retrieved context (largest portion)
+		HistoryTokens:   60000,  // Conversation history
+		ResponseTokens:  4096,   // Model response
+		PruneThreshold:  0.8,    // Prune at 80% capacity
 	}
 }
 
@@ -37,6 +37,7 @@ type Manager struct {
 	config    BudgetConfig
 	messages  []llm.Message
 	promptMgr *prompts.PromptManager
+	tokenizer Tokenizer
 }
 
 // NewManager creates a new context manager
@@ -49,9 +50,20 @@ func NewManager(config BudgetConfig) *Manager {
 		config:    config,
 		messages:  []llm.Message{},
 		promptMgr: promptMgr,
+		tokenizer: defaultTokenizer,
 	}
 }
 
+// SetTokenizer overrides this Manager's tokenizer, e.g. for a caller that
+// wants the heuristic even while a server-backed default is configured
+// globally.
+func (m *Manager) SetTokenizer(t Tokenizer) {
+	if t == nil {
+		t = heuristicTokenizer{}
+	}
+	m.tokenizer = t
+}
+
 // AddMessage adds a message to the context
 func (m *Manager) AddMessage(msg llm.Message) {
 	m.messages = append(m.messages, msg)
@@ -67,9 +79,17 @@ func (m *Manager) GetMessages() []llm.Message {
 	return m.messages
 }
 
-// EstimateTokens estimates token count for text (rough approximation)
-// Real implementation would use tiktoken or similar
+// EstimateTokens counts tokens for text using this Manager's tokenizer -
+// the heuristic chars/3.5 approximation by default, or a server-backed
+// tokenizer (see SetTokenizer/SetDefaultTokenizer) for true counts.
 func (m *Manager) EstimateTokens(text string) int {
+	return m.tokenizer.CountTokens(text)
+}
+
+// EstimateTokens is the package-level form of (*Manager).EstimateTokens, so
+// callers that just need the shared char-per-token heuristic (e.g. the
+// embeddings chunker) don't need a full Manager to get it.
+func EstimateTokens(text string) int {
 	// Rough estimate: 1 token ≈ 4 characters for English text
 	// For code, it's closer to 1 token ≈ 3 characters
 	// We'll use 3.5 as a middle ground
@@ -187,6 +207,73 @@ func (m *Manager) SummarizeMessages(messages []llm.Message) string {
 	return summary.String()
 }
 
+// ToolResultCompressionTurns is how many of the most recent user turns keep
+// their tool results verbatim; older ones are eligible for
+// CompressOldToolResults.
+const ToolResultCompressionTurns = 5
+
+// toolResultCompressionMinChars is the smallest tool result
+// CompressOldToolResults bothers compressing - short results already cost
+// little enough that summarizing them (and writing an artifact) isn't worth
+// it.
+const toolResultCompressionMinChars = 500
+
+// CompressOldToolResults replaces tool-result messages more than
+// ToolResultCompressionTurns turns old with a one-line summary plus an
+// artifact reference, freeing history budget while keeping the full result
+// recoverable. saveArtifact persists the original content under a name
+// derived from the message and returns where it landed (e.g.
+// (*tools.Registry).SaveArtifact); a message whose save fails is compressed
+// without the reference rather than left verbatim.
+func (m *Manager) CompressOldToolResults(saveArtifact func(name, content string) (string, error)) []llm.Message {
+	totalTurns := 0
+	for _, msg := range m.messages {
+		if msg.Role == "user" {
+			totalTurns++
+		}
+	}
+
+	compressed := make([]llm.Message, len(m.messages))
+	copy(compressed, m.messages)
+
+	turn := 0
+	for i, msg := range compressed {
+		if msg.Role == "user" {
+			turn++
+		}
+		if msg.Role != "tool" || len(msg.Content) < toolResultCompressionMinChars {
+			continue
+		}
+		if totalTurns-turn < ToolResultCompressionTurns {
+			continue // still within the recent window
+		}
+
+		summary := firstLine(msg.Content, 120)
+		path, err := saveArtifact(msg.ToolID, msg.Content)
+		if err != nil {
+			compressed[i].Content = fmt.Sprintf("%s\n[%d bytes compressed]", summary, len(msg.Content))
+			continue
+		}
+		compressed[i].Content = fmt.Sprintf("%s\n[%d bytes compressed - full result saved to %s]", summary, len(msg.Content), path)
+	}
+
+	m.messages = compressed
+	return compressed
+}
+
+// firstLine returns s truncated to its first line and at most max runes -
+// the one-line summary CompressOldToolResults keeps in place of a
+// compressed tool result.
+func firstLine(s string, max int) string {
+	if idx := strings.IndexByte(s, '\n'); idx != -1 {
+		s = s[:idx]
+	}
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
+
 // PrepareMessagesForLLM prepares messages with context injection and pruning
 func (m *Manager) PrepareMessagesForLLM(retrievedContext []string) []llm.Message {
 	// Prune if necessary
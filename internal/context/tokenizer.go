@@ -0,0 +1,97 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Tokenizer counts tokens for a piece of text, so budget accounting and
+// pruning decisions can use the same unit the model itself is billed and
+// context-limited in.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// heuristicTokenizer is the char-count approximation used when no
+// server-backed tokenizer is configured, or when one fails.
+type heuristicTokenizer struct{}
+
+func (heuristicTokenizer) CountTokens(text string) int {
+	return EstimateTokens(text)
+}
+
+// defaultTokenizer is used by every new Manager unless overridden with
+// SetTokenizer. It starts as the heuristic so callers that never touch
+// tokenizer setup (most tests, the embeddings chunker via the package-level
+// EstimateTokens) keep working unchanged.
+var defaultTokenizer Tokenizer = heuristicTokenizer{}
+
+// SetDefaultTokenizer overrides the tokenizer new Managers pick up, e.g. to
+// point at a running llama.cpp server's /tokenize endpoint for accurate
+// counts instead of the chars/3.5 heuristic. Passing nil restores the
+// heuristic.
+func SetDefaultTokenizer(t Tokenizer) {
+	if t == nil {
+		t = heuristicTokenizer{}
+	}
+	defaultTokenizer = t
+}
+
+// LlamaCppTokenizer calls a running llama.cpp server's /tokenize endpoint
+// for exact token counts against the same model generating completions.
+// Any request error falls back to the chars/3.5 heuristic so a slow or
+// unreachable server degrades gracefully instead of blocking budget checks.
+type LlamaCppTokenizer struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewLlamaCppTokenizer builds a tokenizer against endpoint, the llama.cpp
+// server's base URL (e.g. "http://localhost:8080" - not the "/v1"-suffixed
+// OpenAI-compatible completions path).
+func NewLlamaCppTokenizer(endpoint string) *LlamaCppTokenizer {
+	return &LlamaCppTokenizer{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (t *LlamaCppTokenizer) CountTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	body, err := json.Marshal(map[string]string{"content": text})
+	if err != nil {
+		return EstimateTokens(text)
+	}
+
+	resp, err := t.client.Post(t.endpoint+"/tokenize", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return EstimateTokens(text)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return EstimateTokens(text)
+	}
+
+	var result struct {
+		Tokens []int `json:"tokens"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return EstimateTokens(text)
+	}
+
+	return len(result.Tokens)
+}
+
+// ServerBaseURL strips the OpenAI-compatible "/v1" suffix from an LLM
+// endpoint, so callers configured with e.g. "http://localhost:8080/v1" can
+// still reach llama.cpp's root-level /tokenize endpoint.
+func ServerBaseURL(endpoint string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(endpoint, "/"), "/v1")
+}
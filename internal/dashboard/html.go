@@ -0,0 +1,76 @@
+package dashboard
+
+// indexHTML is the dashboard's single page - vanilla HTML/JS only, no build
+// step and no third-party JS libraries, so `gocode dashboard` has nothing to
+// install beyond the binary itself.
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>GoCode Dashboard</title>
+<style>
+  body { font-family: -apple-system, sans-serif; margin: 2rem; background: #111; color: #ddd; }
+  h1 { font-size: 1.3rem; }
+  h2 { font-size: 1rem; color: #8ab; margin-top: 2rem; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: 0.3rem 0.6rem; border-bottom: 1px solid #333; font-size: 0.85rem; }
+  th { color: #8ab; }
+  .empty { color: #666; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>GoCode Dashboard</h1>
+
+<h2>Recent Traces</h2>
+<table id="traces"><thead><tr><th>Trace</th><th>Spans</th><th>Started</th></tr></thead><tbody></tbody></table>
+
+<h2>Tool Usage</h2>
+<table id="tools"><thead><tr><th>Tool</th><th>Calls</th><th>Failures</th></tr></thead><tbody></tbody></table>
+
+<h2>Token Usage by Day</h2>
+<table id="tokens"><thead><tr><th>Day</th><th>Prompt</th><th>Completion</th></tr></thead><tbody></tbody></table>
+
+<h2>Recent Memories</h2>
+<table id="memories"><thead><tr><th>Type</th><th>Summary</th><th>Created</th></tr></thead><tbody></tbody></table>
+
+<h2>Checkpoint Threads</h2>
+<table id="threads"><thead><tr><th>Name</th><th>Updated</th><th>Current Checkpoint</th></tr></thead><tbody></tbody></table>
+
+<script>
+function fillTable(id, rows, mapRow) {
+  const tbody = document.querySelector('#' + id + ' tbody');
+  tbody.innerHTML = '';
+  if (!rows || rows.length === 0) {
+    tbody.innerHTML = '<tr><td colspan="3" class="empty">No data yet</td></tr>';
+    return;
+  }
+  for (const row of rows) {
+    const tr = document.createElement('tr');
+    tr.innerHTML = mapRow(row).map(c => '<td>' + c + '</td>').join('');
+    tbody.appendChild(tr);
+  }
+}
+
+async function load() {
+  const traces = await fetch('/api/traces').then(r => r.json());
+  fillTable('traces', traces, t => [t.TraceID, t.SpanCount, new Date(t.StartTime / 1e6).toLocaleString()]);
+
+  const tools = await fetch('/api/tools').then(r => r.json());
+  fillTable('tools', tools, t => [t.Name, t.Calls, t.Failures]);
+
+  const tokens = await fetch('/api/tokens').then(r => r.json());
+  fillTable('tokens', tokens, t => [t.Day, t.PromptTokens, t.CompletionTokens]);
+
+  const memories = await fetch('/api/memories').then(r => r.json());
+  fillTable('memories', memories, m => [m.type, m.summary || m.content, new Date(m.created_at).toLocaleString()]);
+
+  const threads = await fetch('/api/threads').then(r => r.json());
+  fillTable('threads', threads, t => [t.name, t.updated_at, t.current_checkpoint]);
+}
+
+load();
+setInterval(load, 5000);
+</script>
+</body>
+</html>
+`
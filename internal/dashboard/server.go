@@ -0,0 +1,175 @@
+// Package dashboard serves a small local web UI over the data GoCode already
+// writes to disk - trace spans in traces.db, long-term memories in
+// memory.db, and checkpoint threads in checkpoints.db - so a developer can
+// see what happened in a session without grepping through log files.
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jake/gocode/internal/checkpoint"
+	"github.com/jake/gocode/internal/memory"
+	"github.com/jake/gocode/internal/telemetry"
+)
+
+// Server holds the data sources backing the dashboard's JSON API. Any of
+// them may be nil if the corresponding store couldn't be opened (feature
+// disabled, or the database doesn't exist yet) - handlers degrade to an
+// empty result rather than failing the whole page.
+type Server struct {
+	traces      *telemetry.SQLiteExporter
+	memories    *memory.LongTermMemory
+	checkpoints *checkpoint.Manager
+}
+
+// Open best-effort opens each backing store at the given path. A missing or
+// unopenable store is left nil rather than treated as a fatal error, since
+// the dashboard is a read-only diagnostic view and a partial one is more
+// useful than none.
+func Open(tracesDBPath, memoryDBPath, checkpointsDBPath string) *Server {
+	s := &Server{}
+
+	if traces, err := telemetry.NewSQLiteExporter(tracesDBPath); err == nil {
+		s.traces = traces
+	}
+	if memories, err := memory.NewLongTermMemory(memoryDBPath); err == nil {
+		s.memories = memories
+	}
+	if mgr, err := checkpoint.NewManager(checkpoint.Config{DBPath: checkpointsDBPath}); err == nil {
+		s.checkpoints = mgr
+	}
+
+	return s
+}
+
+// Close releases whichever backing stores were successfully opened.
+func (s *Server) Close() {
+	if s.traces != nil {
+		s.traces.Close()
+	}
+	if s.memories != nil {
+		s.memories.Close()
+	}
+	if s.checkpoints != nil {
+		s.checkpoints.Close()
+	}
+}
+
+// Handler returns the http.Handler serving the dashboard page and its JSON
+// API. Kept separate from Open so a caller (e.g. a future `--dashboard`
+// flag on the interactive agent) can mount it under its own mux.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/api/traces", s.handleTraces)
+	mux.HandleFunc("/api/tools", s.handleTools)
+	mux.HandleFunc("/api/tokens", s.handleTokens)
+	mux.HandleFunc("/api/memories", s.handleMemories)
+	mux.HandleFunc("/api/threads", s.handleThreads)
+	return mux
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(indexHTML))
+}
+
+func (s *Server) handleTraces(w http.ResponseWriter, r *http.Request) {
+	if s.traces == nil {
+		writeJSON(w, []telemetry.TraceInfo{})
+		return
+	}
+	traces, err := s.traces.ListRecentTraces(50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, traces)
+}
+
+func (s *Server) handleTools(w http.ResponseWriter, r *http.Request) {
+	if s.traces == nil {
+		writeJSON(w, []telemetry.ToolStat{})
+		return
+	}
+	stats, err := s.traces.ToolStats()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, stats)
+}
+
+func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
+	if s.traces == nil {
+		writeJSON(w, []telemetry.TokenUsagePoint{})
+		return
+	}
+	points, err := s.traces.TokenUsageByDay()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, points)
+}
+
+func (s *Server) handleMemories(w http.ResponseWriter, r *http.Request) {
+	if s.memories == nil {
+		writeJSON(w, []*memory.Memory{})
+		return
+	}
+	recent, err := s.memories.GetRecent(50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, recent)
+}
+
+// threadSummary is the subset of checkpoint.Thread the dashboard shows -
+// its full Metadata map is an implementation detail callers shouldn't rely
+// on the shape of.
+type threadSummary struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+	CurrentCheckpoint string `json:"current_checkpoint"`
+}
+
+func (s *Server) handleThreads(w http.ResponseWriter, r *http.Request) {
+	if s.checkpoints == nil {
+		writeJSON(w, []threadSummary{})
+		return
+	}
+	threads, err := s.checkpoints.ListThreads()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]threadSummary, 0, len(threads))
+	for _, t := range threads {
+		summaries = append(summaries, threadSummary{
+			ID:                t.ID,
+			Name:              t.Name,
+			CreatedAt:         t.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:         t.UpdatedAt.Format("2006-01-02 15:04:05"),
+			CurrentCheckpoint: t.CurrentCheckpoint,
+		})
+	}
+	writeJSON(w, summaries)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Fprintf(w, `{"error": %q}`, err.Error())
+	}
+}
@@ -0,0 +1,192 @@
+package embeddings
+
+import (
+	"math"
+	"sort"
+)
+
+// ANNConfig controls the vector store's approximate search. Kept as a plain
+// local struct rather than importing internal/config, the same way
+// ChunkerConfig is - the caller (internal/agent) translates config's
+// ANNConfig into this one at construction time.
+type ANNConfig struct {
+	Enabled  bool // Store int8-quantized embeddings and search via an IVF index instead of exact brute-force
+	Clusters int  // Number of IVF partitions (k-means clusters)
+	Probes   int  // Number of nearest partitions to scan per query
+}
+
+// DefaultANNConfig returns sane Clusters/Probes for when ANN is turned on
+// without tuning them.
+func DefaultANNConfig() ANNConfig {
+	return ANNConfig{
+		Enabled:  false,
+		Clusters: 64,
+		Probes:   8,
+	}
+}
+
+// quantizeInt8 converts a float32 vector to a symmetric int8 quantization
+// plus the scale needed to recover it: original[i] ≈ int8[i] * scale. This
+// is what lets ANNConfig.Enabled cut an indexed chunk's embedding footprint
+// 4x versus float32, at a cosine-similarity error that's usually well under
+// 1% for embedding-sized vectors.
+func quantizeInt8(v []float32) ([]int8, float32) {
+	var maxAbs float32
+	for _, x := range v {
+		abs := x
+		if abs < 0 {
+			abs = -abs
+		}
+		if abs > maxAbs {
+			maxAbs = abs
+		}
+	}
+	if maxAbs == 0 {
+		return make([]int8, len(v)), 1
+	}
+
+	scale := maxAbs / 127
+	q := make([]int8, len(v))
+	for i, x := range v {
+		r := x / scale
+		switch {
+		case r > 127:
+			r = 127
+		case r < -127:
+			r = -127
+		}
+		q[i] = int8(math.Round(float64(r)))
+	}
+	return q, scale
+}
+
+// dequantizeInt8 reverses quantizeInt8.
+func dequantizeInt8(q []int8, scale float32) []float32 {
+	v := make([]float32, len(q))
+	for i, x := range q {
+		v[i] = float32(x) * scale
+	}
+	return v
+}
+
+// ivfIndex is a simple inverted-file index: k-means partitions the indexed
+// vectors into clusters, and a query is only compared against chunks in the
+// nearest few clusters instead of the whole store. This is the IVF
+// alternative to HNSW - considerably easier to hand-roll correctly than a
+// graph index, and enough to keep search sub-linear as the store grows into
+// the tens of thousands of chunks.
+type ivfIndex struct {
+	centroids [][]float32
+	members   [][]string // cluster index -> chunk IDs assigned to it
+}
+
+// kmeansIterations bounds how many Lloyd's-algorithm passes buildIVFIndex
+// runs. The index only needs to be roughly right - a handful of passes is
+// plenty to separate an embedding space into useful neighborhoods.
+const kmeansIterations = 8
+
+// buildIVFIndex partitions chunks' vectors into k clusters via k-means,
+// seeded from evenly spaced points across the (sorted, for reproducibility)
+// chunk IDs so rebuilding from the same store twice yields the same index.
+func buildIVFIndex(chunks map[string]*IndexedChunk, k int) *ivfIndex {
+	ids := make([]string, 0, len(chunks))
+	for id := range chunks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	if k <= 0 || len(ids) == 0 {
+		return &ivfIndex{}
+	}
+	if k > len(ids) {
+		k = len(ids)
+	}
+
+	dim := len(chunks[ids[0]].vector())
+	centroids := make([][]float32, k)
+	step := len(ids) / k
+	for i := 0; i < k; i++ {
+		idx := i * step
+		if idx >= len(ids) {
+			idx = len(ids) - 1
+		}
+		centroids[i] = append([]float32(nil), chunks[ids[idx]].vector()...)
+	}
+
+	assign := make(map[string]int, len(ids))
+	for iter := 0; iter < kmeansIterations; iter++ {
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		for i := range sums {
+			sums[i] = make([]float64, dim)
+		}
+
+		for _, id := range ids {
+			vec := chunks[id].vector()
+			best, bestDist := 0, math.MaxFloat64
+			for c, centroid := range centroids {
+				if d := sqDist(vec, centroid); d < bestDist {
+					bestDist, best = d, c
+				}
+			}
+			assign[id] = best
+			counts[best]++
+			for i, x := range vec {
+				sums[best][i] += float64(x)
+			}
+		}
+
+		for c := range centroids {
+			if counts[c] == 0 {
+				continue // no members landed here this pass - leave the centroid in place
+			}
+			updated := make([]float32, dim)
+			for i := range updated {
+				updated[i] = float32(sums[c][i] / float64(counts[c]))
+			}
+			centroids[c] = updated
+		}
+	}
+
+	members := make([][]string, k)
+	for id, c := range assign {
+		members[c] = append(members[c], id)
+	}
+
+	return &ivfIndex{centroids: centroids, members: members}
+}
+
+// sqDist returns the squared Euclidean distance between two equal-length
+// vectors. Only used to rank centroids/points against each other, so
+// skipping the square root costs nothing.
+func sqDist(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i] - b[i])
+		sum += d * d
+	}
+	return sum
+}
+
+// nearestClusters returns up to probes cluster indices, ordered by centroid
+// proximity to query.
+func (idx *ivfIndex) nearestClusters(query []float32, probes int) []int {
+	type ranked struct {
+		cluster int
+		dist    float64
+	}
+	ranks := make([]ranked, len(idx.centroids))
+	for i, c := range idx.centroids {
+		ranks[i] = ranked{i, sqDist(query, c)}
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i].dist < ranks[j].dist })
+
+	if probes > len(ranks) {
+		probes = len(ranks)
+	}
+	clusters := make([]int, probes)
+	for i := 0; i < probes; i++ {
+		clusters[i] = ranks[i].cluster
+	}
+	return clusters
+}
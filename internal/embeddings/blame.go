@@ -0,0 +1,100 @@
+package embeddings
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// blameLine is one line's authorship as reported by `git blame`.
+type blameLine struct {
+	author string
+	date   time.Time
+	commit string
+}
+
+// blameFile runs `git blame --line-porcelain` over filePath and returns
+// per-line authorship keyed by 1-based line number. Returns nil if filePath
+// isn't tracked in a git repo, has no history yet, or git isn't available -
+// callers should treat that as "no blame data" rather than an error, since
+// blame annotation is optional.
+func blameFile(filePath string) map[int]blameLine {
+	dir := filepath.Dir(filePath)
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", filepath.Base(filePath))
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	lines := make(map[int]blameLine)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var sha, author string
+	var authorTime int64
+	var finalLine int
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			if finalLine > 0 {
+				lines[finalLine] = blameLine{author: author, date: time.Unix(authorTime, 0), commit: sha}
+			}
+		case strings.HasPrefix(line, "author "):
+			author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			authorTime, _ = strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64)
+		default:
+			if fields := strings.Fields(line); len(fields) >= 3 && isBlameSHA(fields[0]) {
+				sha = fields[0]
+				finalLine, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+	return lines
+}
+
+// mostRecentBlame returns whichever line in [startLine, endLine] (1-based,
+// inclusive) was authored most recently, or ok=false if lines has no entry
+// in that range.
+func mostRecentBlame(lines map[int]blameLine, startLine, endLine int) (blameLine, bool) {
+	var best blameLine
+	found := false
+	for ln := startLine; ln <= endLine; ln++ {
+		bl, ok := lines[ln]
+		if !ok {
+			continue
+		}
+		if !found || bl.date.After(best.date) {
+			best = bl
+			found = true
+		}
+	}
+	return best, found
+}
+
+// isBlameSHA reports whether s looks like a git blame commit header's SHA -
+// hex digits, at least 7 of them (git blame's header always uses the full
+// 40-character SHA, but this stays lenient rather than hardcoding that).
+func isBlameSHA(s string) bool {
+	if len(s) < 7 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -2,30 +2,36 @@ package embeddings
 
 import (
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
+
+	"github.com/jake/gocode/internal/context"
+	"github.com/jake/gocode/internal/langdetect"
 )
 
 // Chunk represents a piece of text with metadata
 type Chunk struct {
-	Text     string            // The actual text content
-	FilePath string            // Source file path
-	StartLine int              // Starting line number
-	EndLine   int              // Ending line number
+	Text      string            // The actual text content
+	FilePath  string            // Source file path
+	StartLine int               // Starting line number
+	EndLine   int               // Ending line number
 	Metadata  map[string]string // Additional metadata
 }
 
 // ChunkerConfig holds configuration for text chunking
 type ChunkerConfig struct {
-	MaxChunkSize    int  // Maximum characters per chunk
-	OverlapSize     int  // Characters to overlap between chunks
+	MaxChunkTokens    int  // Maximum estimated tokens per chunk
+	OverlapTokens     int  // Estimated tokens to overlap between chunks
 	RespectCodeBlocks bool // Don't split inside code blocks
+	AnnotateBlame     bool // Add "blame_author"/"blame_date"/"blame_commit" metadata from git blame
 }
 
 // DefaultChunkerConfig returns sensible defaults for code chunking
 func DefaultChunkerConfig() ChunkerConfig {
 	return ChunkerConfig{
-		MaxChunkSize:    512,   // ~128 tokens for typical code
-		OverlapSize:     64,    // ~16 tokens overlap
+		MaxChunkTokens:    128,
+		OverlapTokens:     16,
 		RespectCodeBlocks: true,
 	}
 }
@@ -40,77 +46,157 @@ func NewChunker(config ChunkerConfig) *Chunker {
 	return &Chunker{config: config}
 }
 
-// ChunkFile splits a file's content into chunks
+// segment is one physical line, or (for a line too long to ever fit in a
+// chunk on its own, e.g. a minified JS bundle) one piece of a line split by
+// splitOverlongLine. lineNo tracks the originating physical line so
+// StartLine/EndLine still reflect real file lines.
+type segment struct {
+	text   string
+	lineNo int
+}
+
+// ChunkFile splits a file's content into chunks, sized by estimated token
+// count rather than raw characters so chunk boundaries track what actually
+// fills the embedding model's input regardless of average line length.
 func (c *Chunker) ChunkFile(filePath string, content string) []Chunk {
 	lines := strings.Split(content, "\n")
-	chunks := []Chunk{}
 
+	// One `git blame` invocation per file rather than per chunk - flush
+	// below just looks up whichever line in its range was touched most
+	// recently. nil (not in a git repo, no history yet) is a valid result;
+	// every lookup against it simply misses.
+	var blame map[int]blameLine
+	if c.config.AnnotateBlame {
+		blame = blameFile(filePath)
+	}
+
+	var segments []segment
+	for i, line := range lines {
+		if context.EstimateTokens(line) > c.config.MaxChunkTokens {
+			for _, piece := range splitOverlongLine(line, c.config.MaxChunkTokens) {
+				segments = append(segments, segment{text: piece, lineNo: i})
+			}
+			continue
+		}
+		segments = append(segments, segment{text: line, lineNo: i})
+	}
+
+	chunks := []Chunk{}
 	currentChunk := strings.Builder{}
+	currentTokens := 0
 	currentStartLine := 0
 	currentLine := 0
 
-	for i, line := range lines {
-		lineLen := len(line) + 1 // +1 for newline
-
-		// Check if adding this line would exceed max chunk size
-		if currentChunk.Len()+lineLen > c.config.MaxChunkSize && currentChunk.Len() > 0 {
-			// Save current chunk
-			chunks = append(chunks, Chunk{
-				Text:      strings.TrimSpace(currentChunk.String()),
-				FilePath:  filePath,
-				StartLine: currentStartLine,
-				EndLine:   currentLine,
-				Metadata:  c.extractMetadata(currentChunk.String()),
-			})
+	flush := func() {
+		if currentChunk.Len() == 0 {
+			return
+		}
+		metadata := c.extractMetadata(currentChunk.String(), filePath)
+		if blame != nil {
+			// StartLine/EndLine are 0-based; git blame numbers lines from 1.
+			if bl, ok := mostRecentBlame(blame, currentStartLine+1, currentLine+1); ok {
+				metadata["blame_author"] = bl.author
+				metadata["blame_date"] = bl.date.Format(time.RFC3339)
+				metadata["blame_commit"] = bl.commit
+			}
+		}
+		chunks = append(chunks, Chunk{
+			Text:      strings.TrimSpace(currentChunk.String()),
+			FilePath:  filePath,
+			StartLine: currentStartLine,
+			EndLine:   currentLine,
+			Metadata:  metadata,
+		})
+	}
+
+	for i, seg := range segments {
+		segTokens := context.EstimateTokens(seg.text)
+
+		if currentTokens+segTokens > c.config.MaxChunkTokens && currentChunk.Len() > 0 {
+			flush()
 
 			// Start new chunk with overlap
 			currentChunk.Reset()
-			overlapStart := max(0, i-c.calculateOverlapLines(lines, i))
+			currentTokens = 0
+			overlapStart := max(0, i-c.calculateOverlapSegments(segments, i))
 			for j := overlapStart; j < i; j++ {
-				currentChunk.WriteString(lines[j])
+				currentChunk.WriteString(segments[j].text)
 				currentChunk.WriteString("\n")
+				currentTokens += context.EstimateTokens(segments[j].text)
 			}
-			currentStartLine = overlapStart
+			currentStartLine = segments[overlapStart].lineNo
 		}
 
-		currentChunk.WriteString(line)
+		currentChunk.WriteString(seg.text)
 		currentChunk.WriteString("\n")
-		currentLine = i
+		currentTokens += segTokens
+		currentLine = seg.lineNo
 	}
 
-	// Add final chunk if not empty
-	if currentChunk.Len() > 0 {
-		chunks = append(chunks, Chunk{
-			Text:      strings.TrimSpace(currentChunk.String()),
-			FilePath:  filePath,
-			StartLine: currentStartLine,
-			EndLine:   currentLine,
-			Metadata:  c.extractMetadata(currentChunk.String()),
-		})
+	flush()
+	return chunks
+}
+
+// calculateOverlapSegments determines how many trailing segments to carry
+// into the next chunk so consecutive chunks overlap by roughly
+// OverlapTokens, without ever pulling in the segment currently being
+// flushed (which starts the next chunk on its own).
+func (c *Chunker) calculateOverlapSegments(segments []segment, currentIdx int) int {
+	overlapTokens := 0
+	overlapCount := 0
+
+	for i := currentIdx - 1; i >= 0 && overlapTokens < c.config.OverlapTokens; i-- {
+		overlapTokens += context.EstimateTokens(segments[i].text)
+		overlapCount++
 	}
 
-	return chunks
+	return overlapCount
 }
 
-// calculateOverlapLines determines how many lines to include for overlap
-func (c *Chunker) calculateOverlapLines(lines []string, currentIdx int) int {
-	overlapChars := 0
-	overlapLines := 0
+// splitOverlongLine breaks a single line that alone exceeds maxTokens (e.g.
+// a minified JS bundle on one line) into rune-safe pieces that each fit,
+// so ChunkFile never emits a chunk larger than the caller's budget just
+// because the source had no line breaks to chunk on.
+func splitOverlongLine(line string, maxTokens int) []string {
+	if line == "" {
+		return []string{""}
+	}
 
-	for i := currentIdx - 1; i >= 0 && overlapChars < c.config.OverlapSize; i-- {
-		overlapChars += len(lines[i]) + 1
-		overlapLines++
+	// Invert EstimateTokens' chars-per-token ratio to get a byte budget per
+	// piece (EstimateTokens counts bytes, so multi-byte runes cost more),
+	// biased down slightly so re-estimating the piece doesn't tip back over
+	// maxTokens due to rounding. Pieces are cut on rune boundaries.
+	maxBytes := maxTokens * 35 / 10
+	if maxBytes < 1 {
+		maxBytes = 1
 	}
 
-	return overlapLines
+	var pieces []string
+	for len(line) > 0 {
+		n := 0
+		for n < len(line) {
+			_, size := utf8.DecodeRuneInString(line[n:])
+			if n > 0 && n+size > maxBytes {
+				break
+			}
+			n += size
+		}
+		pieces = append(pieces, line[:n])
+		line = line[n:]
+	}
+	return pieces
 }
 
-// extractMetadata extracts useful metadata from chunk text
-func (c *Chunker) extractMetadata(text string) map[string]string {
+// extractMetadata extracts useful metadata from chunk text. filePath is
+// used for language detection (extension, then filename/shebang); the
+// content-based fallback below only fires when that's inconclusive, e.g.
+// for a chunk pulled from an extensionless file with no shebang line.
+func (c *Chunker) extractMetadata(text string, filePath string) map[string]string {
 	metadata := make(map[string]string)
 
-	// Detect language (simple heuristic)
-	if strings.Contains(text, "func ") && strings.Contains(text, "package ") {
+	if lang := langdetect.Detect(filePath, text); lang != "unknown" {
+		metadata["language"] = lang
+	} else if strings.Contains(text, "func ") && strings.Contains(text, "package ") {
 		metadata["language"] = "go"
 	} else if strings.Contains(text, "def ") && strings.Contains(text, "import ") {
 		metadata["language"] = "python"
@@ -172,7 +258,7 @@ func (c *Chunker) ChunkText(text string) []string {
 
 	words := strings.Fields(text)
 	for _, word := range words {
-		if currentChunk.Len()+len(word)+1 > c.config.MaxChunkSize && currentChunk.Len() > 0 {
+		if context.EstimateTokens(currentChunk.String())+context.EstimateTokens(word) > c.config.MaxChunkTokens && currentChunk.Len() > 0 {
 			chunks = append(chunks, strings.TrimSpace(currentChunk.String()))
 			currentChunk.Reset()
 		}
@@ -9,8 +9,39 @@ import (
 	"net/http"
 )
 
-// Client handles communication with a local embedding model server
-// Expected to be compatible with llama.cpp embedding server
+// Embedder generates embedding vectors for text. It abstracts over the
+// handful of local/remote embedding server APIs users actually run:
+// llama-server's /embedding, Ollama's /api/embeddings, and OpenAI-compatible
+// /v1/embeddings. Manager only ever talks to this interface, so adding a
+// new backend never touches Manager.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+	Dimension() int
+	Health(ctx context.Context) error
+}
+
+// NewEmbedder builds the Embedder for the given backend ("llamacpp",
+// "ollama", or "openai"; empty defaults to "llamacpp" for backward
+// compatibility with configs predating this option). model is required by
+// "ollama" and "openai" (which serve multiple models behind one endpoint)
+// and ignored by "llamacpp" (whose server has exactly one model loaded).
+// apiKey is only used by "openai".
+func NewEmbedder(backend, endpoint string, dimension int, model, apiKey string) (Embedder, error) {
+	switch backend {
+	case "", "llamacpp":
+		return NewClient(endpoint, dimension), nil
+	case "ollama":
+		return NewOllamaClient(endpoint, model, dimension), nil
+	case "openai":
+		return NewOpenAIClient(endpoint, apiKey, model, dimension), nil
+	default:
+		return nil, fmt.Errorf("unknown embeddings backend %q (want \"llamacpp\", \"ollama\", or \"openai\")", backend)
+	}
+}
+
+// Client handles communication with a local embedding model server.
+// Expected to be compatible with llama.cpp's embedding server.
 type Client struct {
 	endpoint   string
 	httpClient *http.Client
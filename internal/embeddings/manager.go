@@ -2,40 +2,55 @@ package embeddings
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/jake/gocode/internal/secrets"
 )
 
 // Manager coordinates embedding operations
 type Manager struct {
-	client      *Client
+	client      Embedder
 	vectorStore *VectorStore
 	chunker     *Chunker
 }
 
 // Config holds configuration for the embeddings manager
 type Config struct {
-	EmbeddingEndpoint string // Local embedding server endpoint
+	EmbeddingBackend  string // "llamacpp" (default), "ollama", or "openai" - see NewEmbedder
+	EmbeddingEndpoint string // Embedding server endpoint
+	EmbeddingAPIKey   string // Only used by the "openai" backend
+	EmbeddingModel    string // Model name; required by "ollama"/"openai", ignored by "llamacpp"
 	EmbeddingDim      int    // Embedding dimension
 	VectorDBPath      string // Path to SQLite vector database
 	ChunkerConfig     ChunkerConfig
+	ANN               ANNConfig // Quantized storage + approximate search; zero value means exact brute-force
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() Config {
 	return Config{
+		EmbeddingBackend:  "llamacpp",
 		EmbeddingEndpoint: "http://localhost:8081", // Separate from LLM server
 		EmbeddingDim:      384,                     // Common for nomic-embed-text
 		VectorDBPath:      "embeddings.db",
 		ChunkerConfig:     DefaultChunkerConfig(),
+		ANN:               DefaultANNConfig(),
 	}
 }
 
 // NewManager creates a new embeddings manager
 func NewManager(config Config) (*Manager, error) {
-	client := NewClient(config.EmbeddingEndpoint, config.EmbeddingDim)
+	client, err := NewEmbedder(config.EmbeddingBackend, config.EmbeddingEndpoint, config.EmbeddingDim, config.EmbeddingModel, config.EmbeddingAPIKey)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if embedding server is available
 	ctx := context.Background()
@@ -43,7 +58,7 @@ func NewManager(config Config) (*Manager, error) {
 		return nil, fmt.Errorf("embedding server not available at %s: %w", config.EmbeddingEndpoint, err)
 	}
 
-	vectorStore, err := NewVectorStore(config.VectorDBPath, config.EmbeddingDim)
+	vectorStore, err := NewVectorStore(config.VectorDBPath, config.EmbeddingDim, config.ANN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vector store: %w", err)
 	}
@@ -57,6 +72,11 @@ func NewManager(config Config) (*Manager, error) {
 	}, nil
 }
 
+// indexWorkers bounds how many files IndexDirectory processes concurrently,
+// so a large repo doesn't open more connections to the embedding server than
+// it can actually handle at once.
+const indexWorkers = 4
+
 // IndexFile processes and indexes a file
 func (m *Manager) IndexFile(ctx context.Context, filePath string) error {
 	// Read file content
@@ -65,11 +85,18 @@ func (m *Manager) IndexFile(ctx context.Context, filePath string) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Delete existing chunks for this file
+	// Delete existing chunks for this file - done even when the file turns
+	// out to be a secret below, so a file that becomes one after already
+	// being indexed gets its old (non-secret) chunks purged rather than
+	// left stale, and the caller doesn't need special-case cleanup logic.
 	if err := m.vectorStore.DeleteByFilePath(filePath); err != nil {
 		return fmt.Errorf("failed to delete existing chunks: %w", err)
 	}
 
+	if skip, _ := secrets.ShouldSkip(filePath, string(content)); skip {
+		return nil // credential-shaped file/content - never embedded
+	}
+
 	// Chunk the file
 	chunks := m.chunker.ChunkFile(filePath, string(content))
 	if len(chunks) == 0 {
@@ -96,37 +123,222 @@ func (m *Manager) IndexFile(ctx context.Context, filePath string) error {
 	return nil
 }
 
-// IndexDirectory recursively indexes all files in a directory
-func (m *Manager) IndexDirectory(ctx context.Context, dirPath string) error {
-	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// discoverCandidates walks dirPath and returns every file IndexDirectory
+// would consider indexing (code file, not a lockfile/generated/secret file,
+// under the size cap), shared with Status so the two report on exactly the
+// same set of files.
+func discoverCandidates(dirPath string) (paths []string, infos []os.FileInfo, err error) {
+	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
 		}
 
-		// Skip directories and non-code files
 		if info.IsDir() {
-			// Skip common directories
 			dirName := filepath.Base(path)
 			if dirName == ".git" || dirName == "node_modules" || dirName == "vendor" ||
-			   dirName == ".gocode" || dirName == "logs" {
+				dirName == ".gocode" || dirName == "logs" {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Only index code files
 		if !isCodeFile(path) {
 			return nil
 		}
 
+		if isLockfileOrGenerated(path) {
+			return nil
+		}
+
+		if secrets.LooksLikeSecretFile(path) {
+			return nil
+		}
+
 		// Skip large files (> 1MB)
 		if info.Size() > 1024*1024 {
 			return nil
 		}
 
-		fmt.Printf("Indexing: %s\n", path)
-		return m.IndexFile(ctx, path)
+		paths = append(paths, path)
+		infos = append(infos, info)
+		return nil
 	})
+	return paths, infos, err
+}
+
+// contentHash returns a hex-encoded sha256 of content, used to tell whether
+// a file's content actually changed since it was last indexed, independent
+// of its mod_time (which can move without the content changing, e.g. after
+// a git checkout or a plain touch).
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// IndexDirectory recursively indexes all files in a directory. Files whose
+// mod_time or content hash haven't changed since they were last indexed are
+// skipped, so an interrupted run can simply be re-invoked to resume where
+// it left off, and a mod_time bump with no real content change (e.g. a git
+// checkout) doesn't trigger a needless re-embedding. Remaining files are
+// processed by a small worker pool, with progress and an ETA printed as it
+// goes.
+func (m *Manager) IndexDirectory(ctx context.Context, dirPath string) error {
+	paths, infos, err := discoverCandidates(dirPath)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	hashes := make(map[string]string) // path -> freshly computed content hash, for files that need (re-)indexing
+	for i, path := range paths {
+		modTime := infos[i].ModTime().Unix()
+		done, err := m.vectorStore.IsFileIndexed(path, modTime)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			continue // file disappeared/became unreadable since the walk; the worker's own stat below would skip it too
+		}
+		hash := contentHash(content)
+
+		_, storedHash, ok, err := m.vectorStore.FileIndexRecord(path)
+		if err != nil {
+			return err
+		}
+		if ok && storedHash == hash {
+			// Content is unchanged despite a newer mod_time - bump the
+			// recorded mod_time so IsFileIndexed's fast path catches it
+			// next run, but skip the unnecessary re-embedding.
+			if err := m.vectorStore.MarkFileIndexed(path, modTime, hash); err != nil {
+				return err
+			}
+			continue
+		}
+
+		hashes[path] = hash
+		pending = append(pending, path)
+	}
+
+	skipped := len(paths) - len(pending)
+	if skipped > 0 {
+		fmt.Printf("Resuming: %d file(s) already indexed, %d remaining\n", skipped, len(pending))
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var (
+		mu        sync.Mutex
+		completed int
+		firstErr  error
+		start     = time.Now()
+	)
+	jobs := make(chan string)
+
+	var wg sync.WaitGroup
+	for w := 0; w < indexWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				info, statErr := os.Stat(path)
+				if statErr != nil {
+					continue // file disappeared since the walk; skip it
+				}
+
+				indexErr := m.IndexFile(ctx, path)
+
+				mu.Lock()
+				if indexErr != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to index %s: %w", path, indexErr)
+					}
+				} else if markErr := m.vectorStore.MarkFileIndexed(path, info.ModTime().Unix(), hashes[path]); markErr != nil {
+					if firstErr == nil {
+						firstErr = markErr
+					}
+				}
+				completed++
+				elapsed := time.Since(start)
+				eta := time.Duration(float64(elapsed) / float64(completed) * float64(len(pending)-completed))
+				fmt.Printf("Indexing [%d/%d, ETA %s]: %s\n", completed, len(pending), eta.Round(time.Second), path)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, path := range pending {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
+
+// IndexStatus summarizes how up to date the vector store's index is against
+// the current state of a directory, for the /index status command.
+type IndexStatus struct {
+	TotalFiles   int      // eligible files found in the tree
+	UpToDate     int      // indexed, with a mod_time or content hash matching the current file
+	Stale        []string // indexed before, but content has since changed
+	NeverIndexed []string // never indexed at all
+}
+
+// Status reports IndexDirectory's view of dirPath without changing
+// anything - which files would be (re-)indexed by a Refresh, and which are
+// already current.
+func (m *Manager) Status(dirPath string) (IndexStatus, error) {
+	paths, infos, err := discoverCandidates(dirPath)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	status := IndexStatus{TotalFiles: len(paths)}
+	for i, path := range paths {
+		modTime := infos[i].ModTime().Unix()
+
+		_, storedHash, ok, err := m.vectorStore.FileIndexRecord(path)
+		if err != nil {
+			return IndexStatus{}, err
+		}
+		if !ok {
+			status.NeverIndexed = append(status.NeverIndexed, path)
+			continue
+		}
+
+		if done, err := m.vectorStore.IsFileIndexed(path, modTime); err != nil {
+			return IndexStatus{}, err
+		} else if done {
+			status.UpToDate++
+			continue
+		}
+
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			status.Stale = append(status.Stale, path) // can't confirm unchanged - report it and let Refresh sort it out
+			continue
+		}
+		if contentHash(content) == storedHash {
+			status.UpToDate++
+		} else {
+			status.Stale = append(status.Stale, path)
+		}
+	}
+
+	return status, nil
+}
+
+// Refresh re-indexes dirPath, which is just IndexDirectory under a name
+// that matches the /index refresh command - it already skips anything
+// Status would report as up to date.
+func (m *Manager) Refresh(ctx context.Context, dirPath string) error {
+	return m.IndexDirectory(ctx, dirPath)
 }
 
 // Search searches for semantically similar code
@@ -163,32 +375,75 @@ func (m *Manager) Close() error {
 func isCodeFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 	codeExtensions := map[string]bool{
-		".go":   true,
-		".py":   true,
-		".js":   true,
-		".ts":   true,
-		".tsx":  true,
-		".jsx":  true,
-		".java": true,
-		".c":    true,
-		".cpp":  true,
-		".h":    true,
-		".hpp":  true,
-		".rs":   true,
-		".rb":   true,
-		".php":  true,
-		".cs":   true,
+		".go":    true,
+		".py":    true,
+		".js":    true,
+		".ts":    true,
+		".tsx":   true,
+		".jsx":   true,
+		".java":  true,
+		".c":     true,
+		".cpp":   true,
+		".h":     true,
+		".hpp":   true,
+		".rs":    true,
+		".rb":    true,
+		".php":   true,
+		".cs":    true,
 		".swift": true,
-		".kt":   true,
+		".kt":    true,
 		".scala": true,
-		".sql":  true,
-		".sh":   true,
-		".bash": true,
-		".yaml": true,
-		".yml":  true,
-		".json": true,
-		".xml":  true,
-		".md":   true,
+		".sql":   true,
+		".sh":    true,
+		".bash":  true,
+		".yaml":  true,
+		".yml":   true,
+		".json":  true,
+		".xml":   true,
+		".md":    true,
 	}
 	return codeExtensions[ext]
 }
+
+// lockfileNames are dependency lockfiles: high-term-frequency, low-signal
+// for retrieval since they're mechanically generated and re-list every
+// transitive dependency name on nearly every query.
+var lockfileNames = map[string]bool{
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"go.sum":            true,
+	"cargo.lock":        true,
+	"composer.lock":     true,
+	"gemfile.lock":      true,
+	"poetry.lock":       true,
+}
+
+// generatedSuffixes catches generated code and build output by filename
+// pattern - protobuf/gRPC stubs and minified/bundled assets read as dense
+// token soup that outranks the hand-written source a query is usually
+// actually after.
+var generatedSuffixes = []string{
+	"_pb.go", ".pb.go", ".pb.gw.go", "_pb2.py",
+	".min.js", ".min.css", ".bundle.js",
+}
+
+// isLockfileOrGenerated reports whether path is a dependency lockfile or
+// generated/build artifact that IndexDirectory should skip entirely.
+func isLockfileOrGenerated(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	if lockfileNames[base] {
+		return true
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(path), "/") {
+		if dir == "dist" || dir == "build" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OllamaClient talks to an Ollama server's /api/embeddings endpoint.
+type OllamaClient struct {
+	endpoint   string
+	model      string
+	httpClient *http.Client
+	dimension  int
+}
+
+// ollamaEmbedRequest is Ollama's /api/embeddings request body.
+type ollamaEmbedRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// ollamaEmbedResponse is Ollama's /api/embeddings response body.
+type ollamaEmbedResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+// NewOllamaClient creates a new Ollama embedding client
+func NewOllamaClient(endpoint, model string, dimension int) *OllamaClient {
+	return &OllamaClient{
+		endpoint:   endpoint,
+		model:      model,
+		httpClient: &http.Client{},
+		dimension:  dimension,
+	}
+}
+
+// Embed generates an embedding vector for the given text
+func (c *OllamaClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	jsonData, err := json.Marshal(ollamaEmbedRequest{Model: c.model, Prompt: text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/api/embeddings", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embedding request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embResp ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(embResp.Embedding) != c.dimension {
+		return nil, fmt.Errorf("unexpected embedding dimension: got %d, expected %d", len(embResp.Embedding), c.dimension)
+	}
+
+	return embResp.Embedding, nil
+}
+
+// EmbedBatch generates embeddings for multiple texts. Ollama's
+// /api/embeddings takes one prompt per request, so this just calls Embed
+// sequentially - the same approach Client.EmbedBatch takes.
+func (c *OllamaClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		emb, err := c.Embed(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed text %d: %w", i, err)
+		}
+		embeddings[i] = emb
+	}
+	return embeddings, nil
+}
+
+// Dimension returns the embedding dimension
+func (c *OllamaClient) Dimension() int {
+	return c.dimension
+}
+
+// Health checks if the Ollama server is reachable
+func (c *OllamaClient) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health check request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
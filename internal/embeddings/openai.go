@@ -0,0 +1,135 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OpenAIClient talks to an OpenAI-compatible /v1/embeddings endpoint (the
+// real OpenAI API, or a local server like vLLM/LiteLLM that mimics it).
+type OpenAIClient struct {
+	endpoint   string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	dimension  int
+}
+
+// openaiEmbedRequest is the OpenAI /v1/embeddings request body. Input takes
+// a batch directly, so EmbedBatch doesn't need to fan out one request per
+// text the way the llamacpp/ollama backends do.
+type openaiEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// openaiEmbedResponse is the OpenAI /v1/embeddings response body.
+type openaiEmbedResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+}
+
+// NewOpenAIClient creates a new OpenAI-compatible embedding client
+func NewOpenAIClient(endpoint, apiKey, model string, dimension int) *OpenAIClient {
+	return &OpenAIClient{
+		endpoint:   endpoint,
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{},
+		dimension:  dimension,
+	}
+}
+
+// Embed generates an embedding vector for the given text
+func (c *OpenAIClient) Embed(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := c.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts in a single request
+func (c *OpenAIClient) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	jsonData, err := json.Marshal(openaiEmbedRequest{Model: c.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/v1/embeddings", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embedding request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embResp openaiEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(embResp.Data) != len(texts) {
+		return nil, fmt.Errorf("unexpected embedding count: got %d, expected %d", len(embResp.Data), len(texts))
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, d := range embResp.Data {
+		if d.Index < 0 || d.Index >= len(embeddings) {
+			return nil, fmt.Errorf("embedding response index %d out of range", d.Index)
+		}
+		if len(d.Embedding) != c.dimension {
+			return nil, fmt.Errorf("unexpected embedding dimension: got %d, expected %d", len(d.Embedding), c.dimension)
+		}
+		embeddings[d.Index] = d.Embedding
+	}
+
+	return embeddings, nil
+}
+
+// Dimension returns the embedding dimension
+func (c *OpenAIClient) Dimension() int {
+	return c.dimension
+}
+
+// Health checks if the endpoint is reachable by listing models, the one
+// GET endpoint essentially every OpenAI-compatible server implements.
+func (c *OpenAIClient) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint+"/v1/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health check request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
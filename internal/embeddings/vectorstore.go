@@ -17,26 +17,46 @@ type VectorStore struct {
 	mu         sync.RWMutex
 	inMemIndex map[string]*IndexedChunk // For fast similarity search
 	dimension  int
+	annConfig  ANNConfig
+	ann        *ivfIndex // built lazily; nil until enough chunks exist to be worth it
+	annDirty   bool      // true when inMemIndex changed since ann was built
 }
 
-// IndexedChunk represents a chunk with its embedding in memory
+// IndexedChunk represents a chunk with its embedding in memory. When the
+// store's ANNConfig is disabled, Embedding holds the full-precision vector
+// and Quantized/Scale are unused. When ANN is enabled, only the int8
+// quantization is kept in memory (Embedding is nil) so a large store's RAM
+// footprint stays bounded.
 type IndexedChunk struct {
 	ID        string
 	Chunk     Chunk
 	Embedding []float32
+	Quantized []int8
+	Scale     float32
+}
+
+// vector returns the chunk's embedding at whatever precision it's stored,
+// dequantizing on the fly if only the int8 form is kept.
+func (ic *IndexedChunk) vector() []float32 {
+	if ic.Embedding != nil {
+		return ic.Embedding
+	}
+	return dequantizeInt8(ic.Quantized, ic.Scale)
 }
 
 // SearchResult represents a search result with score
 type SearchResult struct {
-	Chunk      Chunk
-	Score      float32
-	FilePath   string
-	StartLine  int
-	EndLine    int
+	Chunk     Chunk
+	Score     float32
+	FilePath  string
+	StartLine int
+	EndLine   int
 }
 
-// NewVectorStore creates a new vector store backed by SQLite
-func NewVectorStore(dbPath string, dimension int) (*VectorStore, error) {
+// NewVectorStore creates a new vector store backed by SQLite. annConfig
+// controls whether Search does an exact brute-force scan (the zero value)
+// or quantizes embeddings and searches an IVF index instead.
+func NewVectorStore(dbPath string, dimension int, annConfig ANNConfig) (*VectorStore, error) {
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -52,31 +72,83 @@ func NewVectorStore(dbPath string, dimension int) (*VectorStore, error) {
 		text TEXT NOT NULL,
 		metadata TEXT,
 		embedding BLOB NOT NULL,
+		embedding_q8 BLOB,
+		embedding_scale REAL,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_file_path ON chunks(file_path);
 	CREATE INDEX IF NOT EXISTS idx_created_at ON chunks(created_at);
+
+	CREATE TABLE IF NOT EXISTS indexed_files (
+		file_path TEXT PRIMARY KEY,
+		mod_time INTEGER NOT NULL,
+		content_hash TEXT NOT NULL DEFAULT '',
+		indexed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 	`
 
 	if _, err := db.Exec(schema); err != nil {
 		return nil, fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	// A store created before ANN support won't have these columns yet -
+	// add them in place rather than requiring a fresh database.
+	if err := ensureColumn(db, "chunks", "embedding_q8", "BLOB"); err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+	if err := ensureColumn(db, "chunks", "embedding_scale", "REAL"); err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+	if err := ensureColumn(db, "indexed_files", "content_hash", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
 	vs := &VectorStore{
 		db:         db,
 		inMemIndex: make(map[string]*IndexedChunk),
 		dimension:  dimension,
+		annConfig:  annConfig,
 	}
 
 	// Load existing chunks into memory
 	if err := vs.loadIndex(); err != nil {
 		return nil, fmt.Errorf("failed to load index: %w", err)
 	}
+	vs.annDirty = true
 
 	return vs, nil
 }
 
+// ensureColumn adds column to table if it doesn't already exist, so
+// upgrading a store created before a column was introduced doesn't require
+// recreating the database.
+func ensureColumn(db *sql.DB, table, column, ddlType string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, ctype string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dflt, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, ddlType))
+	return err
+}
+
 // Add stores a chunk with its embedding
 func (vs *VectorStore) Add(id string, chunk Chunk, embedding []float32) error {
 	if len(embedding) != vs.dimension {
@@ -92,28 +164,47 @@ func (vs *VectorStore) Add(id string, chunk Chunk, embedding []float32) error {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	// Serialize embedding
+	// Serialize embedding. The full-precision blob is always persisted, even
+	// in ANN mode, so switching ann.enabled off later doesn't strand chunks
+	// without a usable embedding for exact search.
 	embeddingJSON, err := json.Marshal(embedding)
 	if err != nil {
 		return fmt.Errorf("failed to marshal embedding: %w", err)
 	}
 
+	var quantized []int8
+	var scale float32
+	var quantizedJSON []byte
+	if vs.annConfig.Enabled {
+		quantized, scale = quantizeInt8(embedding)
+		quantizedJSON, err = json.Marshal(quantized)
+		if err != nil {
+			return fmt.Errorf("failed to marshal quantized embedding: %w", err)
+		}
+	}
+
 	// Insert into database
 	_, err = vs.db.Exec(`
-		INSERT OR REPLACE INTO chunks (id, file_path, start_line, end_line, text, metadata, embedding)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, id, chunk.FilePath, chunk.StartLine, chunk.EndLine, chunk.Text, string(metadataJSON), embeddingJSON)
+		INSERT OR REPLACE INTO chunks (id, file_path, start_line, end_line, text, metadata, embedding, embedding_q8, embedding_scale)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, id, chunk.FilePath, chunk.StartLine, chunk.EndLine, chunk.Text, string(metadataJSON), embeddingJSON, quantizedJSON, scale)
 
 	if err != nil {
 		return fmt.Errorf("failed to insert chunk: %w", err)
 	}
 
-	// Update in-memory index
-	vs.inMemIndex[id] = &IndexedChunk{
-		ID:        id,
-		Chunk:     chunk,
-		Embedding: embedding,
+	// Update in-memory index. In ANN mode only the int8 quantization is kept
+	// resident, which is the whole point of quantizing in the first place;
+	// otherwise the full-precision embedding is kept as before.
+	indexed := &IndexedChunk{ID: id, Chunk: chunk}
+	if vs.annConfig.Enabled {
+		indexed.Quantized = quantized
+		indexed.Scale = scale
+	} else {
+		indexed.Embedding = embedding
 	}
+	vs.inMemIndex[id] = indexed
+	vs.annDirty = true
 
 	return nil
 }
@@ -134,23 +225,31 @@ func (vs *VectorStore) AddBatch(chunks []Chunk, embeddings [][]float32) error {
 	return nil
 }
 
-// Search finds the most similar chunks to the query embedding
+// Search finds the most similar chunks to the query embedding. When the
+// store's ANNConfig is enabled and there are enough chunks to make it
+// worthwhile, this scans an IVF index instead of the whole store; otherwise
+// it falls back to an exact brute-force scan.
 func (vs *VectorStore) Search(queryEmbedding []float32, topK int) ([]SearchResult, error) {
 	if len(queryEmbedding) != vs.dimension {
 		return nil, fmt.Errorf("query embedding dimension mismatch")
 	}
 
+	vs.ensureANN()
+
 	vs.mu.RLock()
 	defer vs.mu.RUnlock()
 
-	// Calculate similarity for all chunks
+	candidates := vs.candidatesLocked(queryEmbedding)
+
+	// Calculate similarity for the candidate set (the whole store, in exact
+	// mode; a handful of IVF clusters otherwise)
 	scores := make([]struct {
 		chunk *IndexedChunk
 		score float32
-	}, 0, len(vs.inMemIndex))
+	}, 0, len(candidates))
 
-	for _, indexedChunk := range vs.inMemIndex {
-		similarity := cosineSimilarity(queryEmbedding, indexedChunk.Embedding)
+	for _, indexedChunk := range candidates {
+		similarity := cosineSimilarity(queryEmbedding, indexedChunk.vector())
 		scores = append(scores, struct {
 			chunk *IndexedChunk
 			score float32
@@ -199,10 +298,127 @@ func (vs *VectorStore) DeleteByFilePath(filePath string) error {
 			delete(vs.inMemIndex, id)
 		}
 	}
+	vs.annDirty = true
 
 	return nil
 }
 
+// annMinChunks is the smallest store size worth building an IVF index for.
+// Below it, a brute-force scan is already fast and an approximate index
+// would just add error for no benefit.
+const annMinChunks = 256
+
+// ensureANN rebuilds the IVF index if the store has changed since it was
+// last built. A no-op when ANN is disabled or the store is too small yet.
+func (vs *VectorStore) ensureANN() {
+	if !vs.annConfig.Enabled {
+		return
+	}
+
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	if !vs.annDirty && vs.ann != nil {
+		return
+	}
+
+	clusters := vs.annConfig.Clusters
+	if clusters <= 0 {
+		clusters = DefaultANNConfig().Clusters
+	}
+
+	if len(vs.inMemIndex) < annMinChunks || len(vs.inMemIndex) < clusters*2 {
+		vs.ann = nil // too few chunks for clustering to pay off - candidatesLocked falls back to a full scan
+		vs.annDirty = false
+		return
+	}
+
+	vs.ann = buildIVFIndex(vs.inMemIndex, clusters)
+	vs.annDirty = false
+}
+
+// candidatesLocked returns the chunks Search should score against query,
+// given the store's current ANN state. Callers must hold vs.mu (for
+// reading).
+func (vs *VectorStore) candidatesLocked(query []float32) []*IndexedChunk {
+	if vs.ann == nil {
+		all := make([]*IndexedChunk, 0, len(vs.inMemIndex))
+		for _, c := range vs.inMemIndex {
+			all = append(all, c)
+		}
+		return all
+	}
+
+	probes := vs.annConfig.Probes
+	if probes <= 0 {
+		probes = DefaultANNConfig().Probes
+	}
+
+	var candidates []*IndexedChunk
+	for _, cluster := range vs.ann.nearestClusters(query, probes) {
+		for _, id := range vs.ann.members[cluster] {
+			if c, ok := vs.inMemIndex[id]; ok {
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	return candidates
+}
+
+// IsFileIndexed reports whether filePath was indexed at (or after) modTime,
+// so a resumed IndexDirectory run can skip files it already finished and
+// only reprocess ones that changed since or were never completed. This is
+// the cheap mod_time-only pre-check; FileIndexRecord additionally returns
+// the recorded content hash for the (rarer) case where mod_time moved but
+// the content may not have.
+func (vs *VectorStore) IsFileIndexed(filePath string, modTime int64) (bool, error) {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	var stored int64
+	err := vs.db.QueryRow("SELECT mod_time FROM indexed_files WHERE file_path = ?", filePath).Scan(&stored)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check indexed_files: %w", err)
+	}
+	return stored >= modTime, nil
+}
+
+// FileIndexRecord returns the mod_time and content hash filePath was last
+// indexed at, and whether a record exists at all.
+func (vs *VectorStore) FileIndexRecord(filePath string) (modTime int64, contentHash string, ok bool, err error) {
+	vs.mu.RLock()
+	defer vs.mu.RUnlock()
+
+	err = vs.db.QueryRow("SELECT mod_time, content_hash FROM indexed_files WHERE file_path = ?", filePath).Scan(&modTime, &contentHash)
+	if err == sql.ErrNoRows {
+		return 0, "", false, nil
+	}
+	if err != nil {
+		return 0, "", false, fmt.Errorf("failed to check indexed_files: %w", err)
+	}
+	return modTime, contentHash, true, nil
+}
+
+// MarkFileIndexed records that filePath finished indexing as of modTime
+// with the given content hash, so a later interrupted run can resume past
+// it, and an unchanged-content re-scan can skip re-embedding it.
+func (vs *VectorStore) MarkFileIndexed(filePath string, modTime int64, contentHash string) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	_, err := vs.db.Exec(`
+		INSERT OR REPLACE INTO indexed_files (file_path, mod_time, content_hash, indexed_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+	`, filePath, modTime, contentHash)
+	if err != nil {
+		return fmt.Errorf("failed to mark file indexed: %w", err)
+	}
+	return nil
+}
+
 // Count returns the total number of chunks
 func (vs *VectorStore) Count() int {
 	vs.mu.RLock()
@@ -210,10 +426,12 @@ func (vs *VectorStore) Count() int {
 	return len(vs.inMemIndex)
 }
 
-// loadIndex loads all chunks from database into memory
+// loadIndex loads all chunks from database into memory. In ANN mode it
+// prefers each row's stored int8 quantization, quantizing on the fly from
+// the full-precision blob for any row indexed before ANN was turned on.
 func (vs *VectorStore) loadIndex() error {
 	rows, err := vs.db.Query(`
-		SELECT id, file_path, start_line, end_line, text, metadata, embedding
+		SELECT id, file_path, start_line, end_line, text, metadata, embedding, embedding_q8, embedding_scale
 		FROM chunks
 	`)
 	if err != nil {
@@ -224,9 +442,10 @@ func (vs *VectorStore) loadIndex() error {
 	for rows.Next() {
 		var id, filePath, text, metadataJSON string
 		var startLine, endLine int
-		var embeddingJSON []byte
+		var embeddingJSON, quantizedJSON []byte
+		var scale sql.NullFloat64
 
-		if err := rows.Scan(&id, &filePath, &startLine, &endLine, &text, &metadataJSON, &embeddingJSON); err != nil {
+		if err := rows.Scan(&id, &filePath, &startLine, &endLine, &text, &metadataJSON, &embeddingJSON, &quantizedJSON, &scale); err != nil {
 			return fmt.Errorf("failed to scan row: %w", err)
 		}
 
@@ -250,11 +469,25 @@ func (vs *VectorStore) loadIndex() error {
 			Metadata:  metadata,
 		}
 
-		vs.inMemIndex[id] = &IndexedChunk{
-			ID:        id,
-			Chunk:     chunk,
-			Embedding: embedding,
+		indexed := &IndexedChunk{ID: id, Chunk: chunk}
+		if vs.annConfig.Enabled {
+			if len(quantizedJSON) > 0 && scale.Valid {
+				var quantized []int8
+				if err := json.Unmarshal(quantizedJSON, &quantized); err != nil {
+					return fmt.Errorf("failed to unmarshal quantized embedding: %w", err)
+				}
+				indexed.Quantized = quantized
+				indexed.Scale = float32(scale.Float64)
+			} else {
+				// Indexed before ANN was enabled - quantize now rather than
+				// waiting for the file to be re-indexed.
+				indexed.Quantized, indexed.Scale = quantizeInt8(embedding)
+			}
+		} else {
+			indexed.Embedding = embedding
 		}
+
+		vs.inMemIndex[id] = indexed
 	}
 
 	return rows.Err()
@@ -0,0 +1,51 @@
+package evaluation
+
+import (
+	"fmt"
+	"io"
+)
+
+// Report summarizes one model profile's run across a task bundle.
+type Report struct {
+	Profile               string   `json:"profile"`
+	PassAt1               float64  `json:"pass_at_1"`
+	TotalPromptTokens     int      `json:"total_prompt_tokens"`
+	TotalCompletionTokens int      `json:"total_completion_tokens"`
+	TotalDurationSeconds  float64  `json:"total_duration_seconds"`
+	Results               []Result `json:"results"`
+}
+
+// BuildReport aggregates a profile's per-task results into pass@1 and cost
+// totals.
+func BuildReport(profile string, results []Result) Report {
+	report := Report{Profile: profile, Results: results}
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+		report.TotalPromptTokens += r.PromptTokens
+		report.TotalCompletionTokens += r.CompletionTokens
+		report.TotalDurationSeconds += r.DurationSeconds
+	}
+	if len(results) > 0 {
+		report.PassAt1 = float64(passed) / float64(len(results))
+	}
+	return report
+}
+
+// WriteComparison renders a Markdown table comparing reports across model
+// profiles, so a maintainer can eyeball a prompt or model change's effect
+// on pass@1 and token cost in one place.
+func WriteComparison(w io.Writer, reports []Report) error {
+	fmt.Fprintln(w, "| Profile | Pass@1 | Tasks | Prompt Tokens | Completion Tokens | Total Time |")
+	fmt.Fprintln(w, "|---|---|---|---|---|---|")
+	for _, r := range reports {
+		_, err := fmt.Fprintf(w, "| %s | %.1f%% | %d | %d | %d | %.1fs |\n",
+			r.Profile, r.PassAt1*100, len(r.Results), r.TotalPromptTokens, r.TotalCompletionTokens, r.TotalDurationSeconds)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
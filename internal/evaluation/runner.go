@@ -0,0 +1,81 @@
+package evaluation
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/jake/gocode/internal/agent"
+	"github.com/jake/gocode/internal/config"
+)
+
+// Result is one task's outcome under one model profile.
+type Result struct {
+	TaskID           string  `json:"task_id"`
+	Profile          string  `json:"profile"`
+	Passed           bool    `json:"passed"`
+	DurationSeconds  float64 `json:"duration_seconds"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// RunTask drives baseCfg's agent one-shot against task.Issue in
+// task.RepoPath, then runs task.TestCommand there to score pass/fail.
+// baseCfg is not mutated - RunTask works on a copy scoped to the task's
+// checkout, with confirmation forced to auto (there's no terminal to
+// approve from) and session-persisting subsystems disabled so one task's
+// run can't leak state into another's.
+func RunTask(baseCfg *config.Config, profile string, task Task) Result {
+	result := Result{TaskID: task.ID, Profile: profile}
+	start := time.Now()
+
+	taskCfg := *baseCfg
+	taskCfg.WorkingDir = task.RepoPath
+	taskCfg.BaseDir = task.RepoPath
+	taskCfg.Session = ""
+	taskCfg.TUI = false
+	taskCfg.LLM.Stream = false
+	taskCfg.Confirmation.Mode = "auto"
+	taskCfg.Memory.Enabled = false
+	taskCfg.Checkpoint.Enabled = false
+	taskCfg.Audit.Enabled = false
+	taskCfg.Telemetry.Enabled = false
+	taskCfg.Plan.Auto = false
+
+	a, err := agent.New(&taskCfg, nil)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create agent: %v", err)
+		result.DurationSeconds = time.Since(start).Seconds()
+		return result
+	}
+	defer a.Close()
+
+	if err := a.RunOnce(task.Issue); err != nil {
+		result.Error = fmt.Sprintf("agent turn failed: %v", err)
+	}
+	result.PromptTokens, result.CompletionTokens = a.TokenUsage()
+
+	if result.Error == "" {
+		cmd := exec.Command("sh", "-c", task.TestCommand)
+		cmd.Dir = task.RepoPath
+		if out, testErr := cmd.CombinedOutput(); testErr != nil {
+			result.Error = fmt.Sprintf("test oracle failed: %v\n%s", testErr, out)
+		} else {
+			result.Passed = true
+		}
+	}
+
+	result.DurationSeconds = time.Since(start).Seconds()
+	return result
+}
+
+// RunAll runs every task in tasks against profile and returns one Result
+// per task, in order.
+func RunAll(baseCfg *config.Config, profile string, tasks []Task) []Result {
+	results := make([]Result, len(tasks))
+	for i, task := range tasks {
+		results[i] = RunTask(baseCfg, profile, task)
+	}
+	return results
+}
@@ -0,0 +1,56 @@
+// Package evaluation runs GoCode one-shot against SWE-bench-style task
+// bundles (a repository checkout, an issue description, and a test oracle
+// command) and reports pass@1 and token-cost metrics, so maintainers can
+// compare model/prompt changes with data instead of anecdote.
+package evaluation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Task is one benchmark case: a repo already checked out on disk, the issue
+// text to hand the agent as its only turn, and a test command that exits 0
+// when the fix is correct.
+type Task struct {
+	ID          string `json:"id"`
+	RepoPath    string `json:"repo_path"`
+	Issue       string `json:"issue"`
+	TestCommand string `json:"test_command"`
+}
+
+// LoadTasks reads a task bundle file, one JSON object per line - the same
+// JSONL convention used by requests.jsonl and the audit log.
+func LoadTasks(path string) ([]Task, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task bundle: %w", err)
+	}
+	defer f.Close()
+
+	var tasks []Task
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		var task Task
+		if err := json.Unmarshal([]byte(line), &task); err != nil {
+			return nil, fmt.Errorf("task bundle line %d: %w", lineNum, err)
+		}
+		if task.ID == "" || task.RepoPath == "" || task.TestCommand == "" {
+			return nil, fmt.Errorf("task bundle line %d: id, repo_path, and test_command are required", lineNum)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read task bundle: %w", err)
+	}
+	return tasks, nil
+}
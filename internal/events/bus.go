@@ -0,0 +1,41 @@
+package events
+
+import "sync"
+
+// Bus fans a stream of Events out to every subscribed handler, in the order
+// they subscribed. Handlers run synchronously on the publisher's goroutine
+// (the agent loop is already single-threaded per turn), so a handler that
+// blocks - e.g. a slow network sink - will block the turn; keep handlers
+// fast or hand off internally.
+type Bus struct {
+	mu       sync.Mutex
+	handlers []func(Event)
+}
+
+// NewBus creates an empty event bus.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers handler to receive every future Publish call.
+func (b *Bus) Subscribe(handler func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish delivers evt to every subscribed handler. A nil Bus is a no-op,
+// so callers don't need a nil check at every call site.
+func (b *Bus) Publish(evt Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	handlers := make([]func(Event), len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h(evt)
+	}
+}
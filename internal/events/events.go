@@ -0,0 +1,75 @@
+// Package events defines the typed events the agent loop emits as it runs a
+// turn, so a frontend (the terminal renderer, --tui, a future HTTP/SSE
+// endpoint) can render them however it likes instead of the agent core
+// calling fmt.Printf directly.
+package events
+
+import "time"
+
+// Event is the interface every event type in this package satisfies -
+// intentionally minimal, so a subscriber type-switches on the concrete type
+// it cares about and ignores the rest.
+type Event interface {
+	eventMarker()
+}
+
+// AssistantText is emitted once per assistant response, already fully
+// rendered to text (links resolved, streaming complete).
+type AssistantText struct {
+	Content string
+}
+
+// ToolStarted is emitted right before a tool call executes.
+type ToolStarted struct {
+	Name string
+	Args string
+}
+
+// ToolFinished is emitted after a tool call completes (or fails). Extra
+// holds any supplementary text a subscriber should show alongside the
+// result (e.g. todo_write's progress summary), or "" if there is none.
+type ToolFinished struct {
+	Name   string
+	Result string
+	Err    error
+	Extra  string
+}
+
+// ToolRejected is emitted when the user declines a confirmation prompt for
+// a tool call, instead of it running at all.
+type ToolRejected struct {
+	Name string
+	Args string
+}
+
+// ConfirmationRequested is emitted just before the agent blocks on a
+// confirmation prompt for a tool call, so a non-terminal frontend knows a
+// prompt is pending instead of the process simply appearing to hang.
+type ConfirmationRequested struct {
+	Name string
+	Args string
+}
+
+// TurnSummary is emitted once a full user turn (possibly several tool
+// calls) finishes.
+type TurnSummary struct {
+	ToolCalls int
+	Duration  time.Duration
+}
+
+// IndexProgress is emitted as the background workspace indexer
+// (codegraph.Graph.IndexWorkspace) finishes each file, so a frontend can
+// show "n/m files, ETA ...". Done == Total marks the final event.
+type IndexProgress struct {
+	Done  int
+	Total int
+	ETA   time.Duration
+}
+
+func (AssistantText) eventMarker()         {}
+func (ToolStarted) eventMarker()           {}
+func (ToolFinished) eventMarker()          {}
+func (ToolRejected) eventMarker()          {}
+func (ConfirmationRequested) eventMarker() {}
+func (TurnSummary) eventMarker()           {}
+func (IndexProgress) eventMarker()         {}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jake/gocode/internal/theme"
+)
+
+// TerminalRenderer renders events the way the agent loop used to print them
+// directly - it's the default subscriber, kept as a distinct type so other
+// frontends (--tui, a future HTTP/SSE handler) can subscribe their own
+// renderer to the same Bus instead.
+type TerminalRenderer struct{}
+
+// NewTerminalRenderer creates a TerminalRenderer.
+func NewTerminalRenderer() *TerminalRenderer {
+	return &TerminalRenderer{}
+}
+
+// Handle is the Bus subscriber function - pass r.Handle to Bus.Subscribe.
+func (r *TerminalRenderer) Handle(evt Event) {
+	switch e := evt.(type) {
+	case AssistantText:
+		if e.Content != "" {
+			fmt.Printf("\n%s\n", theme.Agent(e.Content))
+		}
+	case ToolStarted:
+		r.renderToolStarted(e)
+	case ToolFinished:
+		r.renderToolFinished(e)
+	case ToolRejected:
+		fmt.Println(theme.Error("❌ Tool execution rejected"))
+	case ConfirmationRequested:
+		// The confirmation prompt itself is rendered by confirmation.Provider;
+		// nothing extra needed for a terminal frontend.
+	case TurnSummary:
+		// The terminal renderer doesn't show a summary line per turn today -
+		// /stats already exposes cumulative turn latency.
+	case IndexProgress:
+		if e.Done == e.Total {
+			fmt.Printf("%s\n", theme.Dim("Workspace indexing complete: %d file(s)", e.Total))
+			return
+		}
+		fmt.Printf("%s\n", theme.Dim("Indexing workspace [%d/%d, ETA %s]", e.Done, e.Total, e.ETA.Round(time.Second)))
+	}
+}
+
+func (r *TerminalRenderer) renderToolStarted(e ToolStarted) {
+	if e.Name == "note" {
+		var noteArgs struct {
+			Content string `json:"content"`
+		}
+		if json.Unmarshal([]byte(e.Args), &noteArgs) == nil {
+			fmt.Printf("\n%s\n", theme.Dim("📝 %s", noteArgs.Content))
+		}
+		return
+	}
+
+	if e.Name == "bash" {
+		var bashArgs struct {
+			Command string `json:"command"`
+		}
+		if json.Unmarshal([]byte(e.Args), &bashArgs) == nil && bashArgs.Command != "" {
+			displayCmd := bashArgs.Command
+			if len(displayCmd) > 60 {
+				displayCmd = displayCmd[:57] + "..."
+			}
+			fmt.Printf("\n%s %s %s\n", theme.Tool("🔧 Executing:"), theme.ToolBold(e.Name), theme.Dim("(%s)", displayCmd))
+			return
+		}
+	}
+
+	fmt.Printf("\n%s %s\n", theme.Tool("🔧 Executing:"), theme.ToolBold(e.Name))
+}
+
+func (r *TerminalRenderer) renderToolFinished(e ToolFinished) {
+	if e.Name == "note" {
+		return
+	}
+
+	if e.Err != nil {
+		fmt.Printf("%s\n", theme.Error("❌ %s", e.Result))
+		return
+	}
+
+	fmt.Printf("%s\n", theme.Success("✓ Complete"))
+	if e.Extra != "" {
+		fmt.Printf("%s\n", theme.Dim(e.Extra))
+	}
+}
@@ -0,0 +1,159 @@
+// Package filewatch keeps the code graph, hybrid retriever, and embeddings
+// vector store in sync with the workspace as files change, so the model
+// doesn't work off stale symbol/search results after an edit.
+package filewatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jake/gocode/internal/codegraph"
+	"github.com/jake/gocode/internal/embeddings"
+	"github.com/jake/gocode/internal/retrieval"
+)
+
+// pollInterval is how often Start re-checks previously-indexed files'
+// mtimes for changes made outside a tracked tool call (e.g. the user
+// editing the file in their own editor). Tool-driven edits are picked up
+// via Queue instead of waiting for the next poll.
+const pollInterval = 5 * time.Second
+
+// queueDebounce is how long Queue waits after the last call for a given
+// path before actually reindexing it, so a burst of tool calls touching the
+// same file in quick succession (e.g. several edits in one turn) triggers
+// one reindex instead of one per call.
+const queueDebounce = 400 * time.Millisecond
+
+// Watcher re-indexes a file's code-graph symbols, BM25/trigram entries, and
+// embeddings whenever it changes. All three targets are optional (nil
+// disables that target) so a Watcher can be constructed even when only some
+// of them are enabled in config.yaml.
+type Watcher struct {
+	workingDir string
+	codeGraph  *codegraph.Graph
+	retriever  *retrieval.HybridRetriever
+	embedMgr   *embeddings.Manager
+
+	mu      sync.Mutex
+	mtimes  map[string]time.Time
+	pending map[string]*time.Timer // paths queued via Queue, debouncing until their timer fires
+}
+
+// New builds a Watcher over the given targets. workingDir is used to derive
+// the relative FilePath the retriever indexes documents under, matching how
+// the initial workspace index (internal/agent's buildRetriever) IDs them.
+func New(workingDir string, codeGraph *codegraph.Graph, retriever *retrieval.HybridRetriever, embedMgr *embeddings.Manager) *Watcher {
+	return &Watcher{
+		workingDir: workingDir,
+		codeGraph:  codeGraph,
+		retriever:  retriever,
+		embedMgr:   embedMgr,
+		mtimes:     make(map[string]time.Time),
+		pending:    make(map[string]*time.Timer),
+	}
+}
+
+// Queue schedules path to be reindexed after queueDebounce, resetting the
+// timer if it's already queued. Wired as tools.Registry's file-change hook,
+// so several rapid edits to the same file in one turn don't each pay for
+// their own reindex, and the turn loop doesn't block waiting for one.
+func (w *Watcher) Queue(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if timer, ok := w.pending[path]; ok {
+		timer.Stop()
+	}
+	w.pending[path] = time.AfterFunc(queueDebounce, func() {
+		w.mu.Lock()
+		delete(w.pending, path)
+		w.mu.Unlock()
+		w.reindex(path)
+	})
+}
+
+// Start runs the periodic mtime poll until ctx is canceled, catching
+// changes made outside the session (e.g. the user editing a tracked file in
+// another editor).
+func (w *Watcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.pollTracked()
+		}
+	}
+}
+
+// pollTracked re-indexes any previously-seen file whose mtime has advanced
+// past what the watcher last recorded for it.
+func (w *Watcher) pollTracked() {
+	w.mu.Lock()
+	paths := make([]string, 0, len(w.mtimes))
+	for p := range w.mtimes {
+		paths = append(paths, p)
+	}
+	w.mu.Unlock()
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // deleted or unreadable; leave the stale entry rather than guess what happened
+		}
+
+		w.mu.Lock()
+		last, tracked := w.mtimes[path]
+		w.mu.Unlock()
+
+		if tracked && !info.ModTime().After(last) {
+			continue
+		}
+
+		w.reindex(path)
+	}
+}
+
+// reindex re-runs code-graph, BM25/trigram, and embeddings indexing for one
+// file and records its mtime so future polls only fire on further changes.
+func (w *Watcher) reindex(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	if w.codeGraph != nil {
+		_ = w.codeGraph.IndexFile(ctx, path)
+	}
+
+	if w.retriever != nil {
+		if content, err := os.ReadFile(path); err == nil {
+			relPath := path
+			if rel, err := filepath.Rel(w.workingDir, path); err == nil {
+				relPath = rel
+			}
+			w.retriever.RemoveDocument(relPath)
+			_ = w.retriever.AddDocument(ctx, retrieval.Document{
+				ID:       relPath,
+				Content:  string(content),
+				FilePath: relPath,
+			})
+		}
+	}
+
+	if w.embedMgr != nil {
+		_ = w.embedMgr.IndexFile(ctx, path)
+	}
+
+	w.mu.Lock()
+	w.mtimes[path] = info.ModTime()
+	w.mu.Unlock()
+}
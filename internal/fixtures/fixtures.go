@@ -0,0 +1,135 @@
+// Package fixtures provides record/replay tooling for tool execution, so
+// agent-level behavior (prompting, loop logic, self-check) can be tested
+// deterministically without touching the filesystem, network, or an LLM. It
+// captures real tool inputs/outputs to a fixture file with Recorder, then
+// serves them back in order with Player.
+//
+// tools.Registry satisfies Executor without any changes on its side, so a
+// test can wrap a real *tools.Registry in a Recorder, drive it through a
+// scripted sequence of calls, and replay the saved fixture through a Player
+// later - see fixtures_test.go for a full record/replay round trip against
+// the actual todo_write tool.
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Executor is the subset of tools.Registry's interface that Recorder and
+// Player need. tools.Registry satisfies this without changes.
+type Executor interface {
+	Execute(ctx context.Context, name string, args string) (string, error)
+}
+
+// Call is one recorded tool invocation: the request (Tool, Args) and what
+// came back (Result, Err). Err is stored as a string since fixture files are
+// plain JSON and errors don't round-trip through encoding/json.
+type Call struct {
+	Tool   string `json:"tool"`
+	Args   string `json:"args"`
+	Result string `json:"result"`
+	Err    string `json:"err,omitempty"`
+}
+
+// Recorder wraps an Executor, transparently forwarding every call to it
+// while appending a Call fixture for each one. Save it to a file with Save
+// once the session being captured is done.
+type Recorder struct {
+	next  Executor
+	calls []Call
+}
+
+// NewRecorder wraps next so its calls are captured as they happen.
+func NewRecorder(next Executor) *Recorder {
+	return &Recorder{next: next}
+}
+
+// Execute forwards to the wrapped Executor and records the call.
+func (r *Recorder) Execute(ctx context.Context, name string, args string) (string, error) {
+	result, err := r.next.Execute(ctx, name, args)
+
+	call := Call{Tool: name, Args: args, Result: result}
+	if err != nil {
+		call.Err = err.Error()
+	}
+	r.calls = append(r.calls, call)
+
+	return result, err
+}
+
+// Calls returns the calls recorded so far, in order.
+func (r *Recorder) Calls() []Call {
+	return r.calls
+}
+
+// Save writes the recorded calls to path as a JSON array, suitable for
+// LoadFixture to read back with Player.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.calls, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write fixture %s: %w", path, err)
+	}
+	return nil
+}
+
+// Player is an Executor that replays a fixed sequence of Calls recorded
+// earlier by Recorder, instead of doing real work. It never touches the
+// filesystem, network, or an LLM - the point of using it in a test.
+type Player struct {
+	calls []Call
+	next  int
+}
+
+// NewPlayer creates a Player that replays calls in order.
+func NewPlayer(calls []Call) *Player {
+	return &Player{calls: calls}
+}
+
+// LoadFixture reads a fixture file written by Recorder.Save and returns a
+// Player ready to replay it.
+func LoadFixture(path string) (*Player, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+
+	var calls []Call
+	if err := json.Unmarshal(data, &calls); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	return NewPlayer(calls), nil
+}
+
+// Execute serves back the next recorded call. It errors if the requested
+// tool/args don't match what was recorded at this point in the sequence, or
+// if the fixture has been exhausted - either means the code under test
+// diverged from the recorded run rather than a genuine replay.
+func (p *Player) Execute(ctx context.Context, name string, args string) (string, error) {
+	if p.next >= len(p.calls) {
+		return "", fmt.Errorf("fixture exhausted: no recorded call left for %s", name)
+	}
+
+	call := p.calls[p.next]
+	if call.Tool != name || call.Args != args {
+		return "", fmt.Errorf("fixture mismatch at call %d: recorded %s(%s), got %s(%s)", p.next, call.Tool, call.Args, name, args)
+	}
+	p.next++
+
+	if call.Err != "" {
+		return call.Result, fmt.Errorf("%s", call.Err)
+	}
+	return call.Result, nil
+}
+
+// Remaining reports how many recorded calls haven't been replayed yet, so a
+// test can assert every fixture entry was actually exercised.
+func (p *Player) Remaining() int {
+	return len(p.calls) - p.next
+}
@@ -0,0 +1,75 @@
+package fixtures
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jake/gocode/internal/tools"
+)
+
+// newTodoRegistry builds a real *tools.Registry around the todo_write tool,
+// backed by a fresh scratch file - the same kind of Executor internal/agent
+// hands its tool-call loop, not a stand-in.
+func newTodoRegistry(t *testing.T) *tools.Registry {
+	t.Helper()
+	registry := tools.NewRegistry()
+	registry.Register(tools.NewTodoWriteTool(filepath.Join(t.TempDir(), "todos.json")))
+	return registry
+}
+
+const todoArgs = `{"todos":[{"content":"Write tests","status":"in_progress","activeForm":"Writing tests"}]}`
+
+// TestRecordThenReplay drives a real Registry through a Recorder, saves the
+// fixture, and confirms a Player loaded from that fixture reproduces the
+// same result without touching the registry again.
+func TestRecordThenReplay(t *testing.T) {
+	ctx := context.Background()
+
+	registry := newTodoRegistry(t)
+	recorder := NewRecorder(registry)
+
+	want, err := recorder.Execute(ctx, "todo_write", todoArgs)
+	if err != nil {
+		t.Fatalf("recording call failed: %v", err)
+	}
+
+	fixturePath := filepath.Join(t.TempDir(), "todo.fixture.json")
+	if err := recorder.Save(fixturePath); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	player, err := LoadFixture(fixturePath)
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+
+	got, err := player.Execute(ctx, "todo_write", todoArgs)
+	if err != nil {
+		t.Fatalf("replaying call failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("replayed result = %q, want %q (recorded from the real tool)", got, want)
+	}
+	if remaining := player.Remaining(); remaining != 0 {
+		t.Errorf("Remaining() = %d, want 0 after replaying the only recorded call", remaining)
+	}
+}
+
+// TestPlayerDetectsDivergence confirms a Player errors instead of silently
+// serving the wrong fixture entry when the code under test calls a
+// different tool/args than what was recorded.
+func TestPlayerDetectsDivergence(t *testing.T) {
+	ctx := context.Background()
+
+	registry := newTodoRegistry(t)
+	recorder := NewRecorder(registry)
+	if _, err := recorder.Execute(ctx, "todo_write", todoArgs); err != nil {
+		t.Fatalf("recording call failed: %v", err)
+	}
+
+	player := NewPlayer(recorder.Calls())
+	if _, err := player.Execute(ctx, "todo_write", `{"todos":[]}`); err == nil {
+		t.Fatal("expected a fixture mismatch error for divergent args, got nil")
+	}
+}
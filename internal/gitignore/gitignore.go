@@ -0,0 +1,51 @@
+// Package gitignore provides a best-effort, single-file .gitignore reader
+// shared by anything that walks a project tree and wants to skip what git
+// itself would skip - list_directory's tree view, the project analyzer's
+// statistics, and (in the future) any other walker that would otherwise
+// need its own copy of this logic.
+package gitignore
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadPatterns reads a top-level .gitignore under root, if present. This is
+// a literal/glob name match against each path segment, not a full
+// gitignore implementation (no negation, no directory-only "/" suffix
+// semantics, no nested .gitignore files).
+func LoadPatterns(root string) []string {
+	f, err := os.Open(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, strings.Trim(line, "/"))
+	}
+	patterns = append(patterns, ".git")
+	return patterns
+}
+
+// IsIgnored reports whether name (a single path segment, e.g. a file or
+// directory's base name) matches one of patterns.
+func IsIgnored(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if p == name {
+			return true
+		}
+		if matched, _ := filepath.Match(p, name); matched {
+			return true
+		}
+	}
+	return false
+}
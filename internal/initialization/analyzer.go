@@ -5,27 +5,70 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+
+	"github.com/jake/gocode/internal/gitignore"
+	"github.com/jake/gocode/internal/langdetect"
+	"gopkg.in/yaml.v3"
 )
 
+// analyzeWorkers bounds how many files' lines are counted concurrently once
+// the directory walk (inherently sequential) has produced the candidate
+// list, mirroring embeddings.Manager's IndexDirectory worker pool.
+const analyzeWorkers = 8
+
+// scanProgressInterval is how often (in files walked) scanFiles prints a
+// progress line, so a first run on a big monorepo doesn't look hung for
+// minutes before the summary appears.
+const scanProgressInterval = 5000
+
+// lineCountSampleThreshold is the file size above which countLines
+// estimates the line count from a sample instead of reading the whole
+// file - a handful of large generated/data files shouldn't dominate scan
+// time.
+const lineCountSampleThreshold = 2 * 1024 * 1024 // 2MB
+
+// lineCountSampleBytes is how much of an oversized file countLines reads to
+// estimate its line density.
+const lineCountSampleBytes = 256 * 1024
+
 // ProjectAnalysis contains the results of analyzing a project
 type ProjectAnalysis struct {
-	ProjectName     string              `json:"project_name"`
-	Languages       []LanguageInfo      `json:"languages"`
-	Frameworks      []FrameworkInfo     `json:"frameworks"`
-	Dependencies    []DependencyInfo    `json:"dependencies"`
-	Structure       ProjectStructure    `json:"structure"`
-	Statistics      CodeStatistics      `json:"statistics"`
-	GitInfo         *GitInfo            `json:"git_info,omitempty"`
-	Recommendations []Recommendation    `json:"recommendations"`
+	ProjectName     string           `json:"project_name"`
+	Languages       []LanguageInfo   `json:"languages"`
+	Frameworks      []FrameworkInfo  `json:"frameworks"`
+	Dependencies    []DependencyInfo `json:"dependencies"`
+	Structure       ProjectStructure `json:"structure"`
+	Statistics      CodeStatistics   `json:"statistics"`
+	GitInfo         *GitInfo         `json:"git_info,omitempty"`
+	Recommendations []Recommendation `json:"recommendations"`
+	APISpec         *APISpecInfo     `json:"api_spec,omitempty"`
+}
+
+// APISpecInfo summarizes an OpenAPI/Swagger document found in the project.
+type APISpecInfo struct {
+	Path      string        `json:"path"`
+	Format    string        `json:"format"` // "openapi" or "swagger"
+	Title     string        `json:"title,omitempty"`
+	Version   string        `json:"version,omitempty"`
+	Endpoints []APIEndpoint `json:"endpoints"`
+}
+
+// APIEndpoint describes a single operation in an API spec.
+type APIEndpoint struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Summary string `json:"summary,omitempty"`
 }
 
 // LanguageInfo describes a detected programming language
 type LanguageInfo struct {
-	Name       string `json:"name"`
-	FileCount  int    `json:"file_count"`
+	Name       string   `json:"name"`
+	FileCount  int      `json:"file_count"`
 	Extensions []string `json:"extensions"`
-	Primary    bool   `json:"primary"`
+	Primary    bool     `json:"primary"`
 }
 
 // FrameworkInfo describes a detected framework
@@ -37,26 +80,37 @@ type FrameworkInfo struct {
 
 // DependencyInfo describes project dependencies
 type DependencyInfo struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"` // go.mod, package.json, requirements.txt, etc.
-	Count   int    `json:"count"`
+	Name  string `json:"name"`
+	Type  string `json:"type"` // go.mod, package.json, requirements.txt, etc.
+	Count int    `json:"count"`
 }
 
 // ProjectStructure describes the project's directory structure
 type ProjectStructure struct {
-	HasSrcDir    bool     `json:"has_src_dir"`
-	HasTestsDir  bool     `json:"has_tests_dir"`
-	HasDocsDir   bool     `json:"has_docs_dir"`
-	ConfigFiles  []string `json:"config_files"`
-	EntryPoints  []string `json:"entry_points"`
+	HasSrcDir   bool     `json:"has_src_dir"`
+	HasTestsDir bool     `json:"has_tests_dir"`
+	HasDocsDir  bool     `json:"has_docs_dir"`
+	ConfigFiles []string `json:"config_files"`
+	EntryPoints []string `json:"entry_points"`
 }
 
 // CodeStatistics contains code metrics
 type CodeStatistics struct {
-	TotalFiles       int `json:"total_files"`
-	TotalDirectories int `json:"total_directories"`
-	TotalLines       int `json:"total_lines"`
-	CodeFiles        int `json:"code_files"`
+	TotalFiles       int             `json:"total_files"`
+	TotalDirectories int             `json:"total_directories"`
+	TotalLines       int             `json:"total_lines"`
+	CodeFiles        int             `json:"code_files"`
+	ByDirectory      []DirectoryInfo `json:"by_directory,omitempty"`
+}
+
+// DirectoryInfo summarizes code volume within a single top-level directory
+// entry (the first path segment under the project root), so a caller can
+// see where the bulk of the code actually lives without re-walking the tree.
+type DirectoryInfo struct {
+	Path      string `json:"path"`
+	Files     int    `json:"files"`
+	CodeFiles int    `json:"code_files"`
+	Lines     int    `json:"lines"`
 }
 
 // GitInfo contains git repository information
@@ -71,22 +125,27 @@ type Recommendation struct {
 	Type        string `json:"type"` // "lsp", "embedding", "tool", etc.
 	Title       string `json:"title"`
 	Description string `json:"description"`
-	Priority    string `json:"priority"` // "high", "medium", "low"
+	Priority    string `json:"priority"`            // "high", "medium", "low"
 	Installed   bool   `json:"installed,omitempty"` // For LSP servers
-	Action      string `json:"action,omitempty"` // Installation command or action to take
+	Action      string `json:"action,omitempty"`    // Installation command or action to take
 }
 
 // Analyzer performs project analysis
 type Analyzer struct {
-	workingDir string
-	detector   *Detector
+	workingDir       string
+	detector         *Detector
+	extraExcludeDirs []string // additional directory names to skip, from config.Initialization.ExcludeDirs
 }
 
-// NewAnalyzer creates a new project analyzer
-func NewAnalyzer(workingDir string, detector *Detector) *Analyzer {
+// NewAnalyzer creates a new project analyzer. extraExcludeDirs supplements
+// the hard-coded skip list (vendor, node_modules, etc.) with project-specific
+// directories a user doesn't want counted or scanned - a generated docs
+// site, a data fixtures directory, and so on.
+func NewAnalyzer(workingDir string, detector *Detector, extraExcludeDirs []string) *Analyzer {
 	return &Analyzer{
-		workingDir: workingDir,
-		detector:   detector,
+		workingDir:       workingDir,
+		detector:         detector,
+		extraExcludeDirs: extraExcludeDirs,
 	}
 }
 
@@ -108,6 +167,7 @@ func (a *Analyzer) Analyze() (*ProjectAnalysis, error) {
 	analysis.Frameworks = a.detectFrameworks(fileInfo)
 	analysis.Dependencies = a.detectDependencies(fileInfo)
 	analysis.GitInfo = a.analyzeGit()
+	analysis.APISpec = a.detectAPISpec(fileInfo)
 
 	// Save analysis to cache
 	if err := a.saveAnalysis(analysis); err != nil {
@@ -137,16 +197,21 @@ func (a *Analyzer) LoadCachedAnalysis() (*ProjectAnalysis, error) {
 
 // fileInfo represents information about scanned files
 type fileInfo struct {
-	path      string
-	ext       string
-	isDir     bool
-	size      int64
-	lines     int
+	path  string
+	ext   string
+	isDir bool
+	size  int64
+	lines int
+	lang  string // set for extensionless files recognized by name/shebang; "" otherwise
 }
 
-// scanFiles recursively scans the project directory
+// scanFiles recursively scans the project directory, then counts lines for
+// the code files it found using a worker pool - the walk itself has to be
+// sequential (filepath.Walk visits one entry at a time), but reading and
+// counting each file's contents does not.
 func (a *Analyzer) scanFiles() ([]fileInfo, error) {
 	var files []fileInfo
+	var pending []int // indices into files that still need a line count
 
 	// Directories to skip
 	skipDirs := map[string]bool{
@@ -163,6 +228,15 @@ func (a *Analyzer) scanFiles() ([]fileInfo, error) {
 		".next":        true,
 		".nuxt":        true,
 	}
+	for _, dir := range a.extraExcludeDirs {
+		skipDirs[dir] = true
+	}
+
+	// .gitignore-listed directories are skipped the same way as the
+	// hard-coded ones above, so a project's own build/vendoring choices
+	// (a custom "generated/" dir, a non-standard vendor path) don't need
+	// a matching entry in config.Initialization.exclude_dirs too.
+	ignorePatterns := gitignore.LoadPatterns(a.workingDir)
 
 	err := filepath.Walk(a.workingDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -177,9 +251,11 @@ func (a *Analyzer) scanFiles() ([]fileInfo, error) {
 			}
 		}
 
-		// Skip known large directories
-		if info.IsDir() && skipDirs[name] {
-			return filepath.SkipDir
+		// Skip known large directories, plus anything .gitignore covers
+		if info.IsDir() && name != "." {
+			if skipDirs[name] || gitignore.IsIgnored(name, ignorePatterns) {
+				return filepath.SkipDir
+			}
 		}
 
 		// Get relative path
@@ -196,16 +272,69 @@ func (a *Analyzer) scanFiles() ([]fileInfo, error) {
 			size:  info.Size(),
 		}
 
-		// Count lines for code files
+		// Mark code files (and extensionless files gocode recognizes by
+		// name/shebang, e.g. Dockerfile, Makefile) for line counting once
+		// the walk finishes.
 		if !info.IsDir() && a.isCodeFile(ext) {
-			fi.lines = a.countLines(path)
+			pending = append(pending, len(files))
+		} else if !info.IsDir() && ext == "" {
+			if lang := langdetect.DetectFile(path); lang != "unknown" {
+				fi.lang = lang
+				pending = append(pending, len(files))
+			}
 		}
 
 		files = append(files, fi)
+		if len(files)%scanProgressInterval == 0 {
+			fmt.Printf("%s files scanned\n", formatCount(len(files)))
+		}
 		return nil
 	})
+	if err != nil {
+		return files, err
+	}
+
+	a.countLinesParallel(files, pending)
+	return files, nil
+}
+
+// countLinesParallel fills in files[i].lines for each index in pending,
+// analyzeWorkers files at a time.
+func (a *Analyzer) countLinesParallel(files []fileInfo, pending []int) {
+	if len(pending) == 0 {
+		return
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < analyzeWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				files[idx].lines = a.countLines(filepath.Join(a.workingDir, files[idx].path))
+			}
+		}()
+	}
+	for _, idx := range pending {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+}
 
-	return files, err
+// formatCount renders n with thousands separators (e.g. "12,400"), matching
+// the request's "12,400 files scanned" style progress line.
+func formatCount(n int) string {
+	s := fmt.Sprintf("%d", n)
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
 }
 
 // detectLanguages identifies programming languages in the project
@@ -255,6 +384,18 @@ func (a *Analyzer) detectLanguages(files []fileInfo) []LanguageInfo {
 			Name:       "PHP",
 			Extensions: []string{".php"},
 		},
+		"dockerfile": {
+			Name:       "Dockerfile",
+			Extensions: []string{},
+		},
+		"makefile": {
+			Name:       "Makefile",
+			Extensions: []string{},
+		},
+		"shell": {
+			Name:       "Shell",
+			Extensions: []string{".sh", ".bash"},
+		},
 	}
 
 	// Count files per language
@@ -263,13 +404,25 @@ func (a *Analyzer) detectLanguages(files []fileInfo) []LanguageInfo {
 		if file.isDir {
 			continue
 		}
+		matched := false
 		for key, info := range langMap {
 			for _, ext := range info.Extensions {
 				if file.ext == ext {
 					counts[key]++
+					matched = true
 					break
 				}
 			}
+			if matched {
+				break
+			}
+		}
+		// Extensionless files classified via shebang/filename detection
+		// (Dockerfile, Makefile, scripts) still get counted here.
+		if !matched && file.lang != "" {
+			if _, ok := langMap[file.lang]; ok {
+				counts[file.lang]++
+			}
 		}
 	}
 
@@ -419,6 +572,87 @@ func (a *Analyzer) detectDependencies(files []fileInfo) []DependencyInfo {
 	return deps
 }
 
+// specFileNames are checked in order; the first match wins.
+var specFileNames = []string{
+	"openapi.yaml", "openapi.yml", "openapi.json",
+	"swagger.yaml", "swagger.yml", "swagger.json",
+}
+
+// detectAPISpec looks for an OpenAPI/Swagger document at the project root
+// and summarizes its operations, so the agent can reason about a REST
+// service's contract without re-reading the whole spec on every turn.
+func (a *Analyzer) detectAPISpec(files []fileInfo) *APISpecInfo {
+	fileSet := make(map[string]bool)
+	for _, file := range files {
+		fileSet[file.path] = true
+	}
+
+	var specPath string
+	for _, name := range specFileNames {
+		if fileSet[name] {
+			specPath = name
+			break
+		}
+	}
+	if specPath == "" {
+		return nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(a.workingDir, specPath))
+	if err != nil {
+		return nil
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil
+	}
+
+	format := "openapi"
+	if _, ok := doc["swagger"]; ok {
+		format = "swagger"
+	}
+
+	info := &APISpecInfo{Path: specPath, Format: format}
+	if infoField, ok := doc["info"].(map[string]interface{}); ok {
+		info.Title, _ = infoField["title"].(string)
+		info.Version, _ = infoField["version"].(string)
+	}
+
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		return info
+	}
+
+	var routes []string
+	for route := range paths {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	httpMethods := []string{"get", "post", "put", "patch", "delete", "options", "head"}
+	for _, route := range routes {
+		operations, ok := paths[route].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range httpMethods {
+			op, ok := operations[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			summary, _ := op["summary"].(string)
+			info.Endpoints = append(info.Endpoints, APIEndpoint{
+				Method:  strings.ToUpper(method),
+				Path:    route,
+				Summary: summary,
+			})
+		}
+	}
+
+	return info
+}
+
 // analyzeStructure analyzes project directory structure
 func (a *Analyzer) analyzeStructure(files []fileInfo) ProjectStructure {
 	structure := ProjectStructure{
@@ -459,7 +693,7 @@ func (a *Analyzer) analyzeStructure(files []fileInfo) ProjectStructure {
 
 		// Detect entry points
 		if name == "main.go" || name == "main.py" || name == "index.js" ||
-		   name == "index.ts" || name == "app.py" || name == "server.js" {
+			name == "index.ts" || name == "app.py" || name == "server.js" {
 			structure.EntryPoints = append(structure.EntryPoints, file.path)
 		}
 	}
@@ -470,22 +704,51 @@ func (a *Analyzer) analyzeStructure(files []fileInfo) ProjectStructure {
 // calculateStatistics computes code statistics
 func (a *Analyzer) calculateStatistics(files []fileInfo) CodeStatistics {
 	stats := CodeStatistics{}
+	byDir := make(map[string]*DirectoryInfo)
 
 	for _, file := range files {
 		if file.isDir {
 			stats.TotalDirectories++
-		} else {
-			stats.TotalFiles++
-			if a.isCodeFile(file.ext) {
-				stats.CodeFiles++
-				stats.TotalLines += file.lines
-			}
+			continue
+		}
+
+		stats.TotalFiles++
+		top := topLevelDir(file.path)
+		dir := byDir[top]
+		if dir == nil {
+			dir = &DirectoryInfo{Path: top}
+			byDir[top] = dir
 		}
+		dir.Files++
+
+		if a.isCodeFile(file.ext) {
+			stats.CodeFiles++
+			stats.TotalLines += file.lines
+			dir.CodeFiles++
+			dir.Lines += file.lines
+		}
+	}
+
+	for _, dir := range byDir {
+		stats.ByDirectory = append(stats.ByDirectory, *dir)
 	}
+	sort.Slice(stats.ByDirectory, func(i, j int) bool {
+		return stats.ByDirectory[i].Path < stats.ByDirectory[j].Path
+	})
 
 	return stats
 }
 
+// topLevelDir returns the first path segment of a project-relative path, or
+// "." for files at the project root - the grouping key for the by-directory
+// breakdown in CodeStatistics.
+func topLevelDir(relPath string) string {
+	if idx := strings.IndexAny(relPath, "/\\"); idx != -1 {
+		return relPath[:idx]
+	}
+	return "."
+}
+
 // analyzeGit checks git repository information
 func (a *Analyzer) analyzeGit() *GitInfo {
 	gitDir := filepath.Join(a.workingDir, ".git")
@@ -527,12 +790,42 @@ func (a *Analyzer) isCodeFile(ext string) bool {
 	return codeExts[ext]
 }
 
+// countLines returns path's line count. Files above lineCountSampleThreshold
+// are estimated from a lineCountSampleBytes sample rather than read in full,
+// since a handful of large generated/data files shouldn't dominate scan
+// time for a number that's only ever used as a rough statistic.
 func (a *Analyzer) countLines(path string) int {
-	content, err := os.ReadFile(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+
+	if info.Size() <= lineCountSampleThreshold {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return 0
+		}
+		return strings.Count(string(content), "\n") + 1
+	}
+
+	f, err := os.Open(path)
 	if err != nil {
 		return 0
 	}
-	return strings.Count(string(content), "\n") + 1
+	defer f.Close()
+
+	sample := make([]byte, lineCountSampleBytes)
+	n, err := f.Read(sample)
+	if err != nil && n == 0 {
+		return 0
+	}
+	sample = sample[:n]
+
+	newlines := strings.Count(string(sample), "\n")
+	if newlines == 0 {
+		return 1
+	}
+	return int(float64(newlines) / float64(n) * float64(info.Size()))
 }
 
 func (a *Analyzer) countGoModDependencies() int {
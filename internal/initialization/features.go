@@ -80,10 +80,10 @@ func (fd *FeatureDetector) generateLSPRecommendations() []Recommendation {
 	var recommendations []Recommendation
 
 	lspServers := map[string]struct {
-		language    string
-		command     string
-		name        string
-		installCmd  string
+		language   string
+		command    string
+		name       string
+		installCmd string
 	}{
 		"Go":         {"go", "gopls", "gopls", "go install golang.org/x/tools/gopls@latest"},
 		"Python":     {"python", "pylsp", "Python Language Server", "pip install python-lsp-server"},
@@ -135,6 +135,29 @@ func (fd *FeatureDetector) generateLSPRecommendations() []Recommendation {
 	return recommendations
 }
 
+// lspInstallCommands maps an LSP server's config key (config.yaml's
+// lsp.servers keys - "go", "python", "typescript", ...) to the shell
+// command that installs it, for `/lsp install <lang>` to run. Keyed
+// differently from generateLSPRecommendations' lspServers map (which is
+// keyed by the detected-language display name, e.g. "Go") since that one
+// only needs to look up by ProjectAnalysis.Languages entries.
+var lspInstallCommands = map[string]string{
+	"go":         "go install golang.org/x/tools/gopls@latest",
+	"python":     "pip install python-lsp-server",
+	"typescript": "npm install -g typescript-language-server typescript",
+	"javascript": "npm install -g typescript-language-server typescript",
+	"rust":       "rustup component add rust-analyzer",
+	"c":          "Install LLVM/Clang toolchain",
+	"cpp":        "Install LLVM/Clang toolchain",
+}
+
+// InstallCommandFor returns the known install command for an LSP server's
+// config key, if one is known.
+func InstallCommandFor(language string) (string, bool) {
+	cmd, ok := lspInstallCommands[language]
+	return cmd, ok
+}
+
 // isCommandAvailable checks if a command is available in PATH
 func (fd *FeatureDetector) isCommandAvailable(command string) bool {
 	_, err := exec.LookPath(command)
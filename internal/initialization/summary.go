@@ -35,6 +35,12 @@ func DisplaySummary(analysis *ProjectAnalysis, recommendations []Recommendation)
 		fmt.Println()
 	}
 
+	// API spec
+	if analysis.APISpec != nil {
+		displayAPISpec(analysis.APISpec)
+		fmt.Println()
+	}
+
 	// Recommendations
 	if len(recommendations) > 0 {
 		displayRecommendations(recommendations)
@@ -121,6 +127,19 @@ func displayDependencies(dependencies []DependencyInfo) {
 	}
 }
 
+func displayAPISpec(spec *APISpecInfo) {
+	fmt.Println(theme.HeaderBold("📄 API Spec Detected:"))
+	title := spec.Title
+	if title == "" {
+		title = spec.Path
+	}
+	fmt.Printf("   %s %s (%s, %d endpoints)\n",
+		theme.Success("•"),
+		theme.Agent(title),
+		theme.Dim(spec.Format),
+		len(spec.Endpoints))
+}
+
 func displayRecommendations(recommendations []Recommendation) {
 	fmt.Println(theme.HeaderBold("💡 Recommended Enhancements:"))
 
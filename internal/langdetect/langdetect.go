@@ -0,0 +1,145 @@
+// Package langdetect is the single source of truth for mapping a source
+// file to a language name. It's shared by the parser, codegraph indexer,
+// embeddings chunker, and project analyzer so a file classified one way in
+// one place doesn't come out "unknown" in another.
+package langdetect
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extLanguages maps file extensions to language names.
+var extLanguages = map[string]string{
+	".go":   "go",
+	".py":   "python",
+	".js":   "javascript",
+	".jsx":  "javascript",
+	".mjs":  "javascript",
+	".cjs":  "javascript",
+	".ts":   "javascript",
+	".tsx":  "javascript",
+	".java": "java",
+	".rb":   "ruby",
+	".php":  "php",
+	".cs":   "csharp",
+	".rs":   "rust",
+	".c":    "c",
+	".h":    "c",
+	".cpp":  "cpp",
+	".cc":   "cpp",
+	".cxx":  "cpp",
+	".hpp":  "cpp",
+	".h++":  "cpp",
+}
+
+// filenameLanguages maps well-known extensionless filenames (build tools,
+// container manifests) that would otherwise be classified "unknown".
+var filenameLanguages = map[string]string{
+	"Dockerfile":  "dockerfile",
+	"Makefile":    "makefile",
+	"makefile":    "makefile",
+	"GNUmakefile": "makefile",
+	"Rakefile":    "ruby",
+	"Gemfile":     "ruby",
+	"Vagrantfile": "ruby",
+}
+
+// shebangInterpreters maps the interpreter named on a "#!" line to a
+// language, for scripts that carry no file extension at all.
+var shebangInterpreters = map[string]string{
+	"python":  "python",
+	"python3": "python",
+	"node":    "javascript",
+	"ruby":    "ruby",
+	"php":     "php",
+	"bash":    "shell",
+	"sh":      "shell",
+	"zsh":     "shell",
+}
+
+// FromExtension maps a file extension (as returned by filepath.Ext) to a
+// language name. This is the fast, no-I/O path tried before filename or
+// shebang detection.
+func FromExtension(ext string) (string, bool) {
+	lang, ok := extLanguages[ext]
+	return lang, ok
+}
+
+// FromFilename recognizes well-known extensionless filenames.
+func FromFilename(name string) (string, bool) {
+	lang, ok := filenameLanguages[name]
+	return lang, ok
+}
+
+// FromShebang reads a candidate first line and maps its interpreter to a
+// language, e.g. "#!/usr/bin/env python3" -> "python".
+func FromShebang(firstLine string) (string, bool) {
+	line := strings.TrimSpace(firstLine)
+	if !strings.HasPrefix(line, "#!") {
+		return "", false
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" && len(fields) > 1 {
+		interpreter = fields[1]
+	}
+
+	lang, ok := shebangInterpreters[interpreter]
+	return lang, ok
+}
+
+// Detect determines a file's language from its path and, when the
+// extension is missing or unrecognized, its already-loaded content: a
+// well-known filename, then a shebang line. Returns "unknown" if nothing
+// matches.
+func Detect(path string, content string) string {
+	if lang, ok := FromExtension(filepath.Ext(path)); ok {
+		return lang
+	}
+	if lang, ok := FromFilename(filepath.Base(path)); ok {
+		return lang
+	}
+
+	firstLine := content
+	if idx := strings.IndexByte(content, '\n'); idx >= 0 {
+		firstLine = content[:idx]
+	}
+	if lang, ok := FromShebang(firstLine); ok {
+		return lang
+	}
+
+	return "unknown"
+}
+
+// DetectFile is like Detect but reads the file's first line itself, for
+// callers that haven't already loaded the content into memory.
+func DetectFile(path string) string {
+	if lang, ok := FromExtension(filepath.Ext(path)); ok {
+		return lang
+	}
+	if lang, ok := FromFilename(filepath.Base(path)); ok {
+		return lang
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "unknown"
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		if lang, ok := FromShebang(scanner.Text()); ok {
+			return lang
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,101 @@
+// Package linkify post-processes assistant response text to find file path
+// references (in citation.Format's "path:line" form, or bare paths that look
+// like they belong to the project), verify they exist on disk, and turn the
+// ones that do into clickable links (OSC 8 terminal hyperlinks in the
+// REPL; there is no HTML rendering). References that don't
+// exist are left as plain text but reported back to the caller, which is
+// enough for the agent to nudge the model when it cites a path that isn't
+// real.
+package linkify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jake/gocode/internal/citation"
+)
+
+// refPattern matches a relative-looking file path, optionally followed by a
+// citation.Format line/range suffix - e.g. "internal/agent/agent.go:120" or
+// "config.yaml". It requires at least one path separator or a recognizable
+// source extension so English words with dots ("e.g.", "v1.2") aren't
+// mistaken for paths.
+var refPattern = regexp.MustCompile(`\b[\w./-]+/[\w.-]+\.[a-zA-Z0-9]{1,8}(?::\d+(?:-\d+)?)?\b|\b[\w-]+\.(?:go|py|js|ts|tsx|jsx|rs|java|c|cpp|h|hpp|yaml|yml|json|md)(?::\d+(?:-\d+)?)?\b`)
+
+// Reference is a single file path mention found in a block of text.
+type Reference struct {
+	Raw    string // the exact substring matched, including any :line suffix
+	Path   string // the file path portion, relative to the project root
+	Exists bool
+}
+
+// Result is the outcome of processing one block of text.
+type Result struct {
+	References []Reference
+	Broken     []Reference // subset of References where Exists is false
+}
+
+// Find scans text for file path references and checks each one against
+// root, without modifying text. Duplicate mentions of the same path are only
+// reported once.
+func Find(text, root string) Result {
+	var result Result
+	seen := make(map[string]bool)
+
+	for _, raw := range refPattern.FindAllString(text, -1) {
+		path := raw
+		if p, _, _, ok := citation.Parse(raw); ok {
+			path = p
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		_, err := os.Stat(filepath.Join(root, path))
+		ref := Reference{Raw: raw, Path: path, Exists: err == nil}
+		result.References = append(result.References, ref)
+		if !ref.Exists {
+			result.Broken = append(result.Broken, ref)
+		}
+	}
+
+	return result
+}
+
+// Hyperlink renders text with every reference that exists on disk wrapped in
+// an OSC 8 terminal hyperlink pointing at its absolute path, for terminals
+// that support click-to-open (iTerm2, Windows Terminal, most modern
+// emulators). Terminals without OSC 8 support render the escape sequences as
+// nothing, leaving the visible text unchanged.
+func Hyperlink(text, root string, refs []Reference) string {
+	out := text
+	for _, ref := range refs {
+		if !ref.Exists {
+			continue
+		}
+		target := filepath.Join(root, ref.Path)
+		link := fmt.Sprintf("\x1b]8;;file://%s\x1b\\%s\x1b]8;;\x1b\\", target, ref.Raw)
+		out = strings.ReplaceAll(out, ref.Raw, link)
+	}
+	return out
+}
+
+// CorrectionMessage builds a feedback string listing broken references, for
+// injection back into the conversation the way selfCheck's verified-claims
+// feedback is - so the model gets a chance to correct a citation to a file
+// that doesn't exist before the turn ends.
+func CorrectionMessage(broken []Reference) string {
+	if len(broken) == 0 {
+		return ""
+	}
+	msg := "The following file references in your last response don't exist in this project:\n"
+	for _, ref := range broken {
+		msg += fmt.Sprintf("- %s\n", ref.Raw)
+	}
+	msg += "Double-check the paths (they may be misspelled or from a different project) and correct your response if needed."
+	return msg
+}
@@ -0,0 +1,155 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// capabilities records what the endpoint behind a Client has actually shown
+// it supports, probed from real request/response behavior rather than
+// trusted from config - OpenAI-compatible servers vary widely in what they
+// implement, and a llama.cpp/vLLM endpoint that rejects the "tools" field
+// outright would otherwise fail every turn with a raw API error instead of
+// falling back to something that still works.
+//
+// Only tool-calling support is probed today. Vision and max-context-window
+// negotiation aren't wired up - the client never sends images, and the
+// context window is already an explicit config.LLMConfig field the user
+// sets themselves, so there's nothing to probe for either.
+type capabilities struct {
+	mu             sync.RWMutex
+	toolsProbed    bool
+	toolsSupported bool
+}
+
+// resolveToolMode decides, for one request, whether tools should be sent
+// natively or rewritten into the ReAct-style textual prompt, honoring an
+// explicit config.LLMConfig.ToolCalling override before falling back to the
+// probed capability. mode is normally "auto"/"" (probe-driven); "native" and
+// "textual" force one path regardless of what's been probed so far, useful
+// for endpoints whose behavior is already known.
+func resolveToolMode(mode string, hasTools bool, caps *capabilities) (textual bool) {
+	if !hasTools {
+		return false
+	}
+	switch strings.ToLower(mode) {
+	case "native":
+		return false
+	case "textual":
+		return true
+	default:
+		supported, probed := caps.toolsSupport()
+		return probed && !supported
+	}
+}
+
+// toolsSupport reports the last-known state of tool-calling support and
+// whether it's been probed at all (probed=false before the first request
+// that included tools has completed).
+func (c *capabilities) toolsSupport() (supported, probed bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.toolsSupported, c.toolsProbed
+}
+
+func (c *capabilities) recordToolsSupport(supported bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.toolsProbed = true
+	c.toolsSupported = supported
+}
+
+// looksLikeMissingToolSupport matches the error text OpenAI-compatible
+// servers return when they don't implement function calling at all, as
+// opposed to the request being malformed in some other way. There's no
+// standard error code for this across providers, so this is necessarily a
+// heuristic over known phrasings from llama.cpp, vLLM, and similar servers.
+func looksLikeMissingToolSupport(errMsg string) bool {
+	msg := strings.ToLower(errMsg)
+	markers := []string{
+		"function calling is not supported",
+		"functions is not supported",
+		"tools is not supported",
+		"tool use is not supported",
+		"does not support tools",
+		"does not support function",
+		"unsupported param: tools",
+		"unsupported parameter: 'tools'",
+		"unknown parameter: tools",
+		"unrecognized request argument supplied: tools",
+	}
+	for _, marker := range markers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// toolCallPattern matches a single ReAct-style textual tool call emitted by
+// a model that has no native function-calling support, e.g.:
+//
+//	Action: read
+//	Action Input: {"file_path": "main.go"}
+var toolCallPattern = regexp.MustCompile(`(?m)^Action:\s*(\S+)\s*\n\s*Action Input:\s*(\{.*\})\s*$`)
+
+// withTextualToolPrompt appends a ReAct-style instruction block describing
+// req.Tools to the last message, and clears req.Tools so buildChatRequest
+// never sends the (unsupported) "tools" field to the wire. Used once a
+// client has probed that the endpoint doesn't support native tool calling.
+func withTextualToolPrompt(req CompletionRequest) CompletionRequest {
+	if len(req.Tools) == 0 || len(req.Messages) == 0 {
+		return req
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nYou do not have native tool calling on this endpoint. ")
+	b.WriteString("To use a tool, respond with ONLY these two lines (no other text):\n")
+	b.WriteString("Action: <tool name>\nAction Input: <JSON arguments object>\n\n")
+	b.WriteString("Available tools:\n")
+	for _, t := range req.Tools {
+		params, _ := json.Marshal(t.Function.Parameters)
+		fmt.Fprintf(&b, "- %s: %s\n  parameters: %s\n", t.Function.Name, t.Function.Description, params)
+	}
+	b.WriteString("If no tool is needed, respond normally with no Action line.")
+
+	messages := append([]Message(nil), req.Messages...)
+	last := len(messages) - 1
+	messages[last].Content += b.String()
+
+	req.Messages = messages
+	req.Tools = nil
+	return req
+}
+
+// extractTextualToolCall parses a ReAct-style "Action:"/"Action Input:"
+// pair out of content, returning the tool call and the content with that
+// pair stripped out. ok is false when content contains no such pair, which
+// is the common case - most turns still end in plain text.
+func extractTextualToolCall(content string) (call ToolCall, remaining string, ok bool) {
+	match := toolCallPattern.FindStringSubmatchIndex(content)
+	if match == nil {
+		return ToolCall{}, content, false
+	}
+
+	name := content[match[2]:match[3]]
+	args := content[match[4]:match[5]]
+
+	// Confirm the captured Action Input is a syntactically complete JSON
+	// object before committing to it as a tool call - a model that merely
+	// mentions "Action:" in prose shouldn't be misread as invoking a tool.
+	var probe json.RawMessage
+	if err := json.Unmarshal([]byte(args), &probe); err != nil {
+		return ToolCall{}, content, false
+	}
+
+	call = ToolCall{Type: "function"}
+	call.Function.Name = name
+	call.Function.Arguments = args
+
+	remaining = content[:match[0]] + content[match[1]:]
+	return call, strings.TrimSpace(remaining), true
+}
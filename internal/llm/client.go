@@ -2,8 +2,12 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/jake/gocode/internal/config"
 	openai "github.com/sashabaranov/go-openai"
@@ -13,32 +17,107 @@ import (
 )
 
 type Client struct {
-	client *openai.Client
-	config *config.LLMConfig
-	tracer trace.Tracer
+	client  *openai.Client
+	config  *config.LLMConfig
+	tracer  trace.Tracer
+	profile string // name of the active config.Providers entry, "" for the base llm config
+
+	caps capabilities // probed per-endpoint feature support, e.g. tool calling
 }
 
 func NewClient(cfg *config.LLMConfig) *Client {
-	clientConfig := openai.DefaultConfig(cfg.APIKey)
-	clientConfig.BaseURL = cfg.Endpoint
-
 	return &Client{
-		client: openai.NewClientWithConfig(clientConfig),
+		client: buildOpenAIClient(cfg),
 		config: cfg,
 		tracer: trace.NewNoopTracerProvider().Tracer("noop"),
 	}
 }
 
+// buildOpenAIClient wires up a go-openai client for one LLMConfig, adding a
+// header-injecting transport when the profile needs more than a bearer
+// token (e.g. an Anthropic-compatible gateway's anthropic-version header).
+func buildOpenAIClient(cfg *config.LLMConfig) *openai.Client {
+	clientConfig := openai.DefaultConfig(cfg.APIKey)
+	clientConfig.BaseURL = cfg.Endpoint
+
+	if len(cfg.Headers) > 0 {
+		clientConfig.HTTPClient = &http.Client{
+			Transport: &headerTransport{headers: cfg.Headers, base: http.DefaultTransport},
+		}
+	}
+
+	return openai.NewClientWithConfig(clientConfig)
+}
+
+// headerTransport injects a fixed set of headers on every outgoing request.
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
 // SetTracer sets the tracer for this client
 func (c *Client) SetTracer(tracer trace.Tracer) {
 	c.tracer = tracer
 }
 
+// SwitchProfile reconfigures the client in place to use a different named
+// provider profile, so anything already holding this *Client (the agent
+// loop, the write_long_document tool) picks up the switch without being
+// re-wired. cfg's fields overwrite the client's current LLMConfig, since
+// config.Config.Providers entries are meant as complete profiles rather
+// than partial overrides.
+func (c *Client) SwitchProfile(name string, cfg config.LLMConfig) {
+	c.client = buildOpenAIClient(&cfg)
+	*c.config = cfg
+	c.profile = name
+	c.caps = capabilities{} // different endpoint, so re-probe capabilities from scratch
+}
+
+// CancelSlot best-effort frees the llama-server slot that was serving a
+// generation just cancelled via its request context, so the next request
+// doesn't queue behind an abandoned one still holding the slot's KV cache.
+// It's a no-op against providers that don't expose llama.cpp's /slots API -
+// failures here are swallowed since the request itself is already cancelled
+// and there's nothing actionable to report.
+func (c *Client) CancelSlot() {
+	base := strings.TrimSuffix(c.config.Endpoint, "/")
+	base = strings.TrimSuffix(base, "/v1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/slots/0?action=erase", nil)
+	if err != nil {
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// Profile returns the name of the active provider profile, or "default" if
+// SwitchProfile has never been called.
+func (c *Client) Profile() string {
+	if c.profile == "" {
+		return "default"
+	}
+	return c.profile
+}
+
 type Message struct {
-	Role    string      `json:"role"`
-	Content string      `json:"content"`
-	Tool    *ToolCall   `json:"tool_call,omitempty"`
-	ToolID  string      `json:"tool_call_id,omitempty"`
+	Role    string    `json:"role"`
+	Content string    `json:"content"`
+	Tool    *ToolCall `json:"tool_call,omitempty"`
+	ToolID  string    `json:"tool_call_id,omitempty"`
 }
 
 type ToolCall struct {
@@ -55,6 +134,7 @@ type CompletionRequest struct {
 	Tools       []Tool
 	Temperature float32
 	MaxTokens   int
+	Seed        *int // overrides config.LLM.Seed for this call, e.g. for evaluation reruns
 }
 
 type Tool struct {
@@ -69,19 +149,26 @@ type Function struct {
 }
 
 type CompletionResponse struct {
-	Content   string
-	ToolCalls []ToolCall
+	Content      string
+	ToolCalls    []ToolCall
 	FinishReason string
+	Usage        Usage
 }
 
-func (c *Client) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
-	// Start telemetry span
-	ctx, span := c.tracer.Start(ctx, "llm.completion",
-		trace.WithSpanKind(trace.SpanKindClient),
-	)
-	defer span.End()
+// Usage reports token counts for one completion, so callers that need to
+// track cost (e.g. the evaluation harness) don't have to re-derive it from
+// EstimateTokens.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
 
-	// Set GenAI attributes
+// buildChatRequest translates a CompletionRequest into the go-openai wire
+// format shared by both Complete and CompleteStream, applying the client's
+// configured defaults for temperature/max_tokens/seed and recording the
+// GenAI request attributes on span.
+func (c *Client) buildChatRequest(req CompletionRequest, span trace.Span) openai.ChatCompletionRequest {
 	span.SetAttributes(
 		attribute.String("gen_ai.system", "openai-compatible"),
 		attribute.String("gen_ai.request.model", c.config.Model),
@@ -132,21 +219,55 @@ func (c *Client) Complete(ctx context.Context, req CompletionRequest) (*Completi
 		maxTokens = c.config.MaxTokens
 	}
 
-	chatReq := openai.ChatCompletionRequest{
+	seed := req.Seed
+	if seed == nil && c.config.Seed != 0 {
+		seed = &c.config.Seed
+	}
+	if seed != nil {
+		span.SetAttributes(attribute.Int("gen_ai.request.seed", *seed))
+	}
+
+	return openai.ChatCompletionRequest{
 		Model:       c.config.Model,
 		Messages:    messages,
 		Tools:       tools,
 		Temperature: temperature,
 		MaxTokens:   maxTokens,
+		Seed:        seed,
 	}
+}
+
+func (c *Client) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	// Start telemetry span
+	ctx, span := c.tracer.Start(ctx, "llm.completion",
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	textualFallback := resolveToolMode(c.config.ToolCalling, len(req.Tools) > 0, &c.caps)
+	wireReq := req
+	if textualFallback {
+		wireReq = withTextualToolPrompt(req)
+	}
+
+	chatReq := c.buildChatRequest(wireReq, span)
 
 	resp, err := c.client.CreateChatCompletion(ctx, chatReq)
 	if err != nil {
+		autoProbing := strings.ToLower(c.config.ToolCalling) != "native" && strings.ToLower(c.config.ToolCalling) != "textual"
+		if len(req.Tools) > 0 && !textualFallback && autoProbing && looksLikeMissingToolSupport(err.Error()) {
+			c.caps.recordToolsSupport(false)
+			return c.Complete(ctx, req)
+		}
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("completion failed: %w", err)
 	}
 
+	if len(req.Tools) > 0 && !textualFallback {
+		c.caps.recordToolsSupport(true)
+	}
+
 	if len(resp.Choices) == 0 {
 		err := fmt.Errorf("no completion choices returned")
 		span.RecordError(err)
@@ -158,6 +279,19 @@ func (c *Client) Complete(ctx context.Context, req CompletionRequest) (*Completi
 	result := &CompletionResponse{
 		Content:      choice.Message.Content,
 		FinishReason: string(choice.FinishReason),
+		Usage: Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+	}
+
+	if textualFallback {
+		if call, remaining, ok := extractTextualToolCall(result.Content); ok {
+			call.ID = "textual-1"
+			result.Content = remaining
+			result.ToolCalls = []ToolCall{call}
+		}
 	}
 
 	// Set response attributes
@@ -199,3 +333,132 @@ func (c *Client) Complete(ctx context.Context, req CompletionRequest) (*Completi
 	span.SetStatus(codes.Ok, "")
 	return result, nil
 }
+
+// CompleteStream behaves like Complete, but calls onDelta with each piece of
+// assistant text as it arrives over SSE, so a caller like the REPL can
+// render tokens as they're generated instead of waiting for the full
+// response. Tool calls only become available once the stream ends, since
+// providers send them as index-keyed fragments that only make sense once
+// reassembled; onDelta is never called with tool-call content.
+func (c *Client) CompleteStream(ctx context.Context, req CompletionRequest, onDelta func(string)) (*CompletionResponse, error) {
+	ctx, span := c.tracer.Start(ctx, "llm.completion",
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	textualFallback := resolveToolMode(c.config.ToolCalling, len(req.Tools) > 0, &c.caps)
+	wireReq := req
+	if textualFallback {
+		wireReq = withTextualToolPrompt(req)
+	}
+
+	chatReq := c.buildChatRequest(wireReq, span)
+
+	stream, err := c.client.CreateChatCompletionStream(ctx, chatReq)
+	if err != nil {
+		autoProbing := strings.ToLower(c.config.ToolCalling) != "native" && strings.ToLower(c.config.ToolCalling) != "textual"
+		if len(req.Tools) > 0 && !textualFallback && autoProbing && looksLikeMissingToolSupport(err.Error()) {
+			c.caps.recordToolsSupport(false)
+			return c.CompleteStream(ctx, req, onDelta)
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, fmt.Errorf("completion stream failed: %w", err)
+	}
+	defer stream.Close()
+	if len(req.Tools) > 0 && !textualFallback {
+		c.caps.recordToolsSupport(true)
+	}
+
+	var contentBuilder strings.Builder
+	toolCalls := make(map[int]*ToolCall)
+	var toolCallOrder []int
+	var finishReason string
+	usage := Usage{}
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, fmt.Errorf("completion stream failed: %w", err)
+		}
+
+		if chunk.Usage != nil {
+			usage = Usage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		choice := chunk.Choices[0]
+
+		if choice.Delta.Content != "" {
+			contentBuilder.WriteString(choice.Delta.Content)
+			if onDelta != nil {
+				onDelta(choice.Delta.Content)
+			}
+		}
+
+		for _, tc := range choice.Delta.ToolCalls {
+			idx := 0
+			if tc.Index != nil {
+				idx = *tc.Index
+			}
+			acc, ok := toolCalls[idx]
+			if !ok {
+				acc = &ToolCall{}
+				toolCalls[idx] = acc
+				toolCallOrder = append(toolCallOrder, idx)
+			}
+			if tc.ID != "" {
+				acc.ID = tc.ID
+			}
+			if tc.Type != "" {
+				acc.Type = string(tc.Type)
+			}
+			acc.Function.Name += tc.Function.Name
+			acc.Function.Arguments += tc.Function.Arguments
+		}
+
+		if choice.FinishReason != "" {
+			finishReason = string(choice.FinishReason)
+		}
+	}
+
+	result := &CompletionResponse{
+		Content:      contentBuilder.String(),
+		FinishReason: finishReason,
+		Usage:        usage,
+	}
+	for _, idx := range toolCallOrder {
+		result.ToolCalls = append(result.ToolCalls, *toolCalls[idx])
+	}
+
+	if textualFallback {
+		if call, remaining, ok := extractTextualToolCall(result.Content); ok {
+			call.ID = "textual-1"
+			result.Content = remaining
+			result.ToolCalls = []ToolCall{call}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.String("gen_ai.response.finish_reason", finishReason),
+		attribute.Int("gen_ai.usage.prompt_tokens", usage.PromptTokens),
+		attribute.Int("gen_ai.usage.completion_tokens", usage.CompletionTokens),
+		attribute.Int("gen_ai.usage.total_tokens", usage.TotalTokens),
+	)
+	span.AddEvent("response",
+		trace.WithAttributes(attribute.Int("response.length", len(result.Content))),
+	)
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
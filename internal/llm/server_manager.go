@@ -9,12 +9,14 @@ import (
 	"time"
 
 	"github.com/jake/gocode/internal/config"
+	"github.com/jake/gocode/internal/mockllm"
 	"github.com/jake/gocode/internal/theme"
 )
 
 type ServerManager struct {
 	config      *config.LLMConfig
 	process     *exec.Cmd
+	mockServer  *mockllm.Server
 	managedByUs bool
 }
 
@@ -32,6 +34,10 @@ func (sm *ServerManager) Start() error {
 		return nil
 	}
 
+	if sm.config.MockScript != "" {
+		return sm.startMock()
+	}
+
 	fmt.Println(theme.Dim("Checking if llama-server is already running..."))
 
 	// Check if server is already available
@@ -69,9 +75,47 @@ func (sm *ServerManager) Start() error {
 	return nil
 }
 
+// startMock loads sm.config.MockScript and serves it in place of a real
+// llama-server, so the agent loop can be driven end to end in CI or during
+// a config dry-run without a model. It reuses the same managedByUs
+// lifecycle real servers use, so Stop() tears it down the same way.
+func (sm *ServerManager) startMock() error {
+	fmt.Println(theme.Agent(fmt.Sprintf("Starting mock LLM server from script: %s", sm.config.MockScript)))
+
+	script, err := mockllm.LoadScript(sm.config.MockScript)
+	if err != nil {
+		return fmt.Errorf("failed to load mock LLM script: %w", err)
+	}
+
+	sm.mockServer = mockllm.NewServer(script)
+	addr := fmt.Sprintf("%s:%d", sm.config.Server.Host, sm.config.Server.Port)
+	if err := sm.mockServer.Start(addr); err != nil {
+		sm.mockServer = nil
+		return fmt.Errorf("failed to start mock LLM server: %w", err)
+	}
+
+	sm.managedByUs = true
+	fmt.Println(theme.Success("✓ mock LLM server is ready!"))
+	return nil
+}
+
 // Stop gracefully stops the server if we started it
 func (sm *ServerManager) Stop() error {
-	if !sm.managedByUs || sm.process == nil {
+	if !sm.managedByUs {
+		return nil
+	}
+
+	if sm.mockServer != nil {
+		fmt.Println(theme.Dim("🛑 Shutting down mock LLM server..."))
+		err := sm.mockServer.Stop()
+		sm.mockServer = nil
+		if err == nil {
+			fmt.Println(theme.Success("✓ mock LLM server stopped"))
+		}
+		return err
+	}
+
+	if sm.process == nil {
 		return nil
 	}
 
@@ -149,6 +149,49 @@ func (l *Logger) LogToolResult(name string, result string, err error) {
 	l.Log(entry)
 }
 
+// ContextAssemblySnapshot captures what went into a turn's final message
+// array, for root-causing "why did the model not know X" - which memories
+// and retrieved chunks made it in, what got pruned/compressed, and the
+// size of every section of the final prompt.
+type ContextAssemblySnapshot struct {
+	TurnNumber        int                    `json:"turn_number"`
+	Timestamp         time.Time              `json:"timestamp"`
+	Messages          []ContextMessageDigest `json:"messages"`
+	MemoriesIncluded  []string               `json:"memories_included,omitempty"`
+	ChunksRetrieved   int                    `json:"chunks_retrieved"`
+	ChunksIncluded    []string               `json:"chunks_included,omitempty"`
+	ToolResultsPruned int                    `json:"tool_results_pruned"`
+}
+
+// ContextMessageDigest is one entry of the final message array, sized
+// rather than reproduced in full so the digest stays small enough to skim.
+type ContextMessageDigest struct {
+	Role  string `json:"role"`
+	Chars int    `json:"chars"`
+}
+
+// LogContextAssembly writes a per-turn context-assembly digest to its own
+// file under logs/context/, gated on logging.level: "debug" since it's a
+// diagnostic aid rather than something every session needs to pay for.
+func (l *Logger) LogContextAssembly(snapshot ContextAssemblySnapshot) error {
+	if l.config.Level != "debug" {
+		return nil
+	}
+
+	dir := filepath.Join(filepath.Dir(l.file.Name()), "context")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create context log directory: %w", err)
+	}
+
+	snapshot.Timestamp = time.Now()
+	name := fmt.Sprintf("turn-%03d-%s.json", snapshot.TurnNumber, snapshot.Timestamp.Format("15-04-05.000"))
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal context assembly snapshot: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, name), data, 0644)
+}
+
 func (l *Logger) Close() error {
 	// Send session end log
 	l.Log(LogEntry{
@@ -25,6 +25,9 @@ type Client struct {
 	mu            sync.Mutex
 	notifications chan *Notification
 	shutdown      chan struct{}
+
+	diagMu      sync.RWMutex
+	diagnostics map[string][]Diagnostic // uri -> latest diagnostics from publishDiagnostics
 }
 
 // Message types
@@ -52,8 +55,8 @@ type Response struct {
 
 // ResponseError represents an LSP error
 type ResponseError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
@@ -96,19 +99,51 @@ func NewClient(serverCmd string, args ...string) (*Client, error) {
 		pendingCalls:  make(map[int64]chan *Response),
 		notifications: make(chan *Notification, 100),
 		shutdown:      make(chan struct{}),
+		diagnostics:   make(map[string][]Diagnostic),
 	}
 
 	// Start message reader
 	go client.readMessages()
+	go client.watchNotifications()
 
 	return client, nil
 }
 
+// watchNotifications drains c.notifications for the whole life of the
+// client, capturing textDocument/publishDiagnostics so GetDiagnostics can
+// answer without the caller having to pump the channel itself. Any other
+// notification type is dropped - nothing outside diagnostics is consumed
+// today.
+func (c *Client) watchNotifications() {
+	for notif := range c.notifications {
+		if notif.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(notif.Params, &params); err != nil {
+			continue
+		}
+
+		c.diagMu.Lock()
+		c.diagnostics[params.URI] = params.Diagnostics
+		c.diagMu.Unlock()
+	}
+}
+
+// GetDiagnostics returns the most recent diagnostics the server published
+// for uri, or nil if none have arrived yet.
+func (c *Client) GetDiagnostics(uri string) []Diagnostic {
+	c.diagMu.RLock()
+	defer c.diagMu.RUnlock()
+	return append([]Diagnostic{}, c.diagnostics[uri]...)
+}
+
 // Initialize initializes the LSP session
 func (c *Client) Initialize(ctx context.Context, rootURI string, capabilities ClientCapabilities) (*InitializeResult, error) {
 	params := InitializeParams{
-		ProcessID: nil, // Use nil for unknown process
-		RootURI:   rootURI,
+		ProcessID:    nil, // Use nil for unknown process
+		RootURI:      rootURI,
 		Capabilities: capabilities,
 	}
 
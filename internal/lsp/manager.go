@@ -211,6 +211,54 @@ func (m *Manager) GetHover(ctx context.Context, filePath string, line, character
 	return client.GetHover(ctx, uri, line, character)
 }
 
+// GetDiagnostics returns the most recently published diagnostics for
+// filePath, opening it (and starting its language server) first if
+// necessary so a first-ever call doesn't just return nothing.
+func (m *Manager) GetDiagnostics(ctx context.Context, filePath string) ([]Diagnostic, error) {
+	client, _, err := m.GetClientForFile(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.OpenFile(filePath, m.getLanguageID(filePath)); err != nil {
+		return nil, err
+	}
+
+	return client.GetDiagnostics(pathToURI(filePath)), nil
+}
+
+// Rename requests a rename of the symbol at (line, character) in filePath
+// to newName, opening the file first if necessary.
+func (m *Manager) Rename(ctx context.Context, filePath string, line, character int, newName string) (*WorkspaceEdit, error) {
+	client, _, err := m.GetClientForFile(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.OpenFile(filePath, m.getLanguageID(filePath)); err != nil {
+		return nil, err
+	}
+
+	uri := pathToURI(filePath)
+	return client.Rename(ctx, uri, line, character, newName)
+}
+
+// GetCodeActions requests the code actions available for a line range in
+// filePath, opening the file first if necessary.
+func (m *Manager) GetCodeActions(ctx context.Context, filePath string, startLine, startChar, endLine, endChar int) ([]CodeAction, error) {
+	client, _, err := m.GetClientForFile(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.OpenFile(filePath, m.getLanguageID(filePath)); err != nil {
+		return nil, err
+	}
+
+	uri := pathToURI(filePath)
+	return client.GetCodeActions(ctx, uri, Position{Line: startLine, Character: startChar}, Position{Line: endLine, Character: endChar})
+}
+
 // getLanguageID returns the language ID for a file
 func (m *Manager) getLanguageID(filePath string) string {
 	ext := strings.ToLower(filepath.Ext(filePath))
@@ -250,6 +298,14 @@ func (m *Manager) ValidateServers() map[string]bool {
 	return results
 }
 
+// ActiveClientCount returns the number of language servers currently
+// running, for diagnostics/stats reporting.
+func (m *Manager) ActiveClientCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.clients)
+}
+
 // Shutdown shuts down all LSP clients
 func (m *Manager) Shutdown(ctx context.Context) error {
 	m.mu.Lock()
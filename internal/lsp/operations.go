@@ -134,6 +134,62 @@ func (c *Client) GetHover(ctx context.Context, uri string, line, character int)
 	return &hover, nil
 }
 
+// Rename requests textDocument/rename, returning the WorkspaceEdit the
+// server proposes for renaming the symbol at (line, character) to newName.
+func (c *Client) Rename(ctx context.Context, uri string, line, character int, newName string) (*WorkspaceEdit, error) {
+	params := RenameParams{
+		TextDocumentPositionParams: TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: uri},
+			Position:     Position{Line: line, Character: character},
+		},
+		NewName: newName,
+	}
+
+	var result json.RawMessage
+	if err := c.Call(ctx, "textDocument/rename", params, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 || string(result) == "null" {
+		return &WorkspaceEdit{}, nil
+	}
+
+	var edit WorkspaceEdit
+	if err := json.Unmarshal(result, &edit); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rename result: %w", err)
+	}
+
+	return &edit, nil
+}
+
+// GetCodeActions requests textDocument/codeAction for a range, returning
+// the actions the server offers (quick fixes, refactors, etc). Diagnostics
+// already known for uri are included in the request context, since servers
+// use them to decide which quick fixes apply.
+func (c *Client) GetCodeActions(ctx context.Context, uri string, start, end Position) ([]CodeAction, error) {
+	params := CodeActionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Range:        Range{Start: start, End: end},
+		Context:      CodeActionContext{Diagnostics: c.GetDiagnostics(uri)},
+	}
+
+	var result json.RawMessage
+	if err := c.Call(ctx, "textDocument/codeAction", params, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 || string(result) == "null" {
+		return []CodeAction{}, nil
+	}
+
+	var actions []CodeAction
+	if err := json.Unmarshal(result, &actions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal code action result: %w", err)
+	}
+
+	return actions, nil
+}
+
 // DidOpenTextDocument notifies the server that a document was opened
 func (c *Client) DidOpenTextDocument(uri, languageID, text string) error {
 	params := DidOpenTextDocumentParams{
@@ -8,12 +8,12 @@ type ClientCapabilities struct {
 
 // TextDocumentClientCapabilities represents text document capabilities
 type TextDocumentClientCapabilities struct {
-	Definition         *DefinitionCapability         `json:"definition,omitempty"`
-	References         *ReferencesCapability         `json:"references,omitempty"`
-	DocumentSymbol     *DocumentSymbolCapability     `json:"documentSymbol,omitempty"`
-	Hover              *HoverCapability              `json:"hover,omitempty"`
-	Implementation     *ImplementationCapability     `json:"implementation,omitempty"`
-	TypeDefinition     *TypeDefinitionCapability     `json:"typeDefinition,omitempty"`
+	Definition     *DefinitionCapability     `json:"definition,omitempty"`
+	References     *ReferencesCapability     `json:"references,omitempty"`
+	DocumentSymbol *DocumentSymbolCapability `json:"documentSymbol,omitempty"`
+	Hover          *HoverCapability          `json:"hover,omitempty"`
+	Implementation *ImplementationCapability `json:"implementation,omitempty"`
+	TypeDefinition *TypeDefinitionCapability `json:"typeDefinition,omitempty"`
 }
 
 // WorkspaceClientCapabilities represents workspace capabilities
@@ -34,7 +34,7 @@ type ReferencesCapability struct {
 
 // DocumentSymbolCapability represents document symbol capability
 type DocumentSymbolCapability struct {
-	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
+	DynamicRegistration               bool `json:"dynamicRegistration,omitempty"`
 	HierarchicalDocumentSymbolSupport bool `json:"hierarchicalDocumentSymbolSupport,omitempty"`
 }
 
@@ -45,7 +45,7 @@ type HoverCapability struct {
 }
 
 // ImplementationCapability represents implementation capability
-type ImplementationCapability struct{
+type ImplementationCapability struct {
 	DynamicRegistration bool `json:"dynamicRegistration,omitempty"`
 	LinkSupport         bool `json:"linkSupport,omitempty"`
 }
@@ -75,14 +75,14 @@ type InitializeResult struct {
 
 // ServerCapabilities represents server capabilities
 type ServerCapabilities struct {
-	TextDocumentSync   interface{}                  `json:"textDocumentSync,omitempty"`
-	DefinitionProvider bool                         `json:"definitionProvider,omitempty"`
-	ReferencesProvider bool                         `json:"referencesProvider,omitempty"`
-	DocumentSymbolProvider bool                     `json:"documentSymbolProvider,omitempty"`
-	WorkspaceSymbolProvider bool                    `json:"workspaceSymbolProvider,omitempty"`
-	HoverProvider      bool                         `json:"hoverProvider,omitempty"`
-	ImplementationProvider bool                     `json:"implementationProvider,omitempty"`
-	TypeDefinitionProvider bool                     `json:"typeDefinitionProvider,omitempty"`
+	TextDocumentSync        interface{} `json:"textDocumentSync,omitempty"`
+	DefinitionProvider      bool        `json:"definitionProvider,omitempty"`
+	ReferencesProvider      bool        `json:"referencesProvider,omitempty"`
+	DocumentSymbolProvider  bool        `json:"documentSymbolProvider,omitempty"`
+	WorkspaceSymbolProvider bool        `json:"workspaceSymbolProvider,omitempty"`
+	HoverProvider           bool        `json:"hoverProvider,omitempty"`
+	ImplementationProvider  bool        `json:"implementationProvider,omitempty"`
+	TypeDefinitionProvider  bool        `json:"typeDefinitionProvider,omitempty"`
 }
 
 // Position represents a position in a text document
@@ -108,6 +108,89 @@ type TextDocumentIdentifier struct {
 	URI string `json:"uri"`
 }
 
+// TextEdit represents a textual change to a range within one document.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// WorkspaceEdit represents a set of edits across one or more documents,
+// returned by textDocument/rename and by code actions that edit text
+// directly rather than running a server-side command.
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes,omitempty"` // uri -> edits
+}
+
+// RenameParams represents parameters for textDocument/rename
+type RenameParams struct {
+	TextDocumentPositionParams
+	NewName string `json:"newName"`
+}
+
+// CodeActionContext represents the context sent with a codeAction request
+type CodeActionContext struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// CodeActionParams represents parameters for textDocument/codeAction
+type CodeActionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      CodeActionContext      `json:"context"`
+}
+
+// CodeAction represents a code action the server offers for a range, e.g.
+// a quick fix or refactor. Command-based actions (Edit nil) aren't
+// executed - only actions with a direct Edit can be applied today.
+type CodeAction struct {
+	Title string         `json:"title"`
+	Kind  string         `json:"kind,omitempty"`
+	Edit  *WorkspaceEdit `json:"edit,omitempty"`
+}
+
+// DiagnosticSeverity mirrors the LSP spec's 1-4 severity scale (Error is 1).
+type DiagnosticSeverity int
+
+const (
+	DiagnosticSeverityError       DiagnosticSeverity = 1
+	DiagnosticSeverityWarning     DiagnosticSeverity = 2
+	DiagnosticSeverityInformation DiagnosticSeverity = 3
+	DiagnosticSeverityHint        DiagnosticSeverity = 4
+)
+
+func (s DiagnosticSeverity) String() string {
+	switch s {
+	case DiagnosticSeverityError:
+		return "error"
+	case DiagnosticSeverityWarning:
+		return "warning"
+	case DiagnosticSeverityInformation:
+		return "information"
+	case DiagnosticSeverityHint:
+		return "hint"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic represents a compiler error, warning, or other issue the
+// language server reports for a range in a document.
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity,omitempty"`
+	Code     interface{}        `json:"code,omitempty"`
+	Source   string             `json:"source,omitempty"`
+	Message  string             `json:"message"`
+}
+
+// PublishDiagnosticsParams is sent by the server via
+// textDocument/publishDiagnostics whenever a document's diagnostics change.
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     int          `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
 // TextDocumentPositionParams represents text document position parameters
 type TextDocumentPositionParams struct {
 	TextDocument TextDocumentIdentifier `json:"textDocument"`
@@ -4,8 +4,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
 	"time"
 
+	"github.com/jake/gocode/internal/secrets"
 	_ "modernc.org/sqlite"
 )
 
@@ -18,11 +22,11 @@ type LongTermMemory struct {
 type MemoryType string
 
 const (
-	TypeFact      MemoryType = "fact"      // Learned facts about the codebase
-	TypeArtifact  MemoryType = "artifact"  // Stored artifacts (patches, logs, etc.)
-	TypeDecision  MemoryType = "decision"  // Important decisions made
-	TypePattern   MemoryType = "pattern"   // Recognized patterns
-	TypeError     MemoryType = "error"     // Errors and their solutions
+	TypeFact     MemoryType = "fact"     // Learned facts about the codebase
+	TypeArtifact MemoryType = "artifact" // Stored artifacts (patches, logs, etc.)
+	TypeDecision MemoryType = "decision" // Important decisions made
+	TypePattern  MemoryType = "pattern"  // Recognized patterns
+	TypeError    MemoryType = "error"    // Errors and their solutions
 )
 
 // Memory represents a long-term memory entry
@@ -36,9 +40,38 @@ type Memory struct {
 	CreatedAt   time.Time              `json:"created_at"`
 	AccessCount int                    `json:"access_count"`
 	LastAccess  time.Time              `json:"last_access"`
-	Importance  float32                `json:"importance"` // 0-1 score
-	TraceID     string                 `json:"trace_id,omitempty"`     // Link to OTel trace
-	ArtifactID  string                 `json:"artifact_id,omitempty"`  // Link to artifact
+	Importance  float32                `json:"importance"`            // 0-1 score
+	TraceID     string                 `json:"trace_id,omitempty"`    // Link to OTel trace
+	ArtifactID  string                 `json:"artifact_id,omitempty"` // Link to artifact
+	Author      string                 `json:"author,omitempty"`      // Who stored this, for shared-workspace attribution
+}
+
+// CurrentUser identifies the person driving this session, for attributing
+// stored records in a workspace shared by several developers. It prefers
+// git config (since gocode already assumes a git checkout) and falls back
+// to the OS user if git isn't configured.
+func CurrentUser() string {
+	if name := gitConfigValue("user.email"); name != "" {
+		return name
+	}
+	if name := gitConfigValue("user.name"); name != "" {
+		return name
+	}
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	if u := os.Getenv("USERNAME"); u != "" {
+		return u
+	}
+	return "unknown"
+}
+
+func gitConfigValue(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
 }
 
 // NewLongTermMemory creates a new long-term memory store
@@ -72,13 +105,15 @@ func (ltm *LongTermMemory) initSchema() error {
 		last_access DATETIME,
 		importance REAL DEFAULT 0.5,
 		trace_id TEXT,
-		artifact_id TEXT
+		artifact_id TEXT,
+		author TEXT
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_memory_type ON memories(type);
 	CREATE INDEX IF NOT EXISTS idx_memory_tags ON memories(tags);
 	CREATE INDEX IF NOT EXISTS idx_memory_importance ON memories(importance DESC);
 	CREATE INDEX IF NOT EXISTS idx_memory_created ON memories(created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_memory_author ON memories(author);
 
 	CREATE VIRTUAL TABLE IF NOT EXISTS memory_fts USING fts5(
 		id UNINDEXED,
@@ -94,12 +129,19 @@ func (ltm *LongTermMemory) initSchema() error {
 
 // Store stores a memory
 func (ltm *LongTermMemory) Store(memory *Memory) error {
+	if secrets.ContainsSecret(memory.Content) || secrets.ContainsSecret(memory.Summary) {
+		return fmt.Errorf("refusing to store memory: content looks like it contains a credential")
+	}
+
 	if memory.ID == "" {
 		memory.ID = generateMemoryID()
 	}
 	if memory.CreatedAt.IsZero() {
 		memory.CreatedAt = time.Now()
 	}
+	if memory.Author == "" {
+		memory.Author = CurrentUser()
+	}
 
 	tagsJSON, _ := json.Marshal(memory.Tags)
 	metadataJSON, _ := json.Marshal(memory.Metadata)
@@ -113,10 +155,10 @@ func (ltm *LongTermMemory) Store(memory *Memory) error {
 	// Insert into main table
 	_, err = tx.Exec(`
 		INSERT OR REPLACE INTO memories
-		(id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		(id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id, author)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, memory.ID, memory.Type, memory.Content, memory.Summary, string(tagsJSON), string(metadataJSON),
-		memory.CreatedAt, memory.AccessCount, memory.LastAccess, memory.Importance, memory.TraceID, memory.ArtifactID)
+		memory.CreatedAt, memory.AccessCount, memory.LastAccess, memory.Importance, memory.TraceID, memory.ArtifactID, memory.Author)
 
 	if err != nil {
 		return err
@@ -139,7 +181,7 @@ func (ltm *LongTermMemory) Store(memory *Memory) error {
 func (ltm *LongTermMemory) Search(query string, limit int) ([]*Memory, error) {
 	rows, err := ltm.db.Query(`
 		SELECT m.id, m.type, m.content, m.summary, m.tags, m.metadata,
-		       m.created_at, m.access_count, m.last_access, m.importance, m.trace_id, m.artifact_id
+		       m.created_at, m.access_count, m.last_access, m.importance, m.trace_id, m.artifact_id, m.author
 		FROM memories m
 		INNER JOIN memory_fts fts ON m.id = fts.id
 		WHERE memory_fts MATCH ?
@@ -162,10 +204,10 @@ func (ltm *LongTermMemory) Get(id string) (*Memory, error) {
 	var lastAccess sql.NullTime
 
 	err := ltm.db.QueryRow(`
-		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id
+		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id, author
 		FROM memories WHERE id = ?
 	`, id).Scan(&memory.ID, &memory.Type, &memory.Content, &memory.Summary, &tagsJSON, &metadataJSON,
-		&memory.CreatedAt, &memory.AccessCount, &lastAccess, &memory.Importance, &memory.TraceID, &memory.ArtifactID)
+		&memory.CreatedAt, &memory.AccessCount, &lastAccess, &memory.Importance, &memory.TraceID, &memory.ArtifactID, &memory.Author)
 
 	if err != nil {
 		return nil, err
@@ -184,10 +226,33 @@ func (ltm *LongTermMemory) Get(id string) (*Memory, error) {
 	return &memory, nil
 }
 
+// AdjustImportance nudges a memory's importance by delta (positive or
+// negative), clamped to [0, 1], and returns the resulting value. Backs the
+// /good and /bad feedback commands, which are the only calibration signal
+// the otherwise hard-coded 0.5-0.8 importance constants get today.
+func (ltm *LongTermMemory) AdjustImportance(id string, delta float32) (float32, error) {
+	mem, err := ltm.Get(id)
+	if err != nil {
+		return 0, err
+	}
+
+	importance := mem.Importance + delta
+	if importance > 1 {
+		importance = 1
+	} else if importance < 0 {
+		importance = 0
+	}
+
+	if _, err := ltm.db.Exec(`UPDATE memories SET importance = ? WHERE id = ?`, importance, id); err != nil {
+		return 0, err
+	}
+	return importance, nil
+}
+
 // GetByType retrieves memories by type
 func (ltm *LongTermMemory) GetByType(memType MemoryType, limit int) ([]*Memory, error) {
 	rows, err := ltm.db.Query(`
-		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id
+		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id, author
 		FROM memories
 		WHERE type = ?
 		ORDER BY importance DESC, created_at DESC
@@ -226,7 +291,7 @@ func (ltm *LongTermMemory) GetByTags(tags []string, limit int) ([]*Memory, error
 // GetRecent retrieves recent memories
 func (ltm *LongTermMemory) GetRecent(limit int) ([]*Memory, error) {
 	rows, err := ltm.db.Query(`
-		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id
+		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id, author
 		FROM memories
 		ORDER BY created_at DESC
 		LIMIT ?
@@ -243,7 +308,7 @@ func (ltm *LongTermMemory) GetRecent(limit int) ([]*Memory, error) {
 // GetMostImportant retrieves most important memories
 func (ltm *LongTermMemory) GetMostImportant(limit int) ([]*Memory, error) {
 	rows, err := ltm.db.Query(`
-		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id
+		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id, author
 		FROM memories
 		ORDER BY importance DESC, access_count DESC
 		LIMIT ?
@@ -257,6 +322,26 @@ func (ltm *LongTermMemory) GetMostImportant(limit int) ([]*Memory, error) {
 	return ltm.scanMemories(rows)
 }
 
+// GetByAuthor retrieves memories stored by a specific user, for shared
+// workspaces where several developers' sessions write to the same
+// memory.db (backs `/memory list --mine`).
+func (ltm *LongTermMemory) GetByAuthor(author string, limit int) ([]*Memory, error) {
+	rows, err := ltm.db.Query(`
+		SELECT id, type, content, summary, tags, metadata, created_at, access_count, last_access, importance, trace_id, artifact_id, author
+		FROM memories
+		WHERE author = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, author, limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return ltm.scanMemories(rows)
+}
+
 // Delete deletes a memory
 func (ltm *LongTermMemory) Delete(id string) error {
 	tx, err := ltm.db.Begin()
@@ -304,10 +389,10 @@ func (ltm *LongTermMemory) scanMemories(rows *sql.Rows) ([]*Memory, error) {
 		var memory Memory
 		var tagsJSON, metadataJSON string
 		var lastAccess sql.NullTime
-		var traceID, artifactID sql.NullString
+		var traceID, artifactID, author sql.NullString
 
 		err := rows.Scan(&memory.ID, &memory.Type, &memory.Content, &memory.Summary, &tagsJSON, &metadataJSON,
-			&memory.CreatedAt, &memory.AccessCount, &lastAccess, &memory.Importance, &traceID, &artifactID)
+			&memory.CreatedAt, &memory.AccessCount, &lastAccess, &memory.Importance, &traceID, &artifactID, &author)
 
 		if err != nil {
 			return nil, err
@@ -325,6 +410,9 @@ func (ltm *LongTermMemory) scanMemories(rows *sql.Rows) ([]*Memory, error) {
 		if artifactID.Valid {
 			memory.ArtifactID = artifactID.String
 		}
+		if author.Valid {
+			memory.Author = author.String
+		}
 
 		memories = append(memories, &memory)
 	}
@@ -363,3 +451,11 @@ func generateMemoryID() string {
 func (ltm *LongTermMemory) Close() error {
 	return ltm.db.Close()
 }
+
+// Count returns the total number of stored memory rows, for diagnostics/stats
+// reporting.
+func (ltm *LongTermMemory) Count() (int, error) {
+	var count int
+	err := ltm.db.QueryRow("SELECT COUNT(*) FROM memories").Scan(&count)
+	return count, err
+}
@@ -0,0 +1,212 @@
+// Package mockllm serves a scripted, OpenAI-compatible chat completions
+// endpoint, so the agent loop, confirmation flow, checkpointing, and
+// context pruning can be exercised end to end in CI (or in a config
+// dry-run) without a real model. llm.Client talks to it exactly like any
+// other OpenAI-compatible endpoint - it never needs to know the difference.
+package mockllm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"gopkg.in/yaml.v3"
+)
+
+// ScriptedToolCall is one tool call a scripted response asks the model to
+// make.
+type ScriptedToolCall struct {
+	Name      string `yaml:"name"`
+	Arguments string `yaml:"arguments"` // raw JSON, passed through as the tool call's arguments string
+}
+
+// ScriptedResponse is one turn's worth of scripted assistant output. A
+// response with ToolCalls set produces a tool-call finish; otherwise
+// Content is returned as a normal assistant message.
+type ScriptedResponse struct {
+	Content   string             `yaml:"content"`
+	ToolCalls []ScriptedToolCall `yaml:"tool_calls"`
+}
+
+// Script is the top-level YAML document a mock server is loaded from.
+type Script struct {
+	Responses []ScriptedResponse `yaml:"responses"`
+	Loop      bool               `yaml:"loop"` // when the responses are exhausted, start over instead of erroring
+}
+
+// LoadScript reads and parses a Script from a YAML file.
+func LoadScript(path string) (Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Script{}, fmt.Errorf("failed to read mock script %s: %w", path, err)
+	}
+
+	var script Script
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return Script{}, fmt.Errorf("failed to parse mock script %s: %w", path, err)
+	}
+	if len(script.Responses) == 0 {
+		return Script{}, fmt.Errorf("mock script %s defines no responses", path)
+	}
+	return script, nil
+}
+
+// Server serves Script's responses in order over an OpenAI-compatible
+// /v1/chat/completions endpoint (streaming and non-streaming) plus /v1/models
+// and /health endpoints that answer with a one-model list; /health is the
+// path llm.ServerManager's readiness check polls.
+type Server struct {
+	script Script
+	mu     sync.Mutex
+	next   int
+	server *http.Server
+}
+
+// NewServer creates a Server that hasn't started listening yet.
+func NewServer(script Script) *Server {
+	return &Server{script: script}
+}
+
+// Start begins serving on addr (host:port) in the background. It waits
+// 100ms and returns the bind error if ListenAndServe failed in that time,
+// nil otherwise; it does not probe the listener.
+func (s *Server) Start(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/models", s.handleModels)
+	mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("/health", s.handleModels)
+
+	s.server = &http.Server{Addr: addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	// Give ListenAndServe a moment to either bind or fail (e.g. port in
+	// use) before reporting success.
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("mock LLM server failed to start: %w", err)
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+}
+
+// Stop shuts the server down.
+func (s *Server) Stop() error {
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `{"object":"list","data":[{"id":"mock","object":"model"}]}`)
+}
+
+// nextResponse returns the next scripted response, advancing (and, if Loop
+// is set, wrapping) the cursor. The second return is false once a
+// non-looping script is exhausted.
+func (s *Server) nextResponse() (ScriptedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next >= len(s.script.Responses) {
+		if !s.script.Loop {
+			return ScriptedResponse{}, false
+		}
+		s.next = 0
+	}
+
+	resp := s.script.Responses[s.next]
+	s.next++
+	return resp, true
+}
+
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req openai.ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scripted, ok := s.nextResponse()
+	if !ok {
+		http.Error(w, "mock script exhausted: no more scripted responses", http.StatusServiceUnavailable)
+		return
+	}
+
+	message := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: scripted.Content}
+	finishReason := openai.FinishReasonStop
+	if len(scripted.ToolCalls) > 0 {
+		finishReason = openai.FinishReasonToolCalls
+		for i, tc := range scripted.ToolCalls {
+			message.ToolCalls = append(message.ToolCalls, openai.ToolCall{
+				ID:   fmt.Sprintf("mock-call-%d-%d", s.next, i),
+				Type: openai.ToolTypeFunction,
+				Function: openai.FunctionCall{
+					Name:      tc.Name,
+					Arguments: tc.Arguments,
+				},
+			})
+		}
+	}
+
+	if req.Stream {
+		s.writeStream(w, message, finishReason)
+		return
+	}
+
+	resp := openai.ChatCompletionResponse{
+		ID:      "mock-completion",
+		Object:  "chat.completion",
+		Model:   req.Model,
+		Choices: []openai.ChatCompletionChoice{{Index: 0, Message: message, FinishReason: finishReason}},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeStream renders message as a single-chunk OpenAI SSE stream. Real
+// servers deliver content token by token; scripted output doesn't need
+// to, since nothing about the mock is testing streaming latency.
+func (s *Server) writeStream(w http.ResponseWriter, message openai.ChatCompletionMessage, finishReason openai.FinishReason) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	chunk := openai.ChatCompletionStreamResponse{
+		ID:     "mock-completion",
+		Object: "chat.completion.chunk",
+		Choices: []openai.ChatCompletionStreamChoice{{
+			Index: 0,
+			Delta: openai.ChatCompletionStreamChoiceDelta{
+				Role:      message.Role,
+				Content:   message.Content,
+				ToolCalls: message.ToolCalls,
+			},
+			FinishReason: finishReason,
+		}},
+	}
+	data, _ := json.Marshal(chunk)
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
@@ -0,0 +1,50 @@
+// Package notify sends best-effort OS desktop notifications by shelling out
+// to whatever notifier ships with the platform, so long local-model
+// generations can flag completion without pulling in a cross-platform
+// notification library.
+package notify
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Notifier sends a desktop notification. Failures are non-fatal to callers
+// by design — a missing notify-send binary shouldn't break the REPL.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// NewNotifier returns a platform notifier, or a no-op if disabled.
+func NewNotifier(enabled bool) Notifier {
+	if !enabled {
+		return noopNotifier{}
+	}
+	return osNotifier{}
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) Notify(title, message string) error { return nil }
+
+// osNotifier shells out to the platform's native notifier.
+type osNotifier struct{}
+
+func (osNotifier) Notify(title, message string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("notify-send", title, message)
+	case "darwin":
+		script := `display notification "` + message + `" with title "` + title + `"`
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := `New-BurntToastNotification -Text '` + title + `', '` + message + `'`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		return nil
+	}
+
+	return cmd.Run()
+}
@@ -21,20 +21,29 @@ type Symbol struct {
 type SymbolKind string
 
 const (
-	KindFunction   SymbolKind = "function"
-	KindMethod     SymbolKind = "method"
-	KindClass      SymbolKind = "class"
-	KindInterface  SymbolKind = "interface"
-	KindStruct     SymbolKind = "struct"
-	KindVariable   SymbolKind = "variable"
-	KindConstant   SymbolKind = "constant"
-	KindImport     SymbolKind = "import"
-	KindPackage    SymbolKind = "package"
-	KindType       SymbolKind = "type"
+	KindFunction  SymbolKind = "function"
+	KindMethod    SymbolKind = "method"
+	KindClass     SymbolKind = "class"
+	KindInterface SymbolKind = "interface"
+	KindStruct    SymbolKind = "struct"
+	KindVariable  SymbolKind = "variable"
+	KindConstant  SymbolKind = "constant"
+	KindImport    SymbolKind = "import"
+	KindPackage   SymbolKind = "package"
+	KindType      SymbolKind = "type"
 )
 
-// SimpleParser is a simple regex-based parser for extracting symbols
-// Used as a fallback when LSP is not available
+// SimpleParser is a line-oriented regex-based parser for extracting symbols.
+// Used as a fallback when LSP is not available.
+//
+// This is deliberately not a real AST/tree-sitter parser - go-tree-sitter
+// and its per-language grammars aren't vendored in this tree, and adding
+// them isn't something this parser can do without network access to fetch
+// and vendor those modules. That means nested functions and symbols whose
+// declaration depends on parsing an enclosing block are still missed; the
+// one gap closed here is multi-line function/method signatures, which are
+// common enough (wrapped parameter lists) to be worth handling with a
+// small amount of paren-balance tracking rather than a full parser.
 type SimpleParser struct {
 	language string
 	patterns map[SymbolKind]*regexp.Regexp
@@ -72,11 +81,23 @@ func (p *SimpleParser) Parse(source string) []Symbol {
 		// Try to match symbols
 		for kind, pattern := range p.patterns {
 			if matches := pattern.FindStringSubmatch(line); matches != nil {
+				declLine := lineNum
+				signature := strings.TrimSpace(line)
+
+				// A function/method whose parameter list wraps onto
+				// following lines still has an unbalanced "(" here -
+				// pull in lines until it closes so Signature reflects the
+				// whole declaration instead of just its first line.
+				if (kind == KindFunction || kind == KindMethod) && strings.Count(signature, "(") > strings.Count(signature, ")") {
+					signature, lineNum = extendSignature(scanner, signature, lineNum)
+				}
+
 				symbol := Symbol{
 					Kind:      kind,
-					Line:      lineNum,
+					Line:      declLine,
 					Column:    strings.Index(line, matches[0]),
-					Signature: strings.TrimSpace(line),
+					EndLine:   lineNum,
+					Signature: signature,
 					DocString: strings.TrimSpace(currentDoc.String()),
 				}
 
@@ -94,6 +115,18 @@ func (p *SimpleParser) Parse(source string) []Symbol {
 	return symbols
 }
 
+// extendSignature reads additional lines from scanner and appends them to
+// signature until its parentheses balance (or the scanner runs out),
+// returning the full signature and the line number it ended on.
+func extendSignature(scanner *bufio.Scanner, signature string, startLine int) (string, int) {
+	line := startLine
+	for strings.Count(signature, "(") > strings.Count(signature, ")") && scanner.Scan() {
+		line++
+		signature += " " + strings.TrimSpace(scanner.Text())
+	}
+	return signature, line
+}
+
 // getPatterns returns regex patterns for a language
 func getPatterns(language string) map[SymbolKind]*regexp.Regexp {
 	switch language {
@@ -103,6 +136,18 @@ func getPatterns(language string) map[SymbolKind]*regexp.Regexp {
 		return getPythonPatterns()
 	case "javascript", "typescript":
 		return getJavaScriptPatterns()
+	case "java":
+		return getJavaPatterns()
+	case "ruby":
+		return getRubyPatterns()
+	case "php":
+		return getPHPPatterns()
+	case "csharp":
+		return getCSharpPatterns()
+	case "rust":
+		return getRustPatterns()
+	case "c", "cpp":
+		return getCFamilyPatterns()
 	default:
 		return make(map[SymbolKind]*regexp.Regexp)
 	}
@@ -144,6 +189,67 @@ func getJavaScriptPatterns() map[SymbolKind]*regexp.Regexp {
 	}
 }
 
+// getJavaPatterns returns patterns for Java
+func getJavaPatterns() map[SymbolKind]*regexp.Regexp {
+	return map[SymbolKind]*regexp.Regexp{
+		KindImport:    regexp.MustCompile(`^import\s+(?:static\s+)?([\w.]+);`),
+		KindInterface: regexp.MustCompile(`^\s*(?:public|private|protected)?\s*interface\s+(\w+)`),
+		KindClass:     regexp.MustCompile(`^\s*(?:public|private|protected)?\s*(?:abstract\s+|final\s+)?class\s+(\w+)`),
+		KindMethod:    regexp.MustCompile(`^\s*(?:public|private|protected)\s+(?:static\s+)?(?:final\s+)?[\w<>\[\]]+\s+(\w+)\s*\([^;]*$`),
+	}
+}
+
+// getRubyPatterns returns patterns for Ruby
+func getRubyPatterns() map[SymbolKind]*regexp.Regexp {
+	return map[SymbolKind]*regexp.Regexp{
+		KindImport:   regexp.MustCompile(`^\s*require(?:_relative)?\s+['"](.+)['"]`),
+		KindClass:    regexp.MustCompile(`^\s*class\s+(\w+)`),
+		KindType:     regexp.MustCompile(`^\s*module\s+(\w+)`),
+		KindFunction: regexp.MustCompile(`^\s*def\s+(?:self\.)?(\w+[?!]?)`),
+	}
+}
+
+// getPHPPatterns returns patterns for PHP
+func getPHPPatterns() map[SymbolKind]*regexp.Regexp {
+	return map[SymbolKind]*regexp.Regexp{
+		KindImport:    regexp.MustCompile(`^\s*use\s+([\w\\]+)`),
+		KindInterface: regexp.MustCompile(`^\s*interface\s+(\w+)`),
+		KindClass:     regexp.MustCompile(`^\s*(?:abstract\s+|final\s+)?class\s+(\w+)`),
+		KindFunction:  regexp.MustCompile(`^\s*(?:public|private|protected)?\s*(?:static\s+)?function\s+(\w+)\s*\(`),
+	}
+}
+
+// getCSharpPatterns returns patterns for C#
+func getCSharpPatterns() map[SymbolKind]*regexp.Regexp {
+	return map[SymbolKind]*regexp.Regexp{
+		KindImport:    regexp.MustCompile(`^\s*using\s+([\w.]+);`),
+		KindInterface: regexp.MustCompile(`^\s*(?:public|private|internal)?\s*interface\s+(\w+)`),
+		KindClass:     regexp.MustCompile(`^\s*(?:public|private|internal)?\s*(?:abstract\s+|sealed\s+|static\s+)?class\s+(\w+)`),
+		KindMethod:    regexp.MustCompile(`^\s*(?:public|private|protected|internal)\s+(?:static\s+)?(?:async\s+)?[\w<>\[\]]+\s+(\w+)\s*\([^;]*$`),
+	}
+}
+
+// getRustPatterns returns patterns for Rust
+func getRustPatterns() map[SymbolKind]*regexp.Regexp {
+	return map[SymbolKind]*regexp.Regexp{
+		KindImport:    regexp.MustCompile(`^\s*use\s+([\w:]+)`),
+		KindStruct:    regexp.MustCompile(`^\s*(?:pub\s+)?struct\s+(\w+)`),
+		KindInterface: regexp.MustCompile(`^\s*(?:pub\s+)?trait\s+(\w+)`),
+		KindType:      regexp.MustCompile(`^\s*(?:pub\s+)?enum\s+(\w+)`),
+		KindFunction:  regexp.MustCompile(`^\s*(?:pub\s+)?(?:async\s+)?fn\s+(\w+)`),
+	}
+}
+
+// getCFamilyPatterns returns patterns shared by C and C++
+func getCFamilyPatterns() map[SymbolKind]*regexp.Regexp {
+	return map[SymbolKind]*regexp.Regexp{
+		KindImport:   regexp.MustCompile(`^\s*#include\s+[<"](.+)[>"]`),
+		KindStruct:   regexp.MustCompile(`^\s*(?:typedef\s+)?struct\s+(\w+)`),
+		KindClass:    regexp.MustCompile(`^\s*class\s+(\w+)`),
+		KindFunction: regexp.MustCompile(`^\s*(?:static\s+)?[\w<>:*&]+\s+(\w+)\s*\([^;]*$`),
+	}
+}
+
 // FindSymbolByName finds a symbol by name in the parsed symbols
 func FindSymbolByName(symbols []Symbol, name string) *Symbol {
 	for i := range symbols {
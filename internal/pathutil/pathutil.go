@@ -0,0 +1,39 @@
+// Package pathutil resolves and validates the paths tools receive from the
+// model against the workspace root, so a tool works the same whether the
+// model hands it an absolute path, a relative one, or one with a leading
+// "./" - and so a path that tries to escape the workspace (e.g.
+// "../../etc/passwd") is rejected before any tool touches the filesystem.
+package pathutil
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Normalize resolves path against workingDir and returns it as a
+// workspace-relative path using "/" separators, so it reads the same way
+// on every OS and matches what tools already emit for paths they discover
+// themselves (filepath.Walk over a relative root). An empty path
+// normalizes to ".", the workspace root itself.
+func Normalize(workingDir, path string) (string, error) {
+	if path == "" {
+		return ".", nil
+	}
+
+	abs := path
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(workingDir, abs)
+	}
+	abs = filepath.Clean(abs)
+
+	rel, err := filepath.Rel(workingDir, abs)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve %q against the workspace: %w", path, err)
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside the workspace", path)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
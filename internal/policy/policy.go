@@ -0,0 +1,123 @@
+// Package policy loads and enforces a repo-committed .gocode/policy.yaml
+// declaring guardrails (forbidden paths/commands, a per-turn file-change
+// cap, and checks required before committing) so a team can ship agent
+// safety rules alongside the code instead of relying on each contributor's
+// local confirmation settings.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is the parsed contents of .gocode/policy.yaml.
+type Policy struct {
+	ForbiddenPaths    []string             `yaml:"forbidden_paths"`    // glob patterns (filepath.Match) rejected for write/edit/delete
+	ForbiddenCommands []string             `yaml:"forbidden_commands"` // regexes rejected for bash
+	MaxFilesPerTurn   int                  `yaml:"max_files_per_turn"` // 0 = unlimited
+	RequiredChecks    []string             `yaml:"required_checks"`    // commands that must succeed before a commit is allowed
+	LicenseHeader     *LicenseHeaderPolicy `yaml:"license_header"`     // auto-injected into files the "write" tool creates
+
+	compiledCommands []*regexp.Regexp
+}
+
+// LicenseHeaderPolicy declares the header new files must carry.
+type LicenseHeaderPolicy struct {
+	Template   string   `yaml:"template"`   // literal header text, e.g. "// Copyright 2026 Acme Inc.\n// SPDX-License-Identifier: MIT"
+	Extensions []string `yaml:"extensions"` // file extensions this applies to, e.g. [".go", ".py"]; empty = all files
+}
+
+func (l *LicenseHeaderPolicy) appliesTo(path string) bool {
+	if len(l.Extensions) == 0 {
+		return true
+	}
+	ext := filepath.Ext(path)
+	for _, e := range l.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureLicenseHeader returns content with the policy's template prepended
+// if it isn't already present, and whether it changed anything. A nil
+// receiver (no license_header configured) always reports no change.
+func (p *Policy) EnsureLicenseHeader(path, content string) (string, bool) {
+	if p == nil || p.LicenseHeader == nil || !p.LicenseHeader.appliesTo(path) {
+		return content, false
+	}
+	if strings.Contains(content, strings.TrimSpace(p.LicenseHeader.Template)) {
+		return content, false
+	}
+	return strings.TrimRight(p.LicenseHeader.Template, "\n") + "\n\n" + content, true
+}
+
+// Load reads and parses a policy file. A missing file returns os.ReadFile's
+// error unwrapped, so callers can detect it with os.IsNotExist and treat it
+// as "no policy configured".
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	for _, pattern := range p.ForbiddenCommands {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid forbidden_commands pattern %q: %w", pattern, err)
+		}
+		p.compiledCommands = append(p.compiledCommands, re)
+	}
+
+	return &p, nil
+}
+
+// CheckPath returns an error if path matches one of the forbidden path
+// globs, nil otherwise.
+func (p *Policy) CheckPath(path string) error {
+	for _, pattern := range p.ForbiddenPaths {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return fmt.Errorf("path %q is forbidden by policy (matches %q)", path, pattern)
+		}
+		// filepath.Match doesn't cross path separators; also check the base
+		// name so patterns like "*.env" catch nested files.
+		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
+			return fmt.Errorf("path %q is forbidden by policy (matches %q)", path, pattern)
+		}
+	}
+	return nil
+}
+
+// CheckCommand returns an error if command matches one of the forbidden
+// command regexes, nil otherwise.
+func (p *Policy) CheckCommand(command string) error {
+	for i, re := range p.compiledCommands {
+		if re.MatchString(command) {
+			return fmt.Errorf("command matches forbidden pattern %q: %s", p.ForbiddenCommands[i], command)
+		}
+	}
+	return nil
+}
+
+// CheckFileCount returns an error if changedSoFar+1 would exceed
+// MaxFilesPerTurn (0 means unlimited).
+func (p *Policy) CheckFileCount(changedSoFar int) error {
+	if p.MaxFilesPerTurn <= 0 {
+		return nil
+	}
+	if changedSoFar+1 > p.MaxFilesPerTurn {
+		return fmt.Errorf("turn would change %d files, exceeding the policy limit of %d", changedSoFar+1, p.MaxFilesPerTurn)
+	}
+	return nil
+}
@@ -38,6 +38,7 @@ type SystemPromptData struct {
 	EnabledTools      []ToolInfo
 	Features          FeatureFlags
 	ProjectContext    *ProjectContext
+	Locale            config.LocaleConfig
 }
 
 // ProjectContext contains project-specific information for the prompt
@@ -51,6 +52,7 @@ type ProjectContext struct {
 	GitBranch        string
 	TechStack        string
 	Structure        string
+	APISummary       string
 }
 
 // ToolInfo describes a tool for the prompt
@@ -88,6 +90,7 @@ func (pm *PromptManager) RenderSystemWithProject(cfg *config.Config, tools []Too
 		ShellInstructions: shellInstructions,
 		EnabledTools:      tools,
 		ProjectContext:    projectContext,
+		Locale:            cfg.Locale,
 		Features: FeatureFlags{
 			LSP:        cfg.LSP.Enabled,
 			Retrieval:  cfg.Retrieval.Enabled,
@@ -0,0 +1,194 @@
+// Package remotesync pushes and pulls files - the long-term memory DB and
+// .gocode/overview.md - to a user-provided WebDAV location, encrypting them
+// client-side first, so a developer's accumulated project knowledge follows
+// them across machines without the remote host ever seeing plaintext.
+//
+// Only the "webdav" provider is implemented, via plain net/http PUT/GET/
+// MKCOL requests; "s3" is accepted in config but rejected by NewClient until
+// an S3 SDK is added to go.mod.
+package remotesync
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Target identifies the remote location and credentials to sync against.
+type Target struct {
+	Provider string
+	URL      string
+	Username string
+	Password string
+}
+
+// Client encrypts/decrypts files with AES-256-GCM (key derived from a
+// passphrase via SHA-256) and transfers them to/from a WebDAV endpoint.
+type Client struct {
+	target     Target
+	httpClient *http.Client
+	key        [32]byte
+}
+
+// NewClient validates target and passphrase and returns a Client ready to
+// Push/Pull. It performs no network I/O itself.
+func NewClient(target Target, passphrase string) (*Client, error) {
+	if target.Provider != "webdav" {
+		return nil, fmt.Errorf("remote sync provider %q is not supported yet (only \"webdav\" is wired up)", target.Provider)
+	}
+	if target.URL == "" {
+		return nil, fmt.Errorf("sync.url must be set to enable remote sync")
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("sync.encryption_key must be set to enable remote sync")
+	}
+
+	return &Client{
+		target:     target,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		key:        sha256.Sum256([]byte(passphrase)),
+	}, nil
+}
+
+// Push encrypts the file at localPath and PUTs it to name+".enc" under the
+// target URL, creating the target directory first if needed.
+func (c *Client) Push(ctx context.Context, name, localPath string) error {
+	plaintext, err := os.ReadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", localPath, err)
+	}
+
+	ciphertext, err := c.encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", localPath, err)
+	}
+
+	if err := c.mkdirAll(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.remoteURL(name), bytes.NewReader(ciphertext))
+	if err != nil {
+		return fmt.Errorf("failed to build PUT request: %w", err)
+	}
+	c.setAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("upload of %s failed: HTTP %d", name, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Pull downloads name+".enc" from the target URL, decrypts it, and writes
+// the plaintext to localPath.
+func (c *Client) Pull(ctx context.Context, name, localPath string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.remoteURL(name), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build GET request: %w", err)
+	}
+	c.setAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("download of %s failed: HTTP %d", name, resp.StatusCode)
+	}
+
+	ciphertext, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response for %s: %w", name, err)
+	}
+
+	plaintext, err := c.decrypt(ciphertext)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s (wrong encryption key?): %w", name, err)
+	}
+
+	if err := os.WriteFile(localPath, plaintext, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", localPath, err)
+	}
+
+	return nil
+}
+
+// mkdirAll issues a WebDAV MKCOL for the target directory. Most servers
+// return 405 Method Not Allowed if the directory already exists, which is
+// treated as success.
+func (c *Client) mkdirAll(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "MKCOL", c.target.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build MKCOL request: %w", err)
+	}
+	c.setAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to create remote directory: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusConflict {
+		return fmt.Errorf("failed to create remote directory: HTTP %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) remoteURL(name string) string {
+	return strings.TrimRight(c.target.URL, "/") + "/" + name + ".enc"
+}
+
+func (c *Client) setAuth(req *http.Request) {
+	if c.target.Username != "" {
+		req.SetBasicAuth(c.target.Username, c.target.Password)
+	}
+}
+
+func (c *Client) encrypt(plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(c.key[:])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *Client) decrypt(ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(c.key[:])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, body := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, body, nil)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jake/gocode/internal/citation"
 	"github.com/jake/gocode/internal/embeddings"
 )
 
@@ -25,20 +26,20 @@ func DefaultFusionWeights() FusionWeights {
 
 // HybridRetriever combines multiple retrieval methods
 type HybridRetriever struct {
-	bm25Index      *BM25Index
-	trigramIndex   *TrigramIndex
-	embeddingsMgr  *embeddings.Manager
-	weights        FusionWeights
+	bm25Index         *BM25Index
+	trigramIndex      *TrigramIndex
+	embeddingsMgr     *embeddings.Manager
+	weights           FusionWeights
 	useSemanticSearch bool // Only if embeddings are available
 }
 
 // NewHybridRetriever creates a new hybrid retriever
 func NewHybridRetriever(weights FusionWeights, embeddingsMgr *embeddings.Manager) *HybridRetriever {
 	return &HybridRetriever{
-		bm25Index:      NewBM25Index(DefaultBM25Params()),
-		trigramIndex:   NewTrigramIndex(),
-		embeddingsMgr:  embeddingsMgr,
-		weights:        weights,
+		bm25Index:         NewBM25Index(DefaultBM25Params()),
+		trigramIndex:      NewTrigramIndex(),
+		embeddingsMgr:     embeddingsMgr,
+		weights:           weights,
 		useSemanticSearch: embeddingsMgr != nil,
 	}
 }
@@ -187,7 +188,7 @@ func normalizeSemanticScores(results []embeddings.SearchResult) map[string]float
 	normalized := make(map[string]float32)
 	for _, result := range results {
 		// Use FilePath as ID for semantic results
-		docID := fmt.Sprintf("%s:%d-%d", result.FilePath, result.StartLine, result.EndLine)
+		docID := citation.Format(result.FilePath, result.StartLine, result.EndLine)
 		normalized[docID] = result.Score / maxScore
 	}
 
@@ -207,7 +208,7 @@ func getDocumentByID(results []ScoredDocument, docID string) Document {
 // getDocumentFromSemanticResult converts a semantic result to a Document
 func getDocumentFromSemanticResult(results []embeddings.SearchResult, docID string) Document {
 	for _, result := range results {
-		resultID := fmt.Sprintf("%s:%d-%d", result.FilePath, result.StartLine, result.EndLine)
+		resultID := citation.Format(result.FilePath, result.StartLine, result.EndLine)
 		if resultID == docID {
 			return Document{
 				ID:       docID,
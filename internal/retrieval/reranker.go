@@ -1,7 +1,9 @@
 package retrieval
 
 import (
+	"math"
 	"strings"
+	"time"
 )
 
 // Reranker reorders retrieved results based on heuristics
@@ -61,6 +63,11 @@ func (r *Reranker) extractFeatures(result FusedResult, queryLower string, queryT
 	// File relevance based on path
 	features.FileRelevance = r.calculateFileRelevance(result.Document.FilePath)
 
+	// Recency, from the "blame_date" metadata the chunker adds when
+	// embeddings.annotate_blame is on. 0 (no boost) when it's off or the
+	// file has no blame data (e.g. an untracked file).
+	features.Recency = r.calculateRecency(result.Document.Metadata)
+
 	// Symbol importance
 	features.SymbolImportance = r.calculateSymbolImportance(result.Document.Content, result.Document.Metadata)
 
@@ -81,10 +88,11 @@ func (r *Reranker) calculateBoost(features RerankingFeatures) float32 {
 		boost += 0.3 // 30% boost for exact match
 	}
 
-	boost += features.FileRelevance * 0.2        // Up to 20% from file relevance
-	boost += features.SymbolImportance * 0.25    // Up to 25% from symbol importance
-	boost += features.QueryTermDensity * 0.15    // Up to 15% from term density
-	boost += features.ChunkPosition * 0.1        // Up to 10% from position
+	boost += features.FileRelevance * 0.2     // Up to 20% from file relevance
+	boost += features.SymbolImportance * 0.25 // Up to 25% from symbol importance
+	boost += features.QueryTermDensity * 0.15 // Up to 15% from term density
+	boost += features.ChunkPosition * 0.1     // Up to 10% from position
+	boost += features.Recency * 0.1           // Up to 10% for actively maintained code
 
 	return boost
 }
@@ -100,8 +108,8 @@ func (r *Reranker) calculateFileRelevance(filePath string) float32 {
 
 	// Boost for main package files (not test/vendor)
 	if !strings.Contains(filePath, "_test") &&
-	   !strings.Contains(filePath, "/vendor/") &&
-	   !strings.Contains(filePath, "/node_modules/") {
+		!strings.Contains(filePath, "/vendor/") &&
+		!strings.Contains(filePath, "/node_modules/") {
 		score += 0.2
 	}
 
@@ -116,6 +124,13 @@ func (r *Reranker) calculateFileRelevance(filePath string) float32 {
 		score += 0.1
 	}
 
+	// Heavily penalize lockfiles and generated code - they're mechanically
+	// produced, high-term-frequency, and almost never what a query is
+	// actually after
+	if isLockfileOrGeneratedPath(filePath) {
+		score -= 0.4
+	}
+
 	// Clamp to [0, 1]
 	if score < 0 {
 		score = 0
@@ -127,6 +142,38 @@ func (r *Reranker) calculateFileRelevance(filePath string) float32 {
 	return score
 }
 
+// recencyHalfLife is the age at which calculateRecency's boost has decayed
+// to half its maximum, so a chunk touched yesterday scores much higher than
+// one untouched in years without a hard cutoff.
+const recencyHalfLife = 90 * 24 * time.Hour
+
+// calculateRecency scores a chunk by how recently it was last touched,
+// according to the "blame_date" metadata the chunker adds when
+// embeddings.annotate_blame is enabled. Returns 0 if that metadata is
+// missing (blame off, or the file has no git history).
+func (r *Reranker) calculateRecency(metadata map[string]string) float32 {
+	if metadata == nil {
+		return 0
+	}
+	raw := metadata["blame_date"]
+	if raw == "" {
+		return 0
+	}
+	blameDate, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return 0
+	}
+
+	age := time.Since(blameDate)
+	if age < 0 {
+		age = 0
+	}
+
+	// Exponential decay: score halves every recencyHalfLife.
+	halfLives := float64(age) / float64(recencyHalfLife)
+	return float32(math.Pow(0.5, halfLives))
+}
+
 // calculateSymbolImportance scores based on important code symbols
 func (r *Reranker) calculateSymbolImportance(content string, metadata map[string]string) float32 {
 	score := float32(0.3) // Base score
@@ -212,6 +259,44 @@ func (r *Reranker) calculateChunkPosition(metadata map[string]string) float32 {
 	return 0.5
 }
 
+// lockfileBasenames mirrors embeddings.isLockfileOrGenerated's lockfile
+// list - kept as a small local copy rather than an import since the two
+// packages skip these files for different reasons (one refuses to index
+// them at all, this one just down-weights results that slipped through).
+var lockfileBasenames = map[string]bool{
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"go.sum":            true,
+	"cargo.lock":        true,
+	"composer.lock":     true,
+	"gemfile.lock":      true,
+	"poetry.lock":       true,
+}
+
+var generatedFileSuffixes = []string{
+	"_pb.go", ".pb.go", ".pb.gw.go", "_pb2.py",
+	".min.js", ".min.css", ".bundle.js",
+}
+
+// isLockfileOrGeneratedPath reports whether filePath looks like a
+// dependency lockfile or generated/build artifact.
+func isLockfileOrGeneratedPath(filePath string) bool {
+	base := strings.ToLower(filePath)
+	if idx := strings.LastIndexAny(base, "/\\"); idx != -1 {
+		base = base[idx+1:]
+	}
+	if lockfileBasenames[base] {
+		return true
+	}
+	for _, suffix := range generatedFileSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return strings.Contains(filePath, "/dist/") || strings.Contains(filePath, "/build/")
+}
+
 // OrderContext orders retrieved chunks optimally for prompting
 // Critical chunks at top and bottom to avoid "lost in the middle" effect
 func OrderContext(chunks []string, maxChunks int) []string {
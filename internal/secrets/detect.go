@@ -0,0 +1,90 @@
+// Package secrets provides lightweight, pattern-based detection of
+// credential-shaped content, so the embeddings indexer and long-term memory
+// store can refuse to persist secrets instead of embedding or storing them
+// verbatim. It's not a substitute for a real secret scanner - just a
+// last-line-of-defense guard over what this process itself writes to disk.
+package secrets
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// secretFilenames are exact (case-insensitive) basenames that are almost
+// always credential material regardless of content.
+var secretFilenames = map[string]bool{
+	".env":                 true,
+	".env.local":           true,
+	".env.development":     true,
+	".env.production":      true,
+	".env.test":            true,
+	".netrc":               true,
+	"id_rsa":               true,
+	"id_dsa":               true,
+	"id_ecdsa":             true,
+	"id_ed25519":           true,
+	"credentials":          true,
+	"credentials.json":     true,
+	"service-account.json": true,
+}
+
+// secretFileSuffixes are extensions that are almost always credential
+// material (private keys, certs with embedded keys, keystores).
+var secretFileSuffixes = []string{
+	".pem", ".key", ".pfx", ".p12", ".ppk", ".jks",
+}
+
+// LooksLikeSecretFile reports whether path's name alone (not its content)
+// marks it as a file that shouldn't be indexed.
+func LooksLikeSecretFile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	if secretFilenames[base] {
+		return true
+	}
+	for _, suffix := range secretFileSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// contentPatterns match credential-shaped text regardless of filename -
+// private key blocks, common cloud/vendor token formats, and a generic
+// "key/secret/token/password = <long value>" assignment. False positives
+// (e.g. a placeholder like API_KEY=your-key-here) are an acceptable cost
+// for not embedding a real one.
+var contentPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN (RSA |EC |DSA |OPENSSH )?PRIVATE KEY-----`),
+	regexp.MustCompile(`AKIA[0-9A-Z]{16}`),                                              // AWS access key ID
+	regexp.MustCompile(`ghp_[A-Za-z0-9]{36}`),                                           // GitHub personal access token
+	regexp.MustCompile(`gh[oprsu]_[A-Za-z0-9]{36}`),                                     // Other GitHub token prefixes
+	regexp.MustCompile(`sk-[A-Za-z0-9]{20,}`),                                           // OpenAI-style API key
+	regexp.MustCompile(`xox[baprs]-[A-Za-z0-9-]{10,}`),                                  // Slack token
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), // JWT
+	regexp.MustCompile(`(?i)(api[_-]?key|secret|token|passwd|password)\s*[:=]\s*['"][A-Za-z0-9\-_./+=]{12,}['"]`),
+}
+
+// ContainsSecret reports whether content matches one of contentPatterns.
+func ContainsSecret(content string) bool {
+	for _, pattern := range contentPatterns {
+		if pattern.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldSkip combines the filename and content checks for a file about to
+// be indexed, returning whether it should be skipped and, if so, a short
+// human-readable reason.
+func ShouldSkip(path, content string) (bool, string) {
+	if LooksLikeSecretFile(path) {
+		return true, "filename matches a common secret/credential file pattern"
+	}
+	if ContainsSecret(content) {
+		return true, "content matches a secret-detection pattern"
+	}
+	return false, ""
+}
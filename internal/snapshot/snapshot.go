@@ -0,0 +1,278 @@
+// Package snapshot records a lightweight hash manifest of a workspace at a
+// point in time and diffs it against the current tree - independent of
+// git, so a session working outside a git repo (or across working-tree
+// changes the user hasn't staged) still gets a trustworthy "what changed"
+// view before committing agent work.
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// skipDirs mirrors the project analyzer's/graph indexer's skip list so a
+// snapshot doesn't walk into vendor/build output or gocode's own state
+// directory.
+var skipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, ".gocode": true,
+	"__pycache__": true, ".venv": true, "venv": true, "dist": true,
+	"build": true, "target": true, ".next": true, ".nuxt": true,
+}
+
+// maxCapturedFileSize skips content capture (but still records the hash)
+// for files larger than this, so one huge binary asset doesn't blow up
+// session memory. Diffs against such files fall back to a hash-only notice.
+const maxCapturedFileSize = 2 * 1024 * 1024 // 2MB
+
+type file struct {
+	Hash    string
+	Content []byte // nil if the file was too large to capture
+}
+
+// Snapshot is a workspace's file manifest at one point in time.
+type Snapshot struct {
+	rootDir string
+	TakenAt time.Time
+	files   map[string]file // path relative to rootDir, slash-separated
+}
+
+// Take walks rootDir and records a hash (and, for reasonably sized files,
+// the content) of every file, skipping VCS/dependency/build directories.
+func Take(rootDir string) (*Snapshot, error) {
+	snap := &Snapshot{rootDir: rootDir, TakenAt: time.Now(), files: make(map[string]file)}
+
+	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // best-effort; skip unreadable entries rather than aborting the whole snapshot
+		}
+		if d.IsDir() {
+			if skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		f := file{Hash: hashBytes(data)}
+		if info.Size() <= maxCapturedFileSize {
+			f.Content = data
+		}
+		snap.files[filepath.ToSlash(rel)] = f
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot workspace: %w", err)
+	}
+	return snap, nil
+}
+
+// Changes summarizes what differs between the snapshot and the current
+// workspace.
+type Changes struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+}
+
+// Empty reports whether nothing has changed since the snapshot was taken.
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Modified) == 0 && len(c.Deleted) == 0
+}
+
+// Diff walks the current workspace and compares it against the snapshot.
+func (s *Snapshot) Diff() (*Changes, error) {
+	current := make(map[string]bool, len(s.files))
+	changes := &Changes{}
+
+	err := filepath.WalkDir(s.rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.rootDir, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		current[rel] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		hash := hashBytes(data)
+
+		prior, existed := s.files[rel]
+		if !existed {
+			changes.Added = append(changes.Added, rel)
+		} else if prior.Hash != hash {
+			changes.Modified = append(changes.Modified, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff workspace: %w", err)
+	}
+
+	for rel := range s.files {
+		if !current[rel] {
+			changes.Deleted = append(changes.Deleted, rel)
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Modified)
+	sort.Strings(changes.Deleted)
+	return changes, nil
+}
+
+// FileDiff renders a line-level diff between the snapshotted content of
+// relPath and its current content on disk.
+func (s *Snapshot) FileDiff(relPath string) (string, error) {
+	relPath = filepath.ToSlash(relPath)
+	prior, existed := s.files[relPath]
+
+	currentData, readErr := os.ReadFile(filepath.Join(s.rootDir, filepath.FromSlash(relPath)))
+
+	switch {
+	case !existed && readErr != nil:
+		return "", fmt.Errorf("%s is in neither the snapshot nor the current workspace", relPath)
+	case !existed:
+		return fmt.Sprintf("%s is new since the snapshot (%d bytes)\n\n%s", relPath, len(currentData), unifiedDiff(nil, currentData)), nil
+	case readErr != nil:
+		return fmt.Sprintf("%s was deleted since the snapshot", relPath), nil
+	case prior.Content == nil:
+		if prior.Hash == hashBytes(currentData) {
+			return fmt.Sprintf("%s is unchanged since the snapshot (hash-only - file exceeds the %d byte capture limit)", relPath, maxCapturedFileSize), nil
+		}
+		return "", fmt.Errorf("%s changed since the snapshot but exceeds the %d byte capture limit, so no line diff is available (hash was %s, now %s)", relPath, maxCapturedFileSize, prior.Hash, hashBytes(currentData))
+	}
+
+	if prior.Hash == hashBytes(currentData) {
+		return fmt.Sprintf("%s is unchanged since the snapshot", relPath), nil
+	}
+	return unifiedDiff(prior.Content, currentData), nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// maxDiffCells bounds the O(n*m) LCS table below - past this many
+// line-pairs, fall back to a plain old/new dump rather than eating gigabytes
+// of memory on huge generated files.
+const maxDiffCells = 4_000_000
+
+// UnifiedDiff is the exported form of unifiedDiff, for callers outside this
+// package that want the same line-diff format without a Snapshot (e.g. the
+// confirmation system's write/edit change preview).
+func UnifiedDiff(oldData, newData []byte) string {
+	return unifiedDiff(oldData, newData)
+}
+
+// unifiedDiff renders a minimal unified-style line diff between two byte
+// slices, using a longest-common-subsequence alignment. It's meant for
+// reviewing an agent's edits at gocode's usual file sizes, not as a
+// replacement for `git diff` on huge files.
+func unifiedDiff(oldData, newData []byte) string {
+	oldLines := splitLines(oldData)
+	newLines := splitLines(newData)
+
+	if len(oldLines)*len(newLines) > maxDiffCells {
+		return fmt.Sprintf("--- old (%d lines) ---\n%s\n\n+++ new (%d lines) +++\n%s", len(oldLines), string(oldData), len(newLines), string(newData))
+	}
+
+	lcs := commonSubsequence(oldLines, newLines)
+
+	var b strings.Builder
+	oi, ni, li := 0, 0, 0
+	for oi < len(oldLines) || ni < len(newLines) {
+		if oi < len(oldLines) && ni < len(newLines) && li < len(lcs) && oldLines[oi] == lcs[li] && newLines[ni] == lcs[li] {
+			b.WriteString("  " + oldLines[oi] + "\n")
+			oi++
+			ni++
+			li++
+			continue
+		}
+		if oi < len(oldLines) && (li >= len(lcs) || oldLines[oi] != lcs[li]) {
+			b.WriteString("- " + oldLines[oi] + "\n")
+			oi++
+			continue
+		}
+		if ni < len(newLines) {
+			b.WriteString("+ " + newLines[ni] + "\n")
+			ni++
+		}
+	}
+	return b.String()
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+// commonSubsequence computes the longest common subsequence of two line
+// slices via the standard O(n*m) dynamic-programming table.
+func commonSubsequence(a, b []string) []string {
+	n, m := len(a), len(b)
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else if dp[i+1][j] >= dp[i][j+1] {
+				dp[i][j] = dp[i+1][j]
+			} else {
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+
+	var lcs []string
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			lcs = append(lcs, a[i])
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return lcs
+}
@@ -289,6 +289,137 @@ type TraceInfo struct {
 	SpanCount int
 }
 
+// ToolStat summarizes call volume and failure rate for one tool, aggregated
+// across every "tool.*" span recorded in the database.
+type ToolStat struct {
+	Name     string
+	Calls    int
+	Failures int
+}
+
+// ToolStats aggregates the "tool.*" spans written by tools.Registry.Execute
+// into per-tool call/failure counts. The tool name is read from the
+// "tool.name" attribute rather than parsed out of the span name, since that
+// attribute is what Execute actually sets.
+func (e *SQLiteExporter) ToolStats() ([]ToolStat, error) {
+	rows, err := e.db.Query(`
+		SELECT attributes, status_code
+		FROM spans
+		WHERE name LIKE 'tool.%'
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*ToolStat)
+	var order []string
+	for rows.Next() {
+		var attrsJSON, statusCode string
+		if err := rows.Scan(&attrsJSON, &statusCode); err != nil {
+			return nil, err
+		}
+
+		var attrs map[string]interface{}
+		if err := json.Unmarshal([]byte(attrsJSON), &attrs); err != nil {
+			continue
+		}
+		name, _ := attrs["tool.name"].(string)
+		if name == "" {
+			continue
+		}
+
+		stat, ok := byName[name]
+		if !ok {
+			stat = &ToolStat{Name: name}
+			byName[name] = stat
+			order = append(order, name)
+		}
+		stat.Calls++
+		if statusCode == "Error" {
+			stat.Failures++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stats := make([]ToolStat, 0, len(order))
+	for _, name := range order {
+		stats = append(stats, *byName[name])
+	}
+	return stats, nil
+}
+
+// TokenUsagePoint is the total prompt/completion token usage recorded by
+// "llm.completion" spans that started on a given day (YYYY-MM-DD, local time).
+type TokenUsagePoint struct {
+	Day              string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// TokenUsageByDay aggregates the gen_ai.usage.* attributes on "llm.completion"
+// spans by calendar day. Attributes are decoded in Go rather than via SQLite
+// JSON functions, since the JSON1 extension isn't guaranteed to be compiled
+// into the mattn/go-sqlite3 build this binary links.
+func (e *SQLiteExporter) TokenUsageByDay() ([]TokenUsagePoint, error) {
+	rows, err := e.db.Query(`
+		SELECT start_time, attributes
+		FROM spans
+		WHERE name = 'llm.completion'
+		ORDER BY start_time
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byDay := make(map[string]*TokenUsagePoint)
+	var order []string
+	for rows.Next() {
+		var startTime int64
+		var attrsJSON string
+		if err := rows.Scan(&startTime, &attrsJSON); err != nil {
+			return nil, err
+		}
+
+		var attrs map[string]interface{}
+		if err := json.Unmarshal([]byte(attrsJSON), &attrs); err != nil {
+			continue
+		}
+
+		day := time.Unix(0, startTime).Format("2006-01-02")
+		point, ok := byDay[day]
+		if !ok {
+			point = &TokenUsagePoint{Day: day}
+			byDay[day] = point
+			order = append(order, day)
+		}
+		point.PromptTokens += intAttr(attrs, "gen_ai.usage.prompt_tokens")
+		point.CompletionTokens += intAttr(attrs, "gen_ai.usage.completion_tokens")
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	points := make([]TokenUsagePoint, 0, len(order))
+	for _, day := range order {
+		points = append(points, *byDay[day])
+	}
+	return points, nil
+}
+
+// intAttr reads a numeric span attribute that json.Unmarshal decoded as
+// float64, returning 0 if it's absent or not a number.
+func intAttr(attrs map[string]interface{}, key string) int {
+	v, ok := attrs[key].(float64)
+	if !ok {
+		return 0
+	}
+	return int(v)
+}
+
 // FormatSpan formats a span for display
 func FormatSpan(span SpanData) string {
 	startTime := time.Unix(0, span.StartTime)
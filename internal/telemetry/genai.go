@@ -23,6 +23,7 @@ const (
 	AttrGenAIRequestTopP          = "gen_ai.request.top_p"
 	AttrGenAIRequestFrequencyPenalty = "gen_ai.request.frequency_penalty"
 	AttrGenAIRequestPresencePenalty  = "gen_ai.request.presence_penalty"
+	AttrGenAIRequestSeed             = "gen_ai.request.seed"
 
 	// Response attributes
 	AttrGenAIResponseID          = "gen_ai.response.id"
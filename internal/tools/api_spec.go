@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jake/gocode/internal/initialization"
+)
+
+// APISpecTool lets the agent query the project's OpenAPI/Swagger spec
+// (detected during project analysis) without re-reading the raw file.
+type APISpecTool struct {
+	spec *initialization.APISpecInfo
+}
+
+func NewAPISpecTool(spec *initialization.APISpecInfo) *APISpecTool {
+	return &APISpecTool{spec: spec}
+}
+
+func (t *APISpecTool) Name() string {
+	return "api_spec"
+}
+
+func (t *APISpecTool) Description() string {
+	return "Lists the operations declared in the project's OpenAPI/Swagger spec, or filters them by path/method."
+}
+
+func (t *APISpecTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Substring to filter endpoint paths by (e.g. \"/tasks\"). Omit to list all endpoints.",
+			},
+		},
+	}
+}
+
+type APISpecArgs struct {
+	Path string `json:"path,omitempty"`
+}
+
+func (t *APISpecTool) Execute(ctx context.Context, args string) (string, error) {
+	var specArgs APISpecArgs
+	if err := UnmarshalArgs(args, &specArgs); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+
+	if t.spec == nil {
+		return "", NewNotFoundError(t.Name(), "no API spec was detected in this project")
+	}
+
+	var matched []initialization.APIEndpoint
+	for _, ep := range t.spec.Endpoints {
+		if specArgs.Path == "" || strings.Contains(ep.Path, specArgs.Path) {
+			matched = append(matched, ep)
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# %s (%s)\n\n", t.spec.Title, t.spec.Format)
+	if t.spec.Version != "" {
+		fmt.Fprintf(&out, "Version: %s\n\n", t.spec.Version)
+	}
+
+	if len(matched) == 0 {
+		out.WriteString("(no matching endpoints)")
+		return out.String(), nil
+	}
+
+	out.WriteString("| Method | Path | Summary |\n")
+	out.WriteString("| --- | --- | --- |\n")
+	for _, ep := range matched {
+		summary := ep.Summary
+		if summary == "" {
+			summary = "-"
+		}
+		fmt.Fprintf(&out, "| %s | %s | %s |\n", ep.Method, ep.Path, summary)
+	}
+
+	return out.String(), nil
+}
@@ -4,24 +4,52 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/jake/gocode/internal/config"
 )
 
 type BashTool struct {
 	processes map[string]*BackgroundProcess
 	mu        sync.RWMutex
 	nextID    int
+	cwd       string               // tracked across calls via a leading `cd`, since each Execute may otherwise spawn a fresh shell with no memory of a prior one
+	sandbox   config.SandboxConfig // zero value (Enabled: false) runs unsandboxed, matching prior behavior
+}
+
+// SetSandbox enables (or reconfigures) the sandbox commands run inside -
+// working-directory jail, environment filtering, and (Linux only) namespace
+// isolation. Passing the zero value disables it.
+func (t *BashTool) SetSandbox(sandbox config.SandboxConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sandbox = sandbox
 }
 
+// bareCDRegex matches a command that is only a directory change, so it can
+// be applied to the tool's tracked cwd instead of a throwaway subprocess
+// whose own `cd` wouldn't outlive that one process.
+var bareCDRegex = regexp.MustCompile(`(?i)^cd\s+(.+)$`)
+
 type BackgroundProcess struct {
-	ID      string
-	Cmd     *exec.Cmd
-	Stdout  *bytes.Buffer
-	Stderr  *bytes.Buffer
-	Done    chan error
-	lastPos int
+	ID        string
+	Name      string // caller-supplied label (e.g. "dev-server"); equals ID when none was given
+	Command   string
+	StartedAt time.Time
+	Cmd       *exec.Cmd
+	Stdout    *bytes.Buffer
+	Stderr    *bytes.Buffer
+	Done      chan error
+	finished  atomic.Bool // set alongside the Done send, so IsRunning can poll without consuming it
+	lastPos   int
 }
 
 func NewBashTool() *BashTool {
@@ -35,7 +63,57 @@ func (t *BashTool) Name() string {
 }
 
 func (t *BashTool) Description() string {
-	return "Executes bash commands with optional timeout and background execution support. Use this to run tests (python tests.py, npm test, go test), builds, and other shell commands."
+	desc := "Executes bash commands with optional timeout and background execution support. Use this to run tests (python tests.py, npm test, go test), builds, and other shell commands."
+	return fmt.Sprintf("%s Current working directory: %s. A leading `cd <path>` updates it for subsequent calls; relative paths in other commands resolve against it.", desc, t.CurrentDir())
+}
+
+// CurrentDir returns the directory bash commands run in: the last directory
+// a `cd` moved to, or the process's own working directory if none has been
+// issued yet.
+func (t *BashTool) CurrentDir() string {
+	t.mu.RLock()
+	cwd := t.cwd
+	t.mu.RUnlock()
+
+	if cwd != "" {
+		return cwd
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return wd
+	}
+	return "."
+}
+
+// tryChangeDir intercepts a bare "cd <path>" command and updates the tool's
+// tracked working directory directly, rather than running it as its own
+// subprocess whose directory change would be lost the moment it exits.
+func (t *BashTool) tryChangeDir(command string) (handled bool, output string, err error) {
+	m := bareCDRegex.FindStringSubmatch(strings.TrimSpace(command))
+	if m == nil {
+		return false, "", nil
+	}
+
+	target := strings.Trim(strings.TrimSpace(m[1]), `"`)
+	newDir := target
+	if !filepath.IsAbs(newDir) {
+		newDir = filepath.Join(t.CurrentDir(), newDir)
+	}
+
+	info, statErr := os.Stat(newDir)
+	if statErr != nil || !info.IsDir() {
+		return true, "", fmt.Errorf("cd: no such directory: %s", target)
+	}
+
+	t.mu.Lock()
+	sandbox := t.sandbox
+	if err := checkJail(sandbox, newDir); err != nil {
+		t.mu.Unlock()
+		return true, "", err
+	}
+	t.cwd = newDir
+	t.mu.Unlock()
+
+	return true, fmt.Sprintf("Changed directory to %s", newDir), nil
 }
 
 func (t *BashTool) Parameters() map[string]interface{} {
@@ -58,6 +136,10 @@ func (t *BashTool) Parameters() map[string]interface{} {
 				"type":        "boolean",
 				"description": "Run command in background",
 			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Optional label for a background shell (e.g. \"dev-server\", \"test-watch\"), used as its ID instead of an auto-generated one. Only meaningful with run_in_background. Reuse it across turns to poll the same shell with bash_output.",
+			},
 		},
 		"required": []string{"command"},
 	}
@@ -68,6 +150,7 @@ type BashArgs struct {
 	Description     string `json:"description,omitempty"`
 	Timeout         int    `json:"timeout,omitempty"`
 	RunInBackground bool   `json:"run_in_background,omitempty"`
+	Name            string `json:"name,omitempty"`
 }
 
 func (t *BashTool) Execute(ctx context.Context, args string) (string, error) {
@@ -76,6 +159,10 @@ func (t *BashTool) Execute(ctx context.Context, args string) (string, error) {
 		return "", fmt.Errorf("invalid arguments: %w", err)
 	}
 
+	if handled, output, cdErr := t.tryChangeDir(bashArgs.Command); handled {
+		return output, cdErr
+	}
+
 	if bashArgs.RunInBackground {
 		return t.executeBackground(bashArgs)
 	}
@@ -95,8 +182,15 @@ func (t *BashTool) executeForeground(ctx context.Context, args BashArgs) (string
 	execCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
 	defer cancel()
 
-	// Use cmd.exe for Windows
-	cmd := exec.CommandContext(execCtx, "cmd", "/C", args.Command)
+	dir := t.CurrentDir()
+	t.mu.RLock()
+	sandbox := t.sandbox
+	t.mu.RUnlock()
+	if err := checkJail(sandbox, dir); err != nil {
+		return "", NewPermissionDeniedError("bash", err.Error())
+	}
+
+	cmd := buildSandboxedCommand(execCtx, args.Command, dir, sandbox)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -109,14 +203,17 @@ func (t *BashTool) executeForeground(ctx context.Context, args BashArgs) (string
 		output += "\nSTDERR:\n" + stderr.String()
 	}
 
-	// Truncate if over 30000 characters
-	if len(output) > 30000 {
-		output = output[:30000] + "\n... (output truncated)"
+	maxOutput := 30000
+	if sandbox.Enabled && sandbox.MaxOutputBytes > 0 {
+		maxOutput = sandbox.MaxOutputBytes
+	}
+	if len(output) > maxOutput {
+		output = output[:maxOutput] + "\n... (output truncated)"
 	}
 
 	if err != nil {
 		if execCtx.Err() == context.DeadlineExceeded {
-			return output, fmt.Errorf("command timed out after %dms", timeout)
+			return output, NewTimeoutError("bash", fmt.Sprintf("command timed out after %dms", timeout))
 		}
 		return output, fmt.Errorf("command failed: %w", err)
 	}
@@ -125,24 +222,40 @@ func (t *BashTool) executeForeground(ctx context.Context, args BashArgs) (string
 }
 
 func (t *BashTool) executeBackground(args BashArgs) (string, error) {
+	dir := t.CurrentDir()
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	t.nextID++
-	id := fmt.Sprintf("bash_%d", t.nextID)
+	if err := checkJail(t.sandbox, dir); err != nil {
+		return "", NewPermissionDeniedError("bash", err.Error())
+	}
+
+	id := args.Name
+	if id != "" {
+		if _, exists := t.processes[id]; exists {
+			return "", fmt.Errorf("a shell named %q is already running; use a different name or kill it first", id)
+		}
+	} else {
+		t.nextID++
+		id = fmt.Sprintf("bash_%d", t.nextID)
+	}
 
-	cmd := exec.Command("cmd", "/C", args.Command)
+	cmd := buildSandboxedCommand(context.Background(), args.Command, dir, t.sandbox)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	proc := &BackgroundProcess{
-		ID:     id,
-		Cmd:    cmd,
-		Stdout: &stdout,
-		Stderr: &stderr,
-		Done:   make(chan error, 1),
+		ID:        id,
+		Name:      args.Name,
+		Command:   args.Command,
+		StartedAt: time.Now(),
+		Cmd:       cmd,
+		Stdout:    &stdout,
+		Stderr:    &stderr,
+		Done:      make(chan error, 1),
 	}
 
 	t.processes[id] = proc
@@ -154,7 +267,9 @@ func (t *BashTool) executeBackground(args BashArgs) (string, error) {
 
 	// Monitor process completion
 	go func() {
-		proc.Done <- cmd.Wait()
+		err := cmd.Wait()
+		proc.finished.Store(true)
+		proc.Done <- err
 	}()
 
 	return fmt.Sprintf("Background process started with ID: %s\nUse bash_output tool to read output.", id), nil
@@ -167,6 +282,29 @@ func (t *BashTool) GetProcess(id string) (*BackgroundProcess, bool) {
 	return proc, ok
 }
 
+// ListProcesses returns every tracked background shell, sorted by ID, for
+// the /shells REPL command. Sessions persist for the lifetime of the
+// BashTool (and therefore the agent process), so this reflects state across
+// turns, not just the current one.
+func (t *BashTool) ListProcesses() []*BackgroundProcess {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	procs := make([]*BackgroundProcess, 0, len(t.processes))
+	for _, proc := range t.processes {
+		procs = append(procs, proc)
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].ID < procs[j].ID })
+	return procs
+}
+
+// IsRunning reports whether a background process is still executing,
+// without consuming its Done channel the way bash_output's completion
+// check does.
+func (p *BackgroundProcess) IsRunning() bool {
+	return !p.finished.Load()
+}
+
 func (t *BashTool) KillProcess(id string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
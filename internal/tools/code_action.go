@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jake/gocode/internal/lsp"
+)
+
+// CodeActionTool surfaces and applies the language server's code actions
+// (quick fixes, refactors) for a line range, so the agent can resolve a
+// diagnostic the way an IDE would instead of hand-editing around it.
+type CodeActionTool struct {
+	lspMgr   *lsp.Manager
+	registry *Registry
+}
+
+// NewCodeActionTool takes the session's Registry, not just a narrower piece
+// of it, because applyWorkspaceEdit needs the full authorize/record
+// pipeline - path normalization, policy, conflict check, file-change hook -
+// for every file an applied action touches. registry is read at call time,
+// so it sees state (e.g. SetFileChangeHook) configured after this tool is
+// constructed.
+func NewCodeActionTool(lspMgr *lsp.Manager, registry *Registry) *CodeActionTool {
+	return &CodeActionTool{lspMgr: lspMgr, registry: registry}
+}
+
+func (t *CodeActionTool) Name() string {
+	return "code_action"
+}
+
+func (t *CodeActionTool) Description() string {
+	return "Lists the language server's code actions (quick fixes, refactors) available for a line range. Call once without \"apply\" to see the available actions, then again with \"apply\" set to the 1-based index of the one to run."
+}
+
+func (t *CodeActionTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file",
+			},
+			"start_line": map[string]interface{}{
+				"type":        "number",
+				"description": "Start line (0-indexed) of the range to request actions for",
+			},
+			"end_line": map[string]interface{}{
+				"type":        "number",
+				"description": "End line (0-indexed) of the range; defaults to start_line",
+			},
+			"apply": map[string]interface{}{
+				"type":        "number",
+				"description": "1-based index of the action (from a prior call) to apply; omit to just list actions",
+			},
+		},
+		"required": []string{"file_path", "start_line"},
+	}
+}
+
+type CodeActionArgs struct {
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Apply     int    `json:"apply"`
+}
+
+func (t *CodeActionTool) Execute(ctx context.Context, args string) (string, error) {
+	var caArgs CodeActionArgs
+	if err := UnmarshalArgs(args, &caArgs); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	endLine := caArgs.EndLine
+	if endLine == 0 {
+		endLine = caArgs.StartLine
+	}
+
+	actions, err := t.lspMgr.GetCodeActions(ctx, caArgs.FilePath, caArgs.StartLine, 0, endLine, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to get code actions: %w", err)
+	}
+	if len(actions) == 0 {
+		return "No code actions available for this range", nil
+	}
+
+	if caArgs.Apply <= 0 {
+		var b strings.Builder
+		b.WriteString("Available code actions:\n")
+		for i, a := range actions {
+			kind := a.Kind
+			if kind == "" {
+				kind = "action"
+			}
+			fmt.Fprintf(&b, "%d. [%s] %s\n", i+1, kind, a.Title)
+		}
+		b.WriteString("\nCall again with \"apply\" set to one of these numbers to run it.")
+		return b.String(), nil
+	}
+
+	if caArgs.Apply > len(actions) {
+		return "", fmt.Errorf("apply index %d out of range (%d action(s) available)", caArgs.Apply, len(actions))
+	}
+
+	action := actions[caArgs.Apply-1]
+	if action.Edit == nil {
+		return "", fmt.Errorf("action %q has no edit GoCode can apply (server-side command actions aren't supported)", action.Title)
+	}
+
+	changed, err := applyWorkspaceEdit(t.registry, t.Name(), action.Edit)
+	if err != nil {
+		return "", fmt.Errorf("failed to apply code action: %w", err)
+	}
+
+	return fmt.Sprintf("Applied %q across %d file(s):\n- %s", action.Title, len(changed), strings.Join(changed, "\n- ")), nil
+}
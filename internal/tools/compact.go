@@ -0,0 +1,73 @@
+package tools
+
+import "strings"
+
+// CompactContextThreshold is the context window size below which tool
+// schemas sent to the LLM are shortened, so a 15-tool loadout doesn't eat a
+// disproportionate share of a small model's context. See Compact.
+const CompactContextThreshold = 16_000
+
+// maxCompactEnumValues caps how many enum options survive compaction; the
+// rest are dropped rather than truncated with an ellipsis marker, since an
+// enum's whole point is exact valid values and a truncated list would look
+// exhaustive when it isn't.
+const maxCompactEnumValues = 4
+
+// Compact shortens a tool's description (first sentence only) and prunes its
+// parameter schema (drops per-property descriptions, caps enum lists) for
+// use when the configured context window is small. It returns new values
+// and never mutates params, since the original schema is still needed for
+// contexts where the window is large enough to afford it.
+func Compact(description string, params map[string]interface{}) (string, map[string]interface{}) {
+	return compactDescription(description), compactSchema(params)
+}
+
+// compactDescription keeps only the first sentence of a (possibly
+// multi-sentence) tool description.
+func compactDescription(description string) string {
+	if idx := strings.Index(description, ". "); idx != -1 {
+		return description[:idx+1]
+	}
+	return description
+}
+
+// compactSchema returns a copy of a JSON-schema-shaped params map with each
+// property's "description" removed and any "enum" list capped at
+// maxCompactEnumValues.
+func compactSchema(params map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		out[k] = v
+	}
+
+	props, ok := out["properties"].(map[string]interface{})
+	if !ok {
+		return out
+	}
+
+	compactProps := make(map[string]interface{}, len(props))
+	for name, raw := range props {
+		prop, ok := raw.(map[string]interface{})
+		if !ok {
+			compactProps[name] = raw
+			continue
+		}
+
+		compactProp := make(map[string]interface{}, len(prop))
+		for k, v := range prop {
+			compactProp[k] = v
+		}
+		delete(compactProp, "description")
+
+		if enum, ok := compactProp["enum"].([]string); ok && len(enum) > maxCompactEnumValues {
+			compactProp["enum"] = enum[:maxCompactEnumValues]
+		} else if enum, ok := compactProp["enum"].([]interface{}); ok && len(enum) > maxCompactEnumValues {
+			compactProp["enum"] = enum[:maxCompactEnumValues]
+		}
+
+		compactProps[name] = compactProp
+	}
+	out["properties"] = compactProps
+
+	return out
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jake/gocode/internal/config"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DBQueryTool lets the agent inspect a project's database (schema and
+// read-only queries) without shelling out to a CLI client. Only the
+// "sqlite3" driver is wired up, matching the driver already vendored for
+// checkpoints; postgres/mysql DSNs are rejected until those drivers are
+// added to go.mod.
+type DBQueryTool struct {
+	cfg *config.DBConfig
+}
+
+func NewDBQueryTool(cfg *config.DBConfig) *DBQueryTool {
+	return &DBQueryTool{cfg: cfg}
+}
+
+func (t *DBQueryTool) Name() string {
+	return "db_query"
+}
+
+func (t *DBQueryTool) Description() string {
+	return "Lists tables/schema or runs a read-only SQL query against the project's configured database, returning results as a markdown table."
+}
+
+func (t *DBQueryTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "SQL to execute. Omit to list tables. Non-SELECT statements are rejected unless read_only is disabled in config.",
+			},
+		},
+	}
+}
+
+type DBQueryArgs struct {
+	Query string `json:"query,omitempty"`
+}
+
+func (t *DBQueryTool) Execute(ctx context.Context, args string) (string, error) {
+	var queryArgs DBQueryArgs
+	if err := UnmarshalArgs(args, &queryArgs); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+
+	if t.cfg == nil || t.cfg.DSN == "" {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("no database configured (set tools.database.dsn in config.yaml)"))
+	}
+
+	driver := t.cfg.Driver
+	if driver == "" {
+		driver = "sqlite3"
+	}
+	if driver != "sqlite3" {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("driver %q is not yet supported (only sqlite3 is wired up)", driver))
+	}
+
+	db, err := sql.Open(driver, t.cfg.DSN)
+	if err != nil {
+		return "", fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	query := queryArgs.Query
+	if query == "" {
+		query = "SELECT name FROM sqlite_master WHERE type='table' ORDER BY name"
+	} else {
+		query = strings.TrimSpace(query)
+		if err := rejectMultipleStatements(query); err != nil {
+			return "", NewPermissionDeniedError(t.Name(), err.Error())
+		}
+		if t.readOnly() && !strings.HasPrefix(strings.ToUpper(query), "SELECT") {
+			return "", NewPermissionDeniedError(t.Name(), "only SELECT statements are allowed (tools.database.read_only is true)")
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	return renderMarkdownTable(rows)
+}
+
+func (t *DBQueryTool) readOnly() bool {
+	return t.cfg == nil || t.cfg.ReadOnly
+}
+
+// rejectMultipleStatements guards against database/sql's sqlite3 driver,
+// which loops over semicolon-separated statements and executes every one
+// of them (see mattn/go-sqlite3's Conn.query) - without this, a query like
+// "SELECT 1; DELETE FROM users;" sails past the read_only prefix check on
+// its first statement and still runs the second. Only a single trailing
+// semicolon is tolerated; any ';' before the end of the (trimmed) query
+// means more than one statement was submitted.
+func rejectMultipleStatements(query string) error {
+	trimmed := strings.TrimRight(query, "; \t\n\r")
+	if strings.Contains(trimmed, ";") {
+		return fmt.Errorf("only a single SQL statement is allowed per query")
+	}
+	return nil
+}
+
+// renderMarkdownTable converts arbitrary query results into a markdown
+// table, since that's what the model renders and reasons about best.
+func renderMarkdownTable(rows *sql.Rows) (string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	var out strings.Builder
+	out.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	out.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	rowCount := 0
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return "", fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		cells := make([]string, len(columns))
+		for i, v := range values {
+			cells[i] = fmt.Sprintf("%v", v)
+		}
+		out.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		rowCount++
+	}
+
+	if rowCount == 0 {
+		return strings.TrimSpace(out.String()) + "\n\n(no rows)", nil
+	}
+
+	return out.String(), rows.Err()
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DeleteFileTool moves files into a trash directory instead of unlinking
+// them, so an agent-issued delete can be undone rather than relying on the
+// model to run `rm` via bash.
+type DeleteFileTool struct {
+	trashDir string
+}
+
+func NewDeleteFileTool(trashDir string) *DeleteFileTool {
+	return &DeleteFileTool{trashDir: trashDir}
+}
+
+func (t *DeleteFileTool) Name() string {
+	return "delete_file"
+}
+
+func (t *DeleteFileTool) Description() string {
+	return "Moves a file to the trash instead of permanently deleting it. Deleted files can be restored with /undo until purged."
+}
+
+func (t *DeleteFileTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "The absolute path to the file to delete",
+			},
+		},
+		"required": []string{"file_path"},
+	}
+}
+
+type DeleteFileArgs struct {
+	FilePath string `json:"file_path"`
+}
+
+// TrashEntry records where a deleted file went, so /undo (and a future
+// purge policy) can find it again.
+type TrashEntry struct {
+	OriginalPath string    `json:"original_path"`
+	TrashPath    string    `json:"trash_path"`
+	DeletedAt    time.Time `json:"deleted_at"`
+}
+
+func (t *DeleteFileTool) Execute(ctx context.Context, args string) (string, error) {
+	var deleteArgs DeleteFileArgs
+	if err := UnmarshalArgs(args, &deleteArgs); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+
+	if _, err := os.Stat(deleteArgs.FilePath); err != nil {
+		if os.IsNotExist(err) {
+			return "", NewNotFoundError(t.Name(), fmt.Sprintf("file not found: %s", deleteArgs.FilePath))
+		}
+		return "", fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if err := os.MkdirAll(t.trashDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	deletedAt := time.Now()
+	trashName := fmt.Sprintf("%d_%s", deletedAt.UnixNano(), filepath.Base(deleteArgs.FilePath))
+	trashPath := filepath.Join(t.trashDir, trashName)
+
+	if err := os.Rename(deleteArgs.FilePath, trashPath); err != nil {
+		return "", fmt.Errorf("failed to move file to trash: %w", err)
+	}
+
+	entry := TrashEntry{
+		OriginalPath: deleteArgs.FilePath,
+		TrashPath:    trashPath,
+		DeletedAt:    deletedAt,
+	}
+	if err := t.appendManifest(entry); err != nil {
+		return "", fmt.Errorf("failed to record trash manifest: %w", err)
+	}
+
+	return fmt.Sprintf("Moved %s to trash. Use /undo to restore it.", deleteArgs.FilePath), nil
+}
+
+func (t *DeleteFileTool) manifestPath() string {
+	return filepath.Join(t.trashDir, "manifest.jsonl")
+}
+
+// appendManifest records the entry in an append-only JSONL file, matching
+// the repo's preference for simple, dependency-free persistence for small
+// side-tables like this.
+func (t *DeleteFileTool) appendManifest(entry TrashEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(t.manifestPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ListTrash returns all recorded trash entries, most recent first, for
+// /undo to present a restore list from.
+func (t *DeleteFileTool) ListTrash() ([]TrashEntry, error) {
+	data, err := os.ReadFile(t.manifestPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []TrashEntry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var entry TrashEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DepsAuditTool reports outdated dependencies for whichever ecosystem
+// manifest(s) are present, using each ecosystem's own CLI rather than
+// vendoring a registry client for each one.
+type DepsAuditTool struct {
+	workingDir string
+}
+
+func NewDepsAuditTool(workingDir string) *DepsAuditTool {
+	return &DepsAuditTool{workingDir: workingDir}
+}
+
+func (t *DepsAuditTool) Name() string {
+	return "deps_audit"
+}
+
+func (t *DepsAuditTool) Description() string {
+	return "Reports outdated dependencies for the project's detected ecosystem(s) using `go list -m -u`, `npm outdated`, or `pip list --outdated`, normalized into one table per ecosystem. Use this before an \"update my dependencies\" task instead of guessing versions."
+}
+
+func (t *DepsAuditTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+// OutdatedDependency is one normalized row of the audit report, regardless
+// of which ecosystem it came from.
+type OutdatedDependency struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Current   string `json:"current"`
+	Latest    string `json:"latest"`
+}
+
+func (t *DepsAuditTool) Execute(ctx context.Context, args string) (string, error) {
+	var sections []string
+	ranAny := false
+
+	if _, err := os.Stat(filepath.Join(t.workingDir, "go.mod")); err == nil {
+		ranAny = true
+		deps, auditErr := t.auditGo(ctx)
+		sections = append(sections, t.renderSection("Go", deps, auditErr))
+	}
+	if _, err := os.Stat(filepath.Join(t.workingDir, "package.json")); err == nil {
+		ranAny = true
+		deps, auditErr := t.auditNPM(ctx)
+		sections = append(sections, t.renderSection("npm", deps, auditErr))
+	}
+	if _, err := os.Stat(filepath.Join(t.workingDir, "requirements.txt")); err == nil {
+		ranAny = true
+		deps, auditErr := t.auditPip(ctx)
+		sections = append(sections, t.renderSection("pip", deps, auditErr))
+	} else if _, err := os.Stat(filepath.Join(t.workingDir, "pyproject.toml")); err == nil {
+		ranAny = true
+		deps, auditErr := t.auditPip(ctx)
+		sections = append(sections, t.renderSection("pip", deps, auditErr))
+	}
+
+	if !ranAny {
+		return "No go.mod, package.json, requirements.txt, or pyproject.toml found - nothing to audit", nil
+	}
+
+	return strings.Join(sections, "\n\n"), nil
+}
+
+func (t *DepsAuditTool) renderSection(ecosystem string, deps []OutdatedDependency, err error) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", ecosystem)
+	if err != nil {
+		fmt.Fprintf(&b, "(audit failed: %v)\n", err)
+		return b.String()
+	}
+	if len(deps) == 0 {
+		b.WriteString("All dependencies up to date.\n")
+		return b.String()
+	}
+	b.WriteString("| Package | Current | Latest |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for _, d := range deps {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", d.Name, d.Current, d.Latest)
+	}
+	return b.String()
+}
+
+// auditGo shells out to `go list -m -u -json all`, which prints one JSON
+// object per module (not a JSON array), and keeps only modules that
+// declare an available Update.
+func (t *DepsAuditTool) auditGo(ctx context.Context) ([]OutdatedDependency, error) {
+	cmd := exec.CommandContext(ctx, "go", "list", "-m", "-u", "-json", "all")
+	cmd.Dir = t.workingDir
+	output, err := cmd.Output()
+	if err != nil && len(output) == 0 {
+		return nil, fmt.Errorf("go list failed: %w", err)
+	}
+
+	type goModule struct {
+		Path    string `json:"Path"`
+		Version string `json:"Version"`
+		Main    bool   `json:"Main"`
+		Update  *struct {
+			Version string `json:"Version"`
+		} `json:"Update"`
+	}
+
+	var deps []OutdatedDependency
+	decoder := json.NewDecoder(strings.NewReader(string(output)))
+	for decoder.More() {
+		var mod goModule
+		if err := decoder.Decode(&mod); err != nil {
+			break
+		}
+		if mod.Main || mod.Update == nil {
+			continue
+		}
+		deps = append(deps, OutdatedDependency{
+			Ecosystem: "go",
+			Name:      mod.Path,
+			Current:   mod.Version,
+			Latest:    mod.Update.Version,
+		})
+	}
+	return deps, nil
+}
+
+// auditNPM shells out to `npm outdated --json`, which (unhelpfully) exits
+// non-zero whenever it finds anything outdated, so a non-zero exit is only
+// a real failure if stdout didn't parse as JSON.
+func (t *DepsAuditTool) auditNPM(ctx context.Context) ([]OutdatedDependency, error) {
+	cmd := exec.CommandContext(ctx, "npm", "outdated", "--json")
+	cmd.Dir = t.workingDir
+	output, _ := cmd.Output()
+
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return nil, nil
+	}
+
+	var raw map[string]struct {
+		Current string `json:"current"`
+		Latest  string `json:"latest"`
+	}
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("npm outdated returned unparseable output: %w", err)
+	}
+
+	var deps []OutdatedDependency
+	for name, info := range raw {
+		deps = append(deps, OutdatedDependency{
+			Ecosystem: "npm",
+			Name:      name,
+			Current:   info.Current,
+			Latest:    info.Latest,
+		})
+	}
+	return deps, nil
+}
+
+// auditPip shells out to `pip list --outdated --format=json`.
+func (t *DepsAuditTool) auditPip(ctx context.Context) ([]OutdatedDependency, error) {
+	cmd := exec.CommandContext(ctx, "pip", "list", "--outdated", "--format=json")
+	cmd.Dir = t.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("pip list failed: %w", err)
+	}
+
+	var raw []struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		LatestVersion string `json:"latest_version"`
+	}
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("pip list returned unparseable output: %w", err)
+	}
+
+	deps := make([]OutdatedDependency, 0, len(raw))
+	for _, p := range raw {
+		deps = append(deps, OutdatedDependency{
+			Ecosystem: "pip",
+			Name:      p.Name,
+			Current:   p.Version,
+			Latest:    p.LatestVersion,
+		})
+	}
+	return deps, nil
+}
@@ -0,0 +1,247 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EditStructuredTool applies a single path-addressed mutation to a JSON or
+// YAML file, so config edits don't depend on exact-string matching that
+// breaks on formatting differences.
+type EditStructuredTool struct{}
+
+func (t *EditStructuredTool) Name() string {
+	return "edit_structured"
+}
+
+func (t *EditStructuredTool) Description() string {
+	return "Applies a set or append mutation to a JSON or YAML file at a dotted path (e.g. 'server.port' or 'servers[0].name'), preserving the file's format."
+}
+
+func (t *EditStructuredTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "The absolute path to the JSON or YAML file to modify",
+			},
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Dotted path to the field, e.g. 'server.port' or 'servers[0].name'",
+			},
+			"operation": map[string]interface{}{
+				"type":        "string",
+				"description": "\"set\" to assign the value, \"append\" to push the value onto an array at path",
+			},
+			"value": map[string]interface{}{
+				"description": "The value to set or append. May be a string, number, boolean, object, or array.",
+			},
+		},
+		"required": []string{"file_path", "path", "operation", "value"},
+	}
+}
+
+type EditStructuredArgs struct {
+	FilePath  string      `json:"file_path"`
+	Path      string      `json:"path"`
+	Operation string      `json:"operation"`
+	Value     interface{} `json:"value"`
+}
+
+func (t *EditStructuredTool) Execute(ctx context.Context, args string) (string, error) {
+	var editArgs EditStructuredArgs
+	if err := UnmarshalArgs(args, &editArgs); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+
+	isYAML := strings.HasSuffix(editArgs.FilePath, ".yaml") || strings.HasSuffix(editArgs.FilePath, ".yml")
+
+	raw, err := os.ReadFile(editArgs.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", NewNotFoundError(t.Name(), fmt.Sprintf("file not found: %s", editArgs.FilePath))
+		}
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var doc interface{}
+	if isYAML {
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return "", fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	} else {
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return "", fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	}
+
+	segments, err := parsePathSegments(editArgs.Path)
+	if err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+
+	switch editArgs.Operation {
+	case "set":
+		doc, err = setPath(doc, segments, editArgs.Value)
+	case "append":
+		doc, err = appendPath(doc, segments, editArgs.Value)
+	default:
+		err = fmt.Errorf("unsupported operation %q (want \"set\" or \"append\")", editArgs.Operation)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to apply mutation: %w", err)
+	}
+
+	var out []byte
+	if isYAML {
+		out, err = yaml.Marshal(doc)
+	} else {
+		out, err = json.MarshalIndent(doc, "", "  ")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize file: %w", err)
+	}
+
+	if err := os.WriteFile(editArgs.FilePath, out, 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return fmt.Sprintf("Applied %s at %q in %s", editArgs.Operation, editArgs.Path, editArgs.FilePath), nil
+}
+
+// pathSegment is either a map key or an array index.
+type pathSegment struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// parsePathSegments turns "servers[0].name" into [{key:"servers"}, {index:0}, {key:"name"}].
+func parsePathSegments(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path must not be empty")
+	}
+
+	var segments []pathSegment
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			if idx := strings.IndexByte(part, '['); idx >= 0 {
+				if idx > 0 {
+					segments = append(segments, pathSegment{key: part[:idx]})
+				}
+				end := strings.IndexByte(part, ']')
+				if end < idx {
+					return nil, fmt.Errorf("malformed index in path segment %q", part)
+				}
+				n, err := strconv.Atoi(part[idx+1 : end])
+				if err != nil {
+					return nil, fmt.Errorf("invalid array index in path segment %q: %w", part, err)
+				}
+				segments = append(segments, pathSegment{index: n, isIdx: true})
+				part = part[end+1:]
+			} else {
+				segments = append(segments, pathSegment{key: part})
+				part = ""
+			}
+		}
+	}
+	return segments, nil
+}
+
+func setPath(doc interface{}, segments []pathSegment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	seg := segments[0]
+	if seg.isIdx {
+		list, ok := doc.([]interface{})
+		if !ok {
+			list = []interface{}{}
+		}
+		for len(list) <= seg.index {
+			list = append(list, nil)
+		}
+		child, err := setPath(list[seg.index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		list[seg.index] = child
+		return list, nil
+	}
+
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+	}
+	child, err := setPath(m[seg.key], segments[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	m[seg.key] = child
+	return m, nil
+}
+
+func appendPath(doc interface{}, segments []pathSegment, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		list, ok := doc.([]interface{})
+		if !ok {
+			list = []interface{}{}
+		}
+		return append(list, value), nil
+	}
+	return setPath(doc, segments[:len(segments)-1], mustAppend(navigate(doc, segments[:len(segments)-1]), segments[len(segments)-1], value))
+}
+
+// navigate reads (without creating) the value at segments, defaulting to nil.
+func navigate(doc interface{}, segments []pathSegment) interface{} {
+	cur := doc
+	for _, seg := range segments {
+		if seg.isIdx {
+			list, ok := cur.([]interface{})
+			if !ok || seg.index >= len(list) {
+				return nil
+			}
+			cur = list[seg.index]
+		} else {
+			m, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			cur = m[seg.key]
+		}
+	}
+	return cur
+}
+
+// mustAppend resolves the final segment's array (creating it if absent) and
+// returns the value to assign back via setPath at the parent path.
+func mustAppend(parentValue interface{}, lastSeg pathSegment, value interface{}) interface{} {
+	if lastSeg.isIdx {
+		// Appending "into" an explicit array index doesn't make sense; treat
+		// the target itself as the array to append to.
+		list, ok := parentValue.([]interface{})
+		if !ok {
+			list = []interface{}{}
+		}
+		return append(list, value)
+	}
+
+	m, ok := parentValue.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+	}
+	list, ok := m[lastSeg.key].([]interface{})
+	if !ok {
+		list = []interface{}{}
+	}
+	m[lastSeg.key] = append(list, value)
+	return m
+}
@@ -0,0 +1,88 @@
+package tools
+
+import "fmt"
+
+// ErrorClass classifies why a tool call failed, so callers (the agent loop,
+// self-check) can branch on the class of failure instead of pattern
+// matching on error strings like "exit status".
+type ErrorClass string
+
+const (
+	ErrorClassNotFound         ErrorClass = "not_found"
+	ErrorClassPermissionDenied ErrorClass = "permission_denied"
+	ErrorClassTimeout          ErrorClass = "timeout"
+	ErrorClassInvalidArgs      ErrorClass = "invalid_args"
+	ErrorClassConflict         ErrorClass = "conflict"
+	ErrorClassUnknown          ErrorClass = "unknown"
+)
+
+// Error is a structured tool failure. Tools that want their errors to be
+// branchable by class should return one of these (typically via the
+// New*Error constructors) instead of a bare fmt.Errorf.
+type Error struct {
+	Class   ErrorClass `json:"class"`
+	Tool    string     `json:"tool"`
+	Message string     `json:"message"`
+	Cause   error      `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func NewNotFoundError(tool, message string) *Error {
+	return &Error{Class: ErrorClassNotFound, Tool: tool, Message: message}
+}
+
+func NewPermissionDeniedError(tool, message string) *Error {
+	return &Error{Class: ErrorClassPermissionDenied, Tool: tool, Message: message}
+}
+
+func NewTimeoutError(tool, message string) *Error {
+	return &Error{Class: ErrorClassTimeout, Tool: tool, Message: message}
+}
+
+func NewInvalidArgsError(tool string, cause error) *Error {
+	return &Error{Class: ErrorClassInvalidArgs, Tool: tool, Message: "invalid arguments", Cause: cause}
+}
+
+func NewConflictError(tool, message string) *Error {
+	return &Error{Class: ErrorClassConflict, Tool: tool, Message: message}
+}
+
+// ClassOf returns the error class of err if it (or something it wraps) is a
+// *Error, the empty class for a nil err, and ErrorClassUnknown otherwise.
+func ClassOf(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+	var te *Error
+	if asError(err, &te) {
+		return te.Class
+	}
+	return ErrorClassUnknown
+}
+
+// asError is a tiny errors.As shim kept local to avoid importing errors
+// just for this one call site elsewhere in the package.
+func asError(err error, target **Error) bool {
+	for err != nil {
+		if te, ok := err.(*Error); ok {
+			*target = te
+			return true
+		}
+		unwrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return false
+		}
+		err = unwrapper.Unwrap()
+	}
+	return false
+}
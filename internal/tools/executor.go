@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"sync"
+)
+
+// batchWorkers bounds how many read-only tool calls ExecuteBatch runs
+// concurrently, mirroring embeddings.Manager's indexWorkers pattern.
+const batchWorkers = 4
+
+// readOnlyTools lists tools with no side effects on the workspace, safe to
+// run concurrently with each other. Anything not in this set is treated as
+// mutating and serialized.
+var readOnlyTools = map[string]bool{
+	"read":            true,
+	"glob":            true,
+	"grep":            true,
+	"list_directory":  true,
+	"find_definition": true,
+	"find_references": true,
+	"list_symbols":    true,
+	"graph_query":     true,
+	"api_spec":        true,
+	"list_tasks":      true,
+	"deps_audit":      true,
+	"related_tests":   true,
+	"session_state":   true,
+	"git_status":      true,
+	"git_diff":        true,
+	"git_log":         true,
+	"web_search":      true,
+}
+
+// IsReadOnly reports whether name has no side effects on the workspace and
+// is therefore safe for ExecuteBatch to run concurrently.
+func IsReadOnly(name string) bool {
+	return readOnlyTools[name]
+}
+
+// Call is one tool invocation to run through ExecuteBatch.
+type Call struct {
+	Name string
+	Args string
+}
+
+// Result is the outcome of one Call, at the same index in ExecuteBatch's
+// return slice.
+type Result struct {
+	Output string
+	Err    error
+}
+
+// ExecuteBatch runs calls and returns results at matching indices. Calls to
+// read-only tools run concurrently, up to batchWorkers at a time; a call to
+// a mutating tool runs alone, only once every call ahead of it has
+// completed, so file edits/bash/commits never race with each other or with
+// concurrent reads.
+func (r *Registry) ExecuteBatch(ctx context.Context, calls []Call) []Result {
+	results := make([]Result, len(calls))
+
+	for i := 0; i < len(calls); {
+		if !readOnlyTools[calls[i].Name] {
+			output, err := r.Execute(ctx, calls[i].Name, calls[i].Args)
+			results[i] = Result{Output: output, Err: err}
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(calls) && readOnlyTools[calls[j].Name] {
+			j++
+		}
+		r.executeParallel(ctx, calls[i:j], results[i:j])
+		i = j
+	}
+
+	return results
+}
+
+// executeParallel runs a contiguous run of read-only calls concurrently,
+// writing each outcome into the matching index of results.
+func (r *Registry) executeParallel(ctx context.Context, calls []Call, results []Result) {
+	sem := make(chan struct{}, batchWorkers)
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			output, err := r.Execute(ctx, calls[i].Name, calls[i].Args)
+			results[i] = Result{Output: output, Err: err}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jake/gocode/internal/citation"
 	"github.com/jake/gocode/internal/codegraph"
 )
 
@@ -79,7 +80,7 @@ func (t *FindReferencesTool) Execute(ctx context.Context, args string) (string,
 	result.WriteString(fmt.Sprintf("Found %d reference(s):\n\n", len(symbols)))
 
 	for i, sym := range symbols {
-		result.WriteString(fmt.Sprintf("%d. %s:%d:%d\n", i+1, sym.FilePath, sym.Line, sym.Column))
+		result.WriteString(fmt.Sprintf("%d. %s\n", i+1, citation.Format(sym.FilePath, sym.Line, sym.Line)))
 		if sym.Signature != "" {
 			result.WriteString(fmt.Sprintf("   Context: %s\n", sym.Signature))
 		}
@@ -0,0 +1,324 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// runGit runs a git subcommand rooted at workingDir and returns its trimmed
+// combined output, wrapping failures with that output (git puts the useful
+// detail on stderr, which CombinedOutput captures) rather than just the
+// generic exit error.
+func runGit(ctx context.Context, workingDir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = workingDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GitStatusTool reports the working tree's branch and file states via
+// `git status --porcelain=v2 -b`, structured instead of leaving the model to
+// parse porcelain output itself through bash.
+type GitStatusTool struct {
+	workingDir string
+}
+
+func NewGitStatusTool(workingDir string) *GitStatusTool {
+	return &GitStatusTool{workingDir: workingDir}
+}
+
+func (t *GitStatusTool) Name() string { return "git_status" }
+
+func (t *GitStatusTool) Description() string {
+	return "Reports the current branch, upstream tracking state, and staged/unstaged/untracked file counts and paths. Use this instead of `bash git status` for structured output."
+}
+
+func (t *GitStatusTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *GitStatusTool) Execute(ctx context.Context, args string) (string, error) {
+	out, err := runGit(ctx, t.workingDir, "status", "--porcelain=v2", "-b")
+	if err != nil {
+		return "", NewNotFoundError("git_status", err.Error())
+	}
+
+	var branch, upstream string
+	var staged, unstaged, untracked []string
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.upstream "):
+			upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "1 ") || strings.HasPrefix(line, "2 "):
+			// Ordinary/renamed changed-entry lines: "<kind> <XY> ... <path>",
+			// where a renamed entry appends "\t<origPath>" after the path.
+			entry := line
+			if tab := strings.IndexByte(entry, '\t'); tab >= 0 {
+				entry = entry[:tab]
+			}
+			fields := strings.Fields(entry)
+			if len(fields) < 2 {
+				continue
+			}
+			xy := fields[1]
+			path := fields[len(fields)-1]
+			if xy[0] != '.' {
+				staged = append(staged, path)
+			}
+			if len(xy) > 1 && xy[1] != '.' {
+				unstaged = append(unstaged, path)
+			}
+		case strings.HasPrefix(line, "? "):
+			untracked = append(untracked, strings.TrimPrefix(line, "? "))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Branch: %s", branch)
+	if upstream != "" {
+		fmt.Fprintf(&b, " (tracking %s)", upstream)
+	}
+	b.WriteString("\n")
+	writeGitStatusSection(&b, "Staged", staged)
+	writeGitStatusSection(&b, "Unstaged", unstaged)
+	writeGitStatusSection(&b, "Untracked", untracked)
+	if len(staged) == 0 && len(unstaged) == 0 && len(untracked) == 0 {
+		b.WriteString("\nWorking tree clean.")
+	}
+	return b.String(), nil
+}
+
+func writeGitStatusSection(b *strings.Builder, label string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s (%d):\n", label, len(paths))
+	for _, p := range paths {
+		fmt.Fprintf(b, "  %s\n", p)
+	}
+}
+
+// GitDiffTool renders a diff via `git diff`, for either the working tree or
+// the staging area, optionally scoped to one path.
+type GitDiffTool struct {
+	workingDir string
+}
+
+func NewGitDiffTool(workingDir string) *GitDiffTool {
+	return &GitDiffTool{workingDir: workingDir}
+}
+
+func (t *GitDiffTool) Name() string { return "git_diff" }
+
+func (t *GitDiffTool) Description() string {
+	return "Shows a unified diff of uncommitted changes. Set staged=true to see what's in the index (what `git commit` would record) instead of the working tree; set file_path to scope to one file."
+}
+
+func (t *GitDiffTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"staged": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Show the staged diff (git diff --cached) instead of the working tree diff",
+			},
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "Limit the diff to this file or directory",
+			},
+		},
+	}
+}
+
+type gitDiffArgs struct {
+	Staged   bool   `json:"staged"`
+	FilePath string `json:"file_path"`
+}
+
+func (t *GitDiffTool) Execute(ctx context.Context, args string) (string, error) {
+	var a gitDiffArgs
+	if err := UnmarshalArgs(args, &a); err != nil {
+		return "", NewInvalidArgsError("git_diff", err)
+	}
+
+	gitArgs := []string{"diff"}
+	if a.Staged {
+		gitArgs = append(gitArgs, "--cached")
+	}
+	if a.FilePath != "" {
+		gitArgs = append(gitArgs, "--", a.FilePath)
+	}
+
+	out, err := runGit(ctx, t.workingDir, gitArgs...)
+	if err != nil {
+		return "", NewNotFoundError("git_diff", err.Error())
+	}
+	if out == "" {
+		return "No differences.", nil
+	}
+	return out, nil
+}
+
+// GitLogTool renders recent commit history via `git log --oneline`.
+type GitLogTool struct {
+	workingDir string
+}
+
+func NewGitLogTool(workingDir string) *GitLogTool {
+	return &GitLogTool{workingDir: workingDir}
+}
+
+func (t *GitLogTool) Name() string { return "git_log" }
+
+func (t *GitLogTool) Description() string {
+	return "Shows recent commit history (hash, subject) as one line per commit, most recent first. Set limit (default 20) or file_path to scope to one file's history."
+}
+
+func (t *GitLogTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of commits to show (default 20)",
+			},
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "Limit history to commits touching this file",
+			},
+		},
+	}
+}
+
+type gitLogArgs struct {
+	Limit    int    `json:"limit"`
+	FilePath string `json:"file_path"`
+}
+
+func (t *GitLogTool) Execute(ctx context.Context, args string) (string, error) {
+	var a gitLogArgs
+	if err := UnmarshalArgs(args, &a); err != nil {
+		return "", NewInvalidArgsError("git_log", err)
+	}
+	if a.Limit <= 0 {
+		a.Limit = 20
+	}
+
+	gitArgs := []string{"log", "--oneline", "-n", strconv.Itoa(a.Limit)}
+	if a.FilePath != "" {
+		gitArgs = append(gitArgs, "--", a.FilePath)
+	}
+
+	out, err := runGit(ctx, t.workingDir, gitArgs...)
+	if err != nil {
+		return "", NewNotFoundError("git_log", err.Error())
+	}
+	if out == "" {
+		return "No commits yet.", nil
+	}
+	return out, nil
+}
+
+// GitCommitTool stages and commits changes via `git commit`, gated by
+// tools.git.allow_commit since letting a model commit unattended is a
+// stronger permission than its other file-editing tools.
+type GitCommitTool struct {
+	workingDir  string
+	allowCommit bool
+}
+
+func NewGitCommitTool(workingDir string, allowCommit bool) *GitCommitTool {
+	return &GitCommitTool{workingDir: workingDir, allowCommit: allowCommit}
+}
+
+func (t *GitCommitTool) Name() string { return "git_commit" }
+
+func (t *GitCommitTool) Description() string {
+	return "Stages and commits changes. Set all=true to stage every modification first (git add -A), or leave false to commit only what's already staged. If message is omitted, one is generated from the staged diff's file list."
+}
+
+func (t *GitCommitTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"message": map[string]interface{}{
+				"type":        "string",
+				"description": "Commit message; auto-generated from the staged changes if omitted",
+			},
+			"all": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Stage all changes, including untracked files, before committing (git add -A)",
+			},
+		},
+	}
+}
+
+type gitCommitArgs struct {
+	Message string `json:"message"`
+	All     bool   `json:"all"`
+}
+
+func (t *GitCommitTool) Execute(ctx context.Context, args string) (string, error) {
+	if !t.allowCommit {
+		return "", NewPermissionDeniedError("git_commit", "committing is disabled - set tools.git.allow_commit: true in config.yaml to allow it")
+	}
+
+	var a gitCommitArgs
+	if err := UnmarshalArgs(args, &a); err != nil {
+		return "", NewInvalidArgsError("git_commit", err)
+	}
+
+	if a.All {
+		if _, err := runGit(ctx, t.workingDir, "add", "-A"); err != nil {
+			return "", NewNotFoundError("git_commit", err.Error())
+		}
+	}
+
+	message := a.Message
+	if message == "" {
+		generated, err := t.generateMessage(ctx)
+		if err != nil {
+			return "", NewNotFoundError("git_commit", err.Error())
+		}
+		message = generated
+	}
+
+	out, err := runGit(ctx, t.workingDir, "commit", "-m", message)
+	if err != nil {
+		return "", NewNotFoundError("git_commit", err.Error())
+	}
+	return out, nil
+}
+
+// generateMessage summarizes the staged diff's changed file list into a
+// short commit message when the model doesn't supply one, e.g. "Update
+// foo.go, bar.go".
+func (t *GitCommitTool) generateMessage(ctx context.Context) (string, error) {
+	out, err := runGit(ctx, t.workingDir, "diff", "--cached", "--name-only")
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", fmt.Errorf("nothing staged to commit")
+	}
+
+	files := strings.Split(out, "\n")
+	if len(files) == 1 {
+		return fmt.Sprintf("Update %s", files[0]), nil
+	}
+	if len(files) <= 4 {
+		return fmt.Sprintf("Update %s", strings.Join(files, ", ")), nil
+	}
+	return fmt.Sprintf("Update %d files", len(files)), nil
+}
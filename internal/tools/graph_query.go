@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jake/gocode/internal/codegraph"
+)
+
+// GraphQueryTool lets the agent pull a symbol's dependency/containment
+// subgraph on demand, e.g. when a user asks "how does X relate to Y" or
+// "draw me the architecture around the Agent type".
+type GraphQueryTool struct {
+	graph *codegraph.Graph
+}
+
+// NewGraphQueryTool creates a new graph query tool
+func NewGraphQueryTool(graph *codegraph.Graph) *GraphQueryTool {
+	return &GraphQueryTool{graph: graph}
+}
+
+func (t *GraphQueryTool) Name() string {
+	return "graph_query"
+}
+
+func (t *GraphQueryTool) Description() string {
+	return "Look up a symbol by name and return its dependency/containment subgraph (definitions, calls, references) as text or a Mermaid diagram. Use this to explain how a type or function relates to the rest of the codebase."
+}
+
+func (t *GraphQueryTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"symbol": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of the function, type, or variable to center the subgraph on",
+			},
+			"depth": map[string]interface{}{
+				"type":        "number",
+				"description": "How many edge hops to traverse from the symbol (default 2)",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "\"text\" for a readable list (default), or \"mermaid\" for a diagram to embed in a doc",
+				"enum":        []string{"text", "mermaid"},
+			},
+		},
+		"required": []string{"symbol"},
+	}
+}
+
+type GraphQueryArgs struct {
+	Symbol string `json:"symbol"`
+	Depth  int    `json:"depth"`
+	Format string `json:"format"`
+}
+
+func (t *GraphQueryTool) Execute(ctx context.Context, args string) (string, error) {
+	var a GraphQueryArgs
+	if err := UnmarshalArgs(args, &a); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+	if a.Symbol == "" {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("symbol is required"))
+	}
+	if a.Depth <= 0 {
+		a.Depth = 2
+	}
+
+	matches := t.graph.FindByName(a.Symbol)
+	if len(matches) == 0 {
+		return "", NewNotFoundError(t.Name(), fmt.Sprintf("no indexed symbol named %q (has the file been read/indexed yet?)", a.Symbol))
+	}
+
+	// Ambiguous names (overloaded methods, same type in different packages)
+	// all get traversed and merged into one subgraph rather than guessing.
+	seen := make(map[string]*codegraph.SymbolNode)
+	for _, m := range matches {
+		for _, n := range t.graph.TraverseFrom(m.ID, "", a.Depth) {
+			seen[n.ID] = n
+		}
+	}
+	nodes := make([]*codegraph.SymbolNode, 0, len(seen))
+	for _, n := range seen {
+		nodes = append(nodes, n)
+	}
+	edges := t.graph.SubgraphEdges(nodes)
+
+	if a.Format == "mermaid" {
+		return codegraph.RenderMermaid(nodes, edges), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Subgraph around %q (%d symbols, %d edges):\n\n", a.Symbol, len(nodes), len(edges))
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "- %s (%s) at %s:%d\n", n.Name, n.Kind, n.FilePath, n.Line)
+	}
+	for _, e := range edges {
+		from, to := t.graph.LookupName(e.From), t.graph.LookupName(e.To)
+		fmt.Fprintf(&b, "  %s --%s--> %s\n", from, e.Type, to)
+	}
+	return b.String(), nil
+}
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/jake/gocode/internal/citation"
 )
 
 type GrepTool struct{}
@@ -137,7 +139,7 @@ func (t *GrepTool) Execute(ctx context.Context, args string) (string, error) {
 
 				if grepArgs.OutputMode == "content" {
 					if grepArgs.LineNumbers {
-						result.WriteString(fmt.Sprintf("%s:%d:%s\n", path, lineNum, line))
+						result.WriteString(fmt.Sprintf("%s: %s\n", citation.Format(path, lineNum, lineNum), line))
 					} else {
 						result.WriteString(fmt.Sprintf("%s:%s\n", path, line))
 					}
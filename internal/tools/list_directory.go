@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jake/gocode/internal/gitignore"
+)
+
+// ListDirectoryTool returns a structured directory listing so the model
+// doesn't have to reach for platform-divergent `dir`/`ls` bash calls just
+// to explore.
+type ListDirectoryTool struct{}
+
+func (t *ListDirectoryTool) Name() string {
+	return "list_directory"
+}
+
+func (t *ListDirectoryTool) Description() string {
+	return "Lists a directory as a tree, showing names, sizes, and types up to a depth limit. Skips gitignored paths by default."
+}
+
+func (t *ListDirectoryTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "The directory to list (defaults to current working directory)",
+			},
+			"max_depth": map[string]interface{}{
+				"type":        "number",
+				"description": "How many directory levels to descend (default 2)",
+			},
+			"show_ignored": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Include paths that would normally be skipped via .gitignore (default false)",
+			},
+		},
+	}
+}
+
+type ListDirectoryArgs struct {
+	Path        string `json:"path,omitempty"`
+	MaxDepth    int    `json:"max_depth,omitempty"`
+	ShowIgnored bool   `json:"show_ignored,omitempty"`
+}
+
+func (t *ListDirectoryTool) Execute(ctx context.Context, args string) (string, error) {
+	var listArgs ListDirectoryArgs
+	if err := UnmarshalArgs(args, &listArgs); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+
+	root := listArgs.Path
+	if root == "" {
+		var err error
+		root, err = os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get working directory: %w", err)
+		}
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", NewNotFoundError(t.Name(), fmt.Sprintf("directory not found: %s", root))
+		}
+		return "", fmt.Errorf("failed to stat directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("%s is not a directory", root))
+	}
+
+	maxDepth := listArgs.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 2
+	}
+
+	ignorePatterns := gitignore.LoadPatterns(root)
+
+	var result strings.Builder
+	result.WriteString(root)
+	result.WriteString("\n")
+	if err := t.walk(&result, root, "", 0, maxDepth, ignorePatterns, listArgs.ShowIgnored); err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(result.String(), "\n"), nil
+}
+
+func (t *ListDirectoryTool) walk(out *strings.Builder, dir, prefix string, depth, maxDepth int, ignorePatterns []string, showIgnored bool) error {
+	if depth >= maxDepth {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	for i, entry := range entries {
+		if !showIgnored && gitignore.IsIgnored(entry.Name(), ignorePatterns) {
+			continue
+		}
+
+		connector := "├── "
+		nextPrefix := prefix + "│   "
+		if i == len(entries)-1 {
+			connector = "└── "
+			nextPrefix = prefix + "    "
+		}
+
+		line := prefix + connector + entry.Name()
+		if entry.IsDir() {
+			line += "/"
+		} else if info, err := entry.Info(); err == nil {
+			line += fmt.Sprintf(" (%s)", humanBytes(info.Size()))
+		}
+		out.WriteString(line)
+		out.WriteString("\n")
+
+		if entry.IsDir() {
+			if err := t.walk(out, filepath.Join(dir, entry.Name()), nextPrefix, depth+1, maxDepth, ignorePatterns, showIgnored); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for n1 := n / unit; n1 >= unit; n1 /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
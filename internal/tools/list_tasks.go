@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Task describes a single discoverable project command (a Makefile target,
+// a package.json script, ...), so the agent can run the project's own
+// canonical commands instead of guessing raw build/test invocations.
+type Task struct {
+	Source      string `json:"source"` // "Makefile", "Taskfile.yml", "package.json", "pyproject.toml"
+	Name        string `json:"name"`
+	Command     string `json:"command,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ListTasksTool discovers task runner targets across the handful of
+// manifests common to the ecosystems this project already detects
+// (initialization.Analyzer.detectFrameworks).
+type ListTasksTool struct {
+	workingDir string
+}
+
+func NewListTasksTool(workingDir string) *ListTasksTool {
+	return &ListTasksTool{workingDir: workingDir}
+}
+
+func (t *ListTasksTool) Name() string {
+	return "list_tasks"
+}
+
+func (t *ListTasksTool) Description() string {
+	return "Lists the project's canonical task/build commands from Makefile, Taskfile.yml, package.json scripts, and pyproject.toml scripts. Use this before inventing a raw build/test/lint invocation."
+}
+
+func (t *ListTasksTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *ListTasksTool) Execute(ctx context.Context, args string) (string, error) {
+	var tasks []Task
+	tasks = append(tasks, parseMakefile(filepath.Join(t.workingDir, "Makefile"))...)
+	tasks = append(tasks, parseTaskfile(filepath.Join(t.workingDir, "Taskfile.yml"))...)
+	tasks = append(tasks, parseTaskfile(filepath.Join(t.workingDir, "Taskfile.yaml"))...)
+	tasks = append(tasks, parsePackageJSONScripts(filepath.Join(t.workingDir, "package.json"))...)
+	tasks = append(tasks, parsePyprojectScripts(filepath.Join(t.workingDir, "pyproject.toml"))...)
+
+	if len(tasks) == 0 {
+		return "No Makefile, Taskfile, package.json, or pyproject.toml task definitions found", nil
+	}
+
+	var out strings.Builder
+	currentSource := ""
+	for _, task := range tasks {
+		if task.Source != currentSource {
+			fmt.Fprintf(&out, "\n## %s\n\n", task.Source)
+			currentSource = task.Source
+		}
+		if task.Description != "" {
+			fmt.Fprintf(&out, "- %s: %s (%s)\n", task.Name, task.Description, task.Command)
+		} else {
+			fmt.Fprintf(&out, "- %s: %s\n", task.Name, task.Command)
+		}
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// makefileTargetPattern matches a target line (name: deps), skipping
+// pattern rules (%) and special targets (.PHONY etc).
+var makefileTargetPattern = regexp.MustCompile(`^([a-zA-Z0-9_-]+):[^=]*$`)
+
+// parseMakefile extracts targets, pairing each with a preceding "## desc"
+// comment when present (the common self-documenting Makefile convention).
+func parseMakefile(path string) []Task {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var tasks []Task
+	lastComment := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "##") {
+			lastComment = strings.TrimSpace(strings.TrimPrefix(trimmed, "##"))
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") || trimmed == "" {
+			continue
+		}
+
+		if m := makefileTargetPattern.FindStringSubmatch(line); m != nil {
+			name := m[1]
+			if strings.HasPrefix(name, ".") {
+				lastComment = ""
+				continue // special target like .PHONY
+			}
+			tasks = append(tasks, Task{
+				Source:      "Makefile",
+				Name:        name,
+				Command:     fmt.Sprintf("make %s", name),
+				Description: lastComment,
+			})
+			lastComment = ""
+			continue
+		}
+		lastComment = ""
+	}
+	return tasks
+}
+
+// taskfileSpec models the subset of a go-task Taskfile.yml this tool reads.
+type taskfileSpec struct {
+	Tasks map[string]struct {
+		Desc string      `yaml:"desc"`
+		Cmds []yaml.Node `yaml:"cmds"`
+	} `yaml:"tasks"`
+}
+
+func parseTaskfile(path string) []Task {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var spec taskfileSpec
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return nil
+	}
+
+	var tasks []Task
+	for name, def := range spec.Tasks {
+		tasks = append(tasks, Task{
+			Source:      filepath.Base(path),
+			Name:        name,
+			Command:     fmt.Sprintf("task %s", name),
+			Description: def.Desc,
+		})
+	}
+	return tasks
+}
+
+func parsePackageJSONScripts(path string) []Task {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil
+	}
+
+	var tasks []Task
+	for name, cmd := range pkg.Scripts {
+		tasks = append(tasks, Task{
+			Source:  "package.json",
+			Name:    name,
+			Command: fmt.Sprintf("npm run %s", name),
+		})
+		tasks[len(tasks)-1].Description = cmd
+	}
+	return tasks
+}
+
+// pyprojectScriptSection matches a "[project.scripts]" or
+// "[tool.poetry.scripts]" table header, and pyprojectScriptEntry matches a
+// "name = \"module:func\"" line inside it. No TOML dependency is vendored
+// in this project, so this is a line-oriented reader rather than a full
+// parser - it only understands the flat script tables, not nested/inline
+// tables or multi-line values.
+var (
+	pyprojectScriptSection = regexp.MustCompile(`^\[(?:project\.scripts|tool\.poetry\.scripts)\]$`)
+	pyprojectScriptEntry   = regexp.MustCompile(`^([\w.-]+)\s*=\s*"(.+)"$`)
+	pyprojectAnySection    = regexp.MustCompile(`^\[.+\]$`)
+)
+
+func parsePyprojectScripts(path string) []Task {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var tasks []Task
+	inScripts := false
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if pyprojectScriptSection.MatchString(trimmed) {
+			inScripts = true
+			continue
+		}
+		if pyprojectAnySection.MatchString(trimmed) {
+			inScripts = false
+			continue
+		}
+		if !inScripts {
+			continue
+		}
+		if m := pyprojectScriptEntry.FindStringSubmatch(trimmed); m != nil {
+			tasks = append(tasks, Task{
+				Source:  "pyproject.toml",
+				Name:    m[1],
+				Command: m[1],
+			})
+			tasks[len(tasks)-1].Description = m[2]
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+// NoteTool gives the model a scratchpad for intermediate reasoning it wants
+// to keep around (a hypothesis to check later, a plan sketch) without
+// dumping it into the reply the user actually reads. The note still lands
+// in the conversation as a tool-result message, so the model can see it on
+// later turns, but the agent loop renders it dimmed and it's left out of
+// appendToConversationHistory's export.
+type NoteTool struct{}
+
+func NewNoteTool() *NoteTool {
+	return &NoteTool{}
+}
+
+func (t *NoteTool) Name() string {
+	return "note"
+}
+
+func (t *NoteTool) Description() string {
+	return "Records a scratchpad note for yourself - intermediate reasoning, a hypothesis to verify, a reminder for later in the turn. Notes stay in your context but are hidden from the user's transcript, so use this instead of thinking out loud in your reply."
+}
+
+func (t *NoteTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"content": map[string]interface{}{
+				"type":        "string",
+				"description": "The note to record",
+			},
+		},
+		"required": []string{"content"},
+	}
+}
+
+type NoteArgs struct {
+	Content string `json:"content"`
+}
+
+func (t *NoteTool) Execute(ctx context.Context, args string) (string, error) {
+	var noteArgs NoteArgs
+	if err := UnmarshalArgs(args, &noteArgs); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if noteArgs.Content == "" {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("content is required"))
+	}
+
+	return "Noted.", nil
+}
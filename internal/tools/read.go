@@ -48,11 +48,17 @@ type ReadArgs struct {
 func (t *ReadTool) Execute(ctx context.Context, args string) (string, error) {
 	var readArgs ReadArgs
 	if err := UnmarshalArgs(args, &readArgs); err != nil {
-		return "", fmt.Errorf("invalid arguments: %w", err)
+		return "", NewInvalidArgsError(t.Name(), err)
 	}
 
 	file, err := os.Open(readArgs.FilePath)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return "", NewNotFoundError(t.Name(), fmt.Sprintf("file not found: %s", readArgs.FilePath))
+		}
+		if os.IsPermission(err) {
+			return "", NewPermissionDeniedError(t.Name(), fmt.Sprintf("permission denied: %s", readArgs.FilePath))
+		}
 		return "", fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jake/gocode/internal/codegraph"
+)
+
+// RelatedTestsTool locates the test file(s) for a source file, by naming
+// convention and (when a code graph is available) by symbol reference, so
+// the agent can check whether an edit needs a matching test update without
+// grepping for it by hand.
+type RelatedTestsTool struct {
+	graph      *codegraph.Graph // nil unless LSP/codegraph is enabled - reference-based matches are skipped without it
+	workingDir string
+}
+
+func NewRelatedTestsTool(graph *codegraph.Graph, workingDir string) *RelatedTestsTool {
+	return &RelatedTestsTool{graph: graph, workingDir: workingDir}
+}
+
+func (t *RelatedTestsTool) Name() string {
+	return "related_tests"
+}
+
+func (t *RelatedTestsTool) Description() string {
+	return "Finds the test file(s) associated with a source file, by naming convention (foo.go -> foo_test.go, foo.py -> test_foo.py, ...) and by code graph references. Use this after editing a source file to check whether its tests need updating too."
+}
+
+func (t *RelatedTestsTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the source file to find tests for",
+			},
+		},
+		"required": []string{"file_path"},
+	}
+}
+
+type RelatedTestsArgs struct {
+	FilePath string `json:"file_path"`
+}
+
+func (t *RelatedTestsTool) Execute(ctx context.Context, args string) (string, error) {
+	var rtArgs RelatedTestsArgs
+	if err := UnmarshalArgs(args, &rtArgs); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+	if rtArgs.FilePath == "" {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("file_path is required"))
+	}
+
+	matches := t.Find(ctx, rtArgs.FilePath)
+	if len(matches) == 0 {
+		return "No related test files found", nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Found %d related test file(s):\n", len(matches)))
+	for _, m := range matches {
+		b.WriteString(fmt.Sprintf("- %s\n", m))
+	}
+	return b.String(), nil
+}
+
+// Find returns the test file(s) associated with filePath, deduplicated and
+// filtered to files that actually exist on disk. It's exported as a plain
+// method (not just reachable through Execute) so the agent package can
+// reuse the same lookup for automatic context injection after an edit.
+func (t *RelatedTestsTool) Find(ctx context.Context, filePath string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	add := func(path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		if _, err := os.Stat(t.resolve(path)); err != nil {
+			return
+		}
+		seen[path] = true
+		matches = append(matches, path)
+	}
+
+	for _, candidate := range namingConventionTestPaths(filePath) {
+		add(candidate)
+	}
+
+	if t.graph != nil {
+		if err := t.graph.IndexFile(ctx, filePath); err == nil {
+			for _, sym := range t.graph.GetSymbolsByFile(filePath) {
+				refs, err := t.graph.FindReferences(ctx, filePath, sym.Line, sym.Column)
+				if err != nil {
+					continue
+				}
+				for _, ref := range refs {
+					if looksLikeTestFile(ref.FilePath) {
+						add(ref.FilePath)
+					}
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+func (t *RelatedTestsTool) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(t.workingDir, path)
+}
+
+// namingConventionTestPaths lists the test file path(s) a source file would
+// have under each ecosystem's own naming convention, without checking
+// whether they exist - Find() filters those out.
+func namingConventionTestPaths(filePath string) []string {
+	dir := filepath.Dir(filePath)
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+
+	switch ext {
+	case ".go":
+		if strings.HasSuffix(name, "_test") {
+			return nil // already a test file
+		}
+		return []string{filepath.Join(dir, name+"_test.go")}
+	case ".py":
+		if strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test") {
+			return nil
+		}
+		return []string{
+			filepath.Join(dir, "test_"+name+".py"),
+			filepath.Join(dir, name+"_test.py"),
+			filepath.Join(dir, "tests", "test_"+name+".py"),
+		}
+	case ".js", ".jsx", ".ts", ".tsx":
+		if strings.HasSuffix(name, ".test") || strings.HasSuffix(name, ".spec") {
+			return nil
+		}
+		return []string{
+			filepath.Join(dir, name+".test"+ext),
+			filepath.Join(dir, name+".spec"+ext),
+			filepath.Join(dir, "__tests__", base),
+		}
+	default:
+		return nil
+	}
+}
+
+// looksLikeTestFile is a coarse filter for the code-graph reference path,
+// so a file that merely imports the source file isn't reported as its test
+// just because it happens to reference a symbol from it.
+func looksLikeTestFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasSuffix(base, "_test.go") ||
+		strings.HasPrefix(base, "test_") ||
+		strings.Contains(base, ".test.") ||
+		strings.Contains(base, ".spec.") ||
+		strings.Contains(path, "__tests__/") ||
+		strings.Contains(path, "/tests/")
+}
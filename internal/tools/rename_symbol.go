@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jake/gocode/internal/lsp"
+)
+
+// RenameSymbolTool renames a symbol across every file that references it,
+// using the language server's textDocument/rename support instead of a
+// grep+edit loop that can miss occurrences or rewrite unrelated identifiers
+// that happen to share the same name.
+type RenameSymbolTool struct {
+	lspMgr   *lsp.Manager
+	registry *Registry
+}
+
+// NewRenameSymbolTool takes the session's Registry (rather than, say, just
+// its Ledger) because applyWorkspaceEdit needs the full authorize/record
+// pipeline - path normalization, policy, conflict check, file-change hook -
+// for every file the rename touches, not just one piece of it. registry is
+// read at call time, so it sees state (e.g. SetFileChangeHook) configured
+// after this tool is constructed.
+func NewRenameSymbolTool(lspMgr *lsp.Manager, registry *Registry) *RenameSymbolTool {
+	return &RenameSymbolTool{lspMgr: lspMgr, registry: registry}
+}
+
+func (t *RenameSymbolTool) Name() string {
+	return "rename_symbol"
+}
+
+func (t *RenameSymbolTool) Description() string {
+	return "Renames the symbol at a file position, updating every file that references it via the language server's rename support. Prefer this over grep+edit for cross-file refactors."
+}
+
+func (t *RenameSymbolTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the file containing the symbol",
+			},
+			"line": map[string]interface{}{
+				"type":        "number",
+				"description": "Line number (0-indexed) where the symbol appears",
+			},
+			"column": map[string]interface{}{
+				"type":        "number",
+				"description": "Column number (0-indexed) where the symbol appears",
+			},
+			"new_name": map[string]interface{}{
+				"type":        "string",
+				"description": "The new name for the symbol",
+			},
+		},
+		"required": []string{"file_path", "line", "column", "new_name"},
+	}
+}
+
+type RenameSymbolArgs struct {
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	NewName  string `json:"new_name"`
+}
+
+func (t *RenameSymbolTool) Execute(ctx context.Context, args string) (string, error) {
+	var rArgs RenameSymbolArgs
+	if err := UnmarshalArgs(args, &rArgs); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	if rArgs.NewName == "" {
+		return "", fmt.Errorf("new_name is required")
+	}
+
+	edit, err := t.lspMgr.Rename(ctx, rArgs.FilePath, rArgs.Line, rArgs.Column, rArgs.NewName)
+	if err != nil {
+		return "", fmt.Errorf("rename failed: %w", err)
+	}
+
+	changed, err := applyWorkspaceEdit(t.registry, t.Name(), edit)
+	if err != nil {
+		return "", fmt.Errorf("failed to apply rename: %w", err)
+	}
+	if len(changed) == 0 {
+		return "The language server returned no edits - nothing was renamed", nil
+	}
+
+	return fmt.Sprintf("Renamed to %q across %d file(s):\n- %s", rArgs.NewName, len(changed), strings.Join(changed, "\n- ")), nil
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// FileSnapshot preserves a file's content from just before a file-changing
+// tool call touched it, so RollbackFilesTool (and /undo) can restore it.
+// Existed distinguishes "restore this content" from "the file didn't exist
+// yet, so rolling back should delete it".
+type FileSnapshot struct {
+	Path    string
+	Existed bool
+	Content []byte
+}
+
+// RollbackFilesTool tracks every file mutation the agent makes during a
+// turn (fed by Snapshot, called once per path before write/edit/delete_file
+// runs) so the model can invoke it itself to undo its own change when that
+// change breaks the build, and so the agent can offer the same revert as
+// /undo once the turn finishes.
+type RollbackFilesTool struct {
+	mu      sync.Mutex
+	current []FileSnapshot // this turn, appended to as file-changing tools run
+	lastRun []FileSnapshot // the most recently finished turn's snapshots, for /undo
+}
+
+func NewRollbackFilesTool() *RollbackFilesTool {
+	return &RollbackFilesTool{}
+}
+
+func (t *RollbackFilesTool) Name() string {
+	return "rollback_files"
+}
+
+func (t *RollbackFilesTool) Description() string {
+	return "Reverts every file written, edited, or deleted so far this turn back to its content from before the turn started. Use this when a change you just made breaks the build and starting over is cleaner than patching it further."
+}
+
+func (t *RollbackFilesTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+// Snapshot records path's pre-mutation content, unless it's already been
+// captured this turn - only the first snapshot per path is kept, since a
+// rollback should restore the state at the start of the turn, not an
+// intermediate edit within it.
+func (t *RollbackFilesTool) Snapshot(path string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, s := range t.current {
+		if s.Path == path {
+			return
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.current = append(t.current, FileSnapshot{Path: path})
+		}
+		return
+	}
+	t.current = append(t.current, FileSnapshot{Path: path, Existed: true, Content: content})
+}
+
+// BeginTurn discards any snapshots left over from a turn that never used or
+// handed them off (see EndTurn), so a stale rollback can't fire mid-turn.
+func (t *RollbackFilesTool) BeginTurn() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.current = nil
+}
+
+// EndTurn hands this turn's snapshots to /undo, once the turn is done using
+// them for its own in-turn rollback_files calls.
+func (t *RollbackFilesTool) EndTurn() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.current) > 0 {
+		t.lastRun = t.current
+	}
+}
+
+// RestoreLastRun restores every snapshot handed off by the most recently
+// finished turn and clears it, for /undo. A nil, nil return means there was
+// nothing to undo.
+func (t *RollbackFilesTool) RestoreLastRun() ([]string, error) {
+	t.mu.Lock()
+	snapshots := t.lastRun
+	t.lastRun = nil
+	t.mu.Unlock()
+
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+
+	restored := restoreSnapshots(snapshots)
+	if len(restored) == 0 {
+		return nil, fmt.Errorf("failed to restore any of %d tracked file(s)", len(snapshots))
+	}
+	return restored, nil
+}
+
+func (t *RollbackFilesTool) Execute(ctx context.Context, args string) (string, error) {
+	t.mu.Lock()
+	snapshots := t.current
+	t.current = nil
+	t.mu.Unlock()
+
+	if len(snapshots) == 0 {
+		return "No file changes recorded yet this turn - nothing to roll back.", nil
+	}
+
+	restored := restoreSnapshots(snapshots)
+	if len(restored) == 0 {
+		return "", fmt.Errorf("failed to restore any of %d tracked file(s)", len(snapshots))
+	}
+
+	return fmt.Sprintf("Rolled back %d file(s):\n- %s", len(restored), strings.Join(restored, "\n- ")), nil
+}
+
+// restoreSnapshots writes each snapshot's original content back, or removes
+// the file if it didn't exist before the turn, returning the paths actually
+// restored.
+func restoreSnapshots(snapshots []FileSnapshot) []string {
+	var restored []string
+	for _, s := range snapshots {
+		if !s.Existed {
+			if err := os.Remove(s.Path); err == nil {
+				restored = append(restored, s.Path)
+			}
+			continue
+		}
+		if err := os.WriteFile(s.Path, s.Content, 0644); err == nil {
+			restored = append(restored, s.Path)
+		}
+	}
+	return restored
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jake/gocode/internal/config"
+)
+
+// checkJail rejects dir if a sandbox is enabled and dir falls outside its
+// WorkingDirJail (default: the sandbox's own zero value means "no jail
+// configured", so this is a no-op unless the caller opted in).
+func checkJail(sandbox config.SandboxConfig, dir string) error {
+	if !sandbox.Enabled || sandbox.WorkingDirJail == "" {
+		return nil
+	}
+
+	jail, err := filepath.Abs(sandbox.WorkingDirJail)
+	if err != nil {
+		return fmt.Errorf("invalid sandbox working_dir_jail %q: %w", sandbox.WorkingDirJail, err)
+	}
+	target, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid working directory %q: %w", dir, err)
+	}
+
+	rel, err := filepath.Rel(jail, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("directory %q is outside the sandbox jail %q", dir, jail)
+	}
+	return nil
+}
+
+// sandboxedEnv returns the environment a sandboxed command should inherit:
+// unfiltered if EnvAllowlist is empty, or only the allowlisted variables
+// (still reading their live value from the agent's own environment)
+// otherwise.
+func sandboxedEnv(sandbox config.SandboxConfig) []string {
+	if !sandbox.Enabled || len(sandbox.EnvAllowlist) == 0 {
+		return os.Environ()
+	}
+
+	env := make([]string, 0, len(sandbox.EnvAllowlist))
+	for _, name := range sandbox.EnvAllowlist {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+// buildSandboxedCommand builds the *exec.Cmd for running command in dir
+// under sandbox, choosing the right shell per OS (bash.go previously always
+// shelled out to cmd.exe, which only works on Windows) and, on Linux with
+// UseNamespaces set, wrapping it in `unshare --mount --pid --fork` for
+// filesystem/process isolation when that binary is present. A host that
+// lacks unshare (or isn't Linux) degrades to the jail/env/output limits
+// alone rather than failing the call outright.
+func buildSandboxedCommand(ctx context.Context, command, dir string, sandbox config.SandboxConfig) *exec.Cmd {
+	shellArgs := []string{"-c", command}
+	shellPath := "sh"
+	if runtime.GOOS == "windows" {
+		shellPath = "cmd"
+		shellArgs = []string{"/C", command}
+	}
+
+	if sandbox.Enabled && sandbox.UseNamespaces && runtime.GOOS == "linux" {
+		if unsharePath, err := exec.LookPath("unshare"); err == nil {
+			nsArgs := append([]string{"--mount", "--pid", "--fork", "--", shellPath}, shellArgs...)
+			cmd := exec.CommandContext(ctx, unsharePath, nsArgs...)
+			cmd.Dir = dir
+			cmd.Env = sandboxedEnv(sandbox)
+			return cmd
+		}
+		// No unshare on this host - fall through to the unsandboxed shell,
+		// still under the jail/env/output limits configured above.
+	}
+
+	cmd := exec.CommandContext(ctx, shellPath, shellArgs...)
+	cmd.Dir = dir
+	cmd.Env = sandboxedEnv(sandbox)
+	return cmd
+}
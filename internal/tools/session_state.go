@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LineRange records a slice of a file's lines the model has already seen.
+// End of 0 means "to the end of the file at the time it was recorded".
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end,omitempty"`
+}
+
+// FileLedgerEntry is one file's read/edit history for the session.
+type FileLedgerEntry struct {
+	Path        string      `json:"path"`
+	VersionHash string      `json:"version_hash"` // sha256 of the file's contents as of LastAction, "" if it couldn't be read
+	LinesSeen   []LineRange `json:"lines_seen"`
+	LastAction  string      `json:"last_action"` // "read" or "edit" (write calls are recorded as "edit")
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// FileLedger tracks which files the model has read or edited this session,
+// backing the session_state tool so the model can check what it already
+// knows instead of re-exploring, and so context pruning can annotate what
+// content was dropped.
+type FileLedger struct {
+	mu      sync.RWMutex
+	entries map[string]*FileLedgerEntry
+}
+
+func NewFileLedger() *FileLedger {
+	return &FileLedger{entries: make(map[string]*FileLedgerEntry)}
+}
+
+// Record notes that action ("read" or "edit") touched path,
+// covering lines [offset, offset+limit) - or the whole file when offset and
+// limit are both 0, which is how write/edit calls (which have no such
+// arguments) are recorded.
+func (l *FileLedger) Record(path, action string, offset, limit int) {
+	hash := hashFile(path)
+	rng := LineRange{Start: offset}
+	if offset == 0 && limit == 0 {
+		rng.Start = 1
+	} else if limit > 0 {
+		rng.End = offset + limit - 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[path]
+	if !ok {
+		entry = &FileLedgerEntry{Path: path}
+		l.entries[path] = entry
+	}
+	entry.LinesSeen = append(entry.LinesSeen, rng)
+	entry.VersionHash = hash
+	entry.LastAction = action
+	entry.UpdatedAt = time.Now()
+}
+
+// Entry returns a copy of path's ledger entry, so callers like conflict
+// detection can inspect it without holding the ledger's lock.
+func (l *FileLedger) Entry(path string) (FileLedgerEntry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entry, ok := l.entries[path]
+	if !ok {
+		return FileLedgerEntry{}, false
+	}
+	return *entry, true
+}
+
+// Snapshot returns a path-sorted copy of the ledger for display.
+func (l *FileLedger) Snapshot() []FileLedgerEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	out := make([]FileLedgerEntry, 0, len(l.entries))
+	for _, e := range l.entries {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+func hashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SessionStateTool exposes the FileLedger to the model as a tool call, so it
+// can check what it's already read or edited instead of re-exploring the
+// workspace from scratch.
+type SessionStateTool struct {
+	ledger *FileLedger
+}
+
+func NewSessionStateTool(ledger *FileLedger) *SessionStateTool {
+	return &SessionStateTool{ledger: ledger}
+}
+
+func (t *SessionStateTool) Name() string {
+	return "session_state"
+}
+
+func (t *SessionStateTool) Description() string {
+	return "Returns the ledger of files read or edited so far this session: path, content hash, line ranges seen, and last action. Check this before re-reading a file you've likely already seen."
+}
+
+func (t *SessionStateTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+func (t *SessionStateTool) Execute(ctx context.Context, args string) (string, error) {
+	entries := t.ledger.Snapshot()
+	if len(entries) == 0 {
+		return "No files read or edited yet this session.", nil
+	}
+
+	var b strings.Builder
+	for _, e := range entries {
+		ranges := make([]string, len(e.LinesSeen))
+		for i, r := range e.LinesSeen {
+			if r.End == 0 {
+				ranges[i] = fmt.Sprintf("%d-end", r.Start)
+			} else {
+				ranges[i] = fmt.Sprintf("%d-%d", r.Start, r.End)
+			}
+		}
+		hash := e.VersionHash
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+		fmt.Fprintf(&b, "%s [%s] hash=%s lines=%s (updated %s)\n",
+			e.Path, e.LastAction, hash, strings.Join(ranges, ","), e.UpdatedAt.Format("15:04:05"))
+	}
+	return b.String(), nil
+}
+
+// ledgerArgs is the subset of tool arguments shared by read/write/edit/
+// edit_structured, used to record what got touched without importing each
+// tool's own Args type.
+type ledgerArgs struct {
+	FilePath string `json:"file_path"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+}
+
+// recordLedger updates ledger after a successful call to one of the
+// file-content tools. Unmarshal errors are ignored since this is
+// best-effort bookkeeping, not something a tool call should fail over.
+func recordLedger(ledger *FileLedger, name, args string) {
+	if ledger == nil {
+		return
+	}
+
+	action := "read"
+	if name != "read" {
+		action = "edit"
+	}
+
+	var a ledgerArgs
+	if err := json.Unmarshal([]byte(args), &a); err != nil || a.FilePath == "" {
+		return
+	}
+	ledger.Record(a.FilePath, action, a.Offset, a.Limit)
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
 type TodoWriteTool struct {
@@ -183,6 +185,86 @@ func (t *TodoWriteTool) GetTodos() []TodoItem {
 	return append([]TodoItem{}, t.todos...)
 }
 
+// ReconcileSession runs once at the start of a session so a list from a
+// prior run doesn't get silently re-injected into every turn forever: a
+// list that finished completely is archived and cleared automatically,
+// while a list with leftover pending/in-progress work is offered back to
+// the caller (via confirmCarryOver) since there's no way to tell "still
+// relevant" from "the user moved on" without asking. confirmCarryOver may
+// be nil, in which case leftover work is archived rather than kept.
+func (t *TodoWriteTool) ReconcileSession(archiveDir string, confirmCarryOver func(pending int) bool) (archived string, carriedOver int, err error) {
+	t.mu.RLock()
+	todos := append([]TodoItem{}, t.todos...)
+	t.mu.RUnlock()
+
+	if len(todos) == 0 {
+		return "", 0, nil
+	}
+
+	pending := 0
+	for _, item := range todos {
+		if item.Status != "completed" {
+			pending++
+		}
+	}
+	if pending == 0 || confirmCarryOver == nil || !confirmCarryOver(pending) {
+		archived, err = t.archiveAndClear(archiveDir, todos)
+		return archived, 0, err
+	}
+
+	// Nothing is actually running yet this session, so a carried-over
+	// "in_progress" item just means it was interrupted last time.
+	t.mu.Lock()
+	for i := range t.todos {
+		if t.todos[i].Status == "in_progress" {
+			t.todos[i].Status = "pending"
+		}
+	}
+	t.mu.Unlock()
+
+	if err := t.Save(); err != nil {
+		return "", 0, err
+	}
+	return "", pending, nil
+}
+
+// archiveAndClear writes todos to a timestamped file under archiveDir and
+// resets the working list, so the next session starts from a clean slate.
+func (t *TodoWriteTool) archiveAndClear(archiveDir string, todos []TodoItem) (string, error) {
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	content.WriteString("# TODO (archived)\n\n")
+	for i, todo := range todos {
+		status := ""
+		switch todo.Status {
+		case "pending":
+			status = "[ ]"
+		case "in_progress":
+			status = "[→]"
+		case "completed":
+			status = "[✓]"
+		}
+		content.WriteString(fmt.Sprintf("%d. %s %s\n", i+1, status, todo.Content))
+	}
+
+	path := filepath.Join(archiveDir, fmt.Sprintf("todo-%s.md", time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	t.todos = []TodoItem{}
+	t.mu.Unlock()
+
+	if err := t.Save(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 // GetProgressSummary returns a summary of TODO progress
 func (t *TodoWriteTool) GetProgressSummary() string {
 	t.mu.RLock()
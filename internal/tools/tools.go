@@ -4,6 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jake/gocode/internal/pathutil"
+	"github.com/jake/gocode/internal/policy"
+	"github.com/jake/gocode/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Tool interface {
@@ -13,39 +24,527 @@ type Tool interface {
 	Execute(ctx context.Context, args string) (string, error)
 }
 
+// DefaultMaxOutputBytes bounds a single tool result so one accidental
+// `cat large.log` can't blow out the rest of the session's context budget.
+const DefaultMaxOutputBytes = 50_000
+
+// headTailBytes is how much of the start/end of an oversized result is kept
+// inline when it gets truncated (half the limit each, when the limit is
+// smaller than twice this).
+const headTailBytes = 20_000
+
+// filePathTools lists tools whose args carry a "file_path" that a policy's
+// forbidden_paths should be checked against, and which count toward
+// max_files_per_turn.
+var filePathTools = map[string]bool{
+	"write":               true,
+	"edit":                true,
+	"edit_structured":     true,
+	"delete_file":         true,
+	"write_long_document": true,
+}
+
+// ledgerTools lists tools whose file_path (and, for "read", offset/limit)
+// gets recorded in the FileLedger after a successful call.
+var ledgerTools = map[string]bool{
+	"read":            true,
+	"write":           true,
+	"edit":            true,
+	"edit_structured": true,
+}
+
+// pathFieldByTool names the args field holding a filesystem path for each
+// tool that accepts one, so normalizePathArgs can resolve it against the
+// workspace root regardless of whether the model passed an absolute path, a
+// relative one, or one with a leading "./".
+var pathFieldByTool = map[string]string{
+	"read":                "file_path",
+	"write":               "file_path",
+	"edit":                "file_path",
+	"edit_structured":     "file_path",
+	"delete_file":         "file_path",
+	"write_long_document": "file_path",
+	"list_directory":      "path",
+	"glob":                "path",
+	"grep":                "path",
+}
+
+// conflictCheckedTools lists tools that overwrite file content and so are
+// checked against the ledger's last-known hash before running, to catch a
+// file changed outside the session (e.g. in the user's editor) since the
+// agent last read or edited it.
+var conflictCheckedTools = map[string]bool{
+	"write":           true,
+	"edit":            true,
+	"edit_structured": true,
+}
+
 type Registry struct {
-	tools map[string]Tool
+	tools              map[string]Tool
+	maxOutputBytes     int
+	artifactsDir       string // where truncated full output is saved; "" disables saving
+	policy             *policy.Policy
+	filesChangedInTurn int
+	ledger             *FileLedger       // read/edit history backing the session_state tool; nil disables tracking
+	workingDir         string            // workspace root; "" disables path normalization (e.g. callers that construct a Registry without it)
+	disabled           map[string]bool   // tool names temporarily turned off via SetToolEnabled, e.g. /tools disable
+	tracer             trace.Tracer      // records a span per tool call when telemetry is enabled; noop otherwise
+	fileChangeHook     func(path string) // called after a successful filePathTools call; nil disables the hook
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
-		tools: make(map[string]Tool),
+		tools:          make(map[string]Tool),
+		maxOutputBytes: DefaultMaxOutputBytes,
+		ledger:         NewFileLedger(),
+		disabled:       make(map[string]bool),
+		tracer:         trace.NewNoopTracerProvider().Tracer("noop"),
+	}
+}
+
+// SetToolEnabled turns a registered tool on or off for the rest of the
+// session: a disabled tool disappears from All() (so it's no longer offered
+// to the LLM on the next turn) and Get/Execute treat it as not found, so a
+// call the model already queued before the toggle still can't slip through.
+// Returns an error if name isn't a registered tool.
+func (r *Registry) SetToolEnabled(name string, enabled bool) error {
+	if _, ok := r.tools[name]; !ok {
+		return fmt.Errorf("tool not found: %s", name)
+	}
+	if enabled {
+		delete(r.disabled, name)
+	} else {
+		r.disabled[name] = true
+	}
+	return nil
+}
+
+// IsToolEnabled reports whether name is registered and not disabled.
+func (r *Registry) IsToolEnabled(name string) bool {
+	_, ok := r.tools[name]
+	return ok && !r.disabled[name]
+}
+
+// Ledger returns the registry's file-read/edit ledger, so callers (e.g. the
+// session_state tool, context pruning) can share the same instance the
+// registry records into.
+func (r *Registry) Ledger() *FileLedger {
+	return r.ledger
+}
+
+// SetMaxOutputBytes overrides the truncation threshold (0 restores the
+// default rather than disabling truncation, to avoid accidental unbounded
+// output if a zero-value config is passed through).
+func (r *Registry) SetMaxOutputBytes(max int) {
+	if max <= 0 {
+		max = DefaultMaxOutputBytes
 	}
+	r.maxOutputBytes = max
+}
+
+// SetArtifactsDir enables saving the untruncated result of any oversized
+// tool call under dir, so the model can be pointed at the full output.
+func (r *Registry) SetArtifactsDir(dir string) {
+	r.artifactsDir = dir
+}
+
+// SetWorkingDir enables normalizePathArgs, resolving every path-carrying
+// tool's args against dir (the workspace root) before the tool runs.
+func (r *Registry) SetWorkingDir(dir string) {
+	r.workingDir = dir
+}
+
+// SetTracer enables per-tool-call OpenTelemetry spans (name, success,
+// result length), exported to traces.db alongside the LLM's llm.completion
+// spans - `gocode dashboard`'s tool failure rate reads these. Registry
+// starts with a noop tracer, so this is safe to leave uncalled.
+func (r *Registry) SetTracer(tracer trace.Tracer) {
+	r.tracer = tracer
+}
+
+// SetFileChangeHook registers a callback invoked with a file's path after
+// every successful filePathTools call, so a caller (filewatch.Watcher.Queue)
+// can schedule incremental reindexing without the turn loop needing to know
+// which tools touch which paths. Passing nil disables the hook.
+func (r *Registry) SetFileChangeHook(hook func(path string)) {
+	r.fileChangeHook = hook
+}
+
+// SetPolicy enables enforcement of a .gocode/policy.yaml's guardrails
+// (forbidden paths/commands, per-turn file-change cap) on every Execute
+// call. Passing nil disables enforcement.
+func (r *Registry) SetPolicy(p *policy.Policy) {
+	r.policy = p
+}
+
+// ResetTurnCounters clears per-turn policy state (e.g. files changed so
+// far). The agent calls this at the start of each new user turn.
+func (r *Registry) ResetTurnCounters() {
+	r.filesChangedInTurn = 0
+}
+
+// checkPolicy rejects a tool call that violates the configured policy,
+// before the tool itself runs.
+func (r *Registry) checkPolicy(name, args string) error {
+	if r.policy == nil {
+		return nil
+	}
+
+	if filePathTools[name] {
+		var pathArgs struct {
+			FilePath string `json:"file_path"`
+		}
+		if err := json.Unmarshal([]byte(args), &pathArgs); err == nil && pathArgs.FilePath != "" {
+			if err := r.policy.CheckPath(pathArgs.FilePath); err != nil {
+				return NewPermissionDeniedError(name, err.Error())
+			}
+		}
+		if err := r.policy.CheckFileCount(r.filesChangedInTurn); err != nil {
+			return NewPermissionDeniedError(name, err.Error())
+		}
+	}
+
+	if name == "bash" {
+		var bashArgs struct {
+			Command string `json:"command"`
+		}
+		if err := json.Unmarshal([]byte(args), &bashArgs); err == nil && bashArgs.Command != "" {
+			if err := r.policy.CheckCommand(bashArgs.Command); err != nil {
+				return NewPermissionDeniedError(name, err.Error())
+			}
+			if strings.Contains(bashArgs.Command, "git commit") {
+				if err := r.runRequiredChecks(); err != nil {
+					return NewPermissionDeniedError(name, err.Error())
+				}
+			}
+		}
+	}
+
+	if name == "git_commit" {
+		if err := r.runRequiredChecks(); err != nil {
+			return NewPermissionDeniedError(name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// normalizePathArgs rewrites name's path-carrying field (see
+// pathFieldByTool) to a workspace-relative path resolved against
+// r.workingDir, rejecting one that resolves outside the workspace. Tools
+// that don't carry a path, and calls where the field is absent or empty,
+// pass through unchanged - most tools' path argument is optional and
+// defaults to the workspace root.
+func (r *Registry) normalizePathArgs(name, args string) (string, error) {
+	field, ok := pathFieldByTool[name]
+	if r.workingDir == "" || !ok {
+		return args, nil
+	}
+
+	var argsMap map[string]interface{}
+	if err := json.Unmarshal([]byte(args), &argsMap); err != nil {
+		return args, nil // let the tool's own UnmarshalArgs surface the malformed JSON
+	}
+
+	raw, ok := argsMap[field].(string)
+	if !ok || raw == "" {
+		return args, nil
+	}
+
+	normalized, err := pathutil.Normalize(r.workingDir, raw)
+	if err != nil {
+		return "", NewPermissionDeniedError(name, err.Error())
+	}
+
+	argsMap[field] = normalized
+	rewritten, err := json.Marshal(argsMap)
+	if err != nil {
+		return args, nil
+	}
+	return string(rewritten), nil
+}
+
+// filePathArg extracts the "file_path" field common to every filePathTools
+// call's args, returning "" if it's absent or the args aren't valid JSON.
+func filePathArg(args string) string {
+	var pathArgs struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := json.Unmarshal([]byte(args), &pathArgs); err != nil {
+		return ""
+	}
+	return pathArgs.FilePath
+}
+
+// checkConflict rejects an edit/write to a file the ledger has seen before
+// if the file's on-disk hash no longer matches what the ledger recorded,
+// meaning it changed outside this session (typically the user editing it in
+// their own editor) since the agent last read or edited it. A file the
+// ledger has never seen isn't checked - there's nothing for the agent to
+// have clobbered.
+func (r *Registry) checkConflict(name, args string) error {
+	if r.ledger == nil || !conflictCheckedTools[name] {
+		return nil
+	}
+
+	var pathArgs struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := json.Unmarshal([]byte(args), &pathArgs); err != nil || pathArgs.FilePath == "" {
+		return nil
+	}
+
+	entry, ok := r.ledger.Entry(pathArgs.FilePath)
+	if !ok || entry.VersionHash == "" {
+		return nil
+	}
+
+	if hashFile(pathArgs.FilePath) != entry.VersionHash {
+		return NewConflictError(name, fmt.Sprintf(
+			"%s changed outside this session since it was last %s (external edit detected) - read it again before editing",
+			pathArgs.FilePath, entry.LastAction))
+	}
+
+	return nil
+}
+
+// AuthorizeExternalWrite validates path - a filesystem path, not a JSON args
+// blob - against the workspace boundary, the policy's forbidden_paths and
+// max_files_per_turn guardrails, and the ledger's external-edit conflict
+// check, returning the normalized path to write to. It's the per-path
+// equivalent of the checks Execute applies via normalizePathArgs/
+// checkPolicy/checkConflict, for tools like rename_symbol/code_action whose
+// LSP-driven WorkspaceEdit can touch a set of files the language server
+// discovers rather than a single file_path named in the tool's own args.
+func (r *Registry) AuthorizeExternalWrite(name, path string) (string, error) {
+	normalized := path
+	if r.workingDir != "" {
+		n, err := pathutil.Normalize(r.workingDir, path)
+		if err != nil {
+			return "", NewPermissionDeniedError(name, err.Error())
+		}
+		normalized = n
+	}
+
+	if r.policy != nil {
+		if err := r.policy.CheckPath(normalized); err != nil {
+			return "", NewPermissionDeniedError(name, err.Error())
+		}
+		if err := r.policy.CheckFileCount(r.filesChangedInTurn); err != nil {
+			return "", NewPermissionDeniedError(name, err.Error())
+		}
+	}
+
+	if r.ledger != nil {
+		if entry, ok := r.ledger.Entry(normalized); ok && entry.VersionHash != "" {
+			if hashFile(normalized) != entry.VersionHash {
+				return "", NewConflictError(name, fmt.Sprintf(
+					"%s changed outside this session since it was last %s (external edit detected) - read it again before editing",
+					normalized, entry.LastAction))
+			}
+		}
+	}
+
+	return normalized, nil
+}
+
+// RecordExternalWrite applies the same per-turn/ledger/file-change-hook
+// bookkeeping Execute runs for filePathTools/ledgerTools calls, for a write
+// made to path outside that flow - see AuthorizeExternalWrite.
+func (r *Registry) RecordExternalWrite(path string) {
+	r.filesChangedInTurn++
+	if r.fileChangeHook != nil {
+		r.fileChangeHook(path)
+	}
+	if r.ledger != nil {
+		r.ledger.Record(path, "edit", 0, 0)
+	}
+}
+
+// runRequiredChecks runs each policy.RequiredChecks command in turn,
+// stopping at the first failure, so a commit can't slip through with
+// broken tests/lint/etc.
+func (r *Registry) runRequiredChecks() error {
+	for _, check := range r.policy.RequiredChecks {
+		cmd := exec.Command("sh", "-c", check)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("required check %q failed: %v\n%s", check, err, output)
+		}
+	}
+	return nil
 }
 
 func (r *Registry) Register(tool Tool) {
 	r.tools[tool.Name()] = tool
 }
 
+// Get returns a registered, enabled tool. A tool disabled via
+// SetToolEnabled is reported as not found, the same as one never
+// registered.
 func (r *Registry) Get(name string) (Tool, bool) {
+	if r.disabled[name] {
+		return nil, false
+	}
 	tool, ok := r.tools[name]
 	return tool, ok
 }
 
+// All returns every registered, enabled tool - what the LLM should be
+// offered on the next completion request.
 func (r *Registry) All() []Tool {
 	tools := make([]Tool, 0, len(r.tools))
-	for _, tool := range r.tools {
+	for name, tool := range r.tools {
+		if r.disabled[name] {
+			continue
+		}
 		tools = append(tools, tool)
 	}
 	return tools
 }
 
+// AllToolNames returns every registered tool's name, enabled or not, sorted
+// for stable /tools output; IsToolEnabled reports each one's state.
+func (r *Registry) AllToolNames() []string {
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (r *Registry) Execute(ctx context.Context, name string, args string) (string, error) {
 	tool, ok := r.Get(name)
 	if !ok {
-		return "", fmt.Errorf("tool not found: %s", name)
+		return "", NewNotFoundError(name, fmt.Sprintf("tool not found: %s", name))
+	}
+
+	normalizedArgs, err := r.normalizePathArgs(name, args)
+	if err != nil {
+		return "", err
+	}
+	args = normalizedArgs
+
+	if err := r.checkPolicy(name, args); err != nil {
+		return "", err
+	}
+
+	if err := r.checkConflict(name, args); err != nil {
+		return "", err
+	}
+
+	toolSpan, ctx := telemetry.StartToolSpan(ctx, r.tracer, name)
+	toolSpan.SetParameters(args)
+	result, err := tool.Execute(ctx, args)
+	if err != nil {
+		toolSpan.SetError(err)
+	} else {
+		toolSpan.SetResult(result, true)
+	}
+	toolSpan.End()
+
+	if err == nil && filePathTools[name] {
+		r.filesChangedInTurn++
+		if r.fileChangeHook != nil {
+			if path := filePathArg(args); path != "" {
+				r.fileChangeHook(path)
+			}
+		}
+	}
+	if err == nil && ledgerTools[name] {
+		recordLedger(r.ledger, name, args)
 	}
-	return tool.Execute(ctx, args)
+	if err == nil && name == "write" {
+		result += r.applyLicenseHeader(args)
+	}
+	if err != nil || len(result) <= r.maxOutputBytes {
+		return result, err
+	}
+
+	return r.truncate(name, result), nil
+}
+
+// applyLicenseHeader re-reads a file the "write" tool just created and, if
+// the policy configures a license_header that the file is missing, prepends
+// it and writes the file back. Returns a note to append to the tool result,
+// or "" if there was no policy, no template match, or the header was
+// already present.
+func (r *Registry) applyLicenseHeader(args string) string {
+	if r.policy == nil {
+		return ""
+	}
+
+	var pathArgs struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := json.Unmarshal([]byte(args), &pathArgs); err != nil || pathArgs.FilePath == "" {
+		return ""
+	}
+
+	content, err := os.ReadFile(pathArgs.FilePath)
+	if err != nil {
+		return ""
+	}
+
+	updated, changed := r.policy.EnsureLicenseHeader(pathArgs.FilePath, string(content))
+	if !changed {
+		return ""
+	}
+
+	if err := os.WriteFile(pathArgs.FilePath, []byte(updated), 0644); err != nil {
+		return ""
+	}
+
+	return "\n(license header injected per .gocode/policy.yaml)"
+}
+
+// truncate keeps the head and tail of an oversized result and, if an
+// artifacts directory is configured, saves the full text to disk so the
+// model can be pointed at it instead of losing the content outright.
+func (r *Registry) truncate(toolName, result string) string {
+	keep := headTailBytes
+	if keep > r.maxOutputBytes/2 {
+		keep = r.maxOutputBytes / 2
+	}
+	head := result[:keep]
+	tail := result[len(result)-keep:]
+
+	notice := fmt.Sprintf("\n\n... [%d bytes omitted; output exceeded %d byte limit]",
+		len(result)-2*keep, r.maxOutputBytes)
+
+	if artifactPath, err := r.saveArtifact(toolName, result); err == nil {
+		notice += fmt.Sprintf(" Full output saved to %s\n\n", artifactPath)
+	} else {
+		notice += "\n\n"
+	}
+
+	return head + notice + tail
+}
+
+// SaveArtifact is the exported form of saveArtifact, for callers outside
+// this package that want to spill a large blob to the same artifacts
+// directory (e.g. context.Manager compressing old tool results out of
+// conversation history).
+func (r *Registry) SaveArtifact(toolName, result string) (string, error) {
+	return r.saveArtifact(toolName, result)
+}
+
+func (r *Registry) saveArtifact(toolName, result string) (string, error) {
+	if r.artifactsDir == "" {
+		return "", fmt.Errorf("artifacts directory not configured")
+	}
+
+	if err := os.MkdirAll(r.artifactsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%d.txt", toolName, time.Now().UnixNano())
+	path := filepath.Join(r.artifactsDir, name)
+	if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+		return "", fmt.Errorf("failed to save artifact: %w", err)
+	}
+
+	return path, nil
 }
 
 // Helper to unmarshal args
@@ -5,19 +5,34 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
+
+	"github.com/jake/gocode/internal/config"
 )
 
+// defaultWebFetchTimeout is used when config.Tools.WebFetch.TimeoutSeconds is
+// unset (0).
+const defaultWebFetchTimeout = 30 * time.Second
+
+// WebFetchTool retrieves a URL and converts it to a markdown-like format.
+// AllowDomains/DenyDomains from config.WebFetchConfig are enforced here
+// rather than by the confirmation system, so a deny-listed domain is
+// rejected outright instead of just requiring an extra approval step.
 type WebFetchTool struct {
 	client *http.Client
+	cfg    config.WebFetchConfig
 }
 
-func NewWebFetchTool() *WebFetchTool {
+func NewWebFetchTool(cfg config.WebFetchConfig) *WebFetchTool {
+	timeout := defaultWebFetchTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
 	return &WebFetchTool{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: &http.Client{Timeout: timeout},
+		cfg:    cfg,
 	}
 }
 
@@ -26,7 +41,7 @@ func (t *WebFetchTool) Name() string {
 }
 
 func (t *WebFetchTool) Description() string {
-	return "Fetches content from a specified URL and returns it. Converts HTML to markdown-like format."
+	return "Fetches content from a specified URL and returns it. Converts HTML to markdown-like format. Subject to configured domain allow/deny lists."
 }
 
 func (t *WebFetchTool) Parameters() map[string]interface{} {
@@ -54,7 +69,21 @@ type WebFetchArgs struct {
 func (t *WebFetchTool) Execute(ctx context.Context, args string) (string, error) {
 	var fetchArgs WebFetchArgs
 	if err := UnmarshalArgs(args, &fetchArgs); err != nil {
-		return "", fmt.Errorf("invalid arguments: %w", err)
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+
+	parsed, err := url.Parse(fetchArgs.URL)
+	if err != nil || parsed.Host == "" {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("invalid URL: %s", fetchArgs.URL))
+	}
+
+	host := hostOnly(parsed.Host)
+	if !domainAllowed(host, t.cfg.AllowDomains, t.cfg.DenyDomains) {
+		return "", NewPermissionDeniedError(t.Name(), fmt.Sprintf("domain %q is not permitted by web_fetch's allow/deny list", host))
+	}
+
+	if t.cfg.RespectRobots && !t.robotsAllow(ctx, parsed) {
+		return "", NewPermissionDeniedError(t.Name(), fmt.Sprintf("robots.txt disallows fetching %s", fetchArgs.URL))
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", fetchArgs.URL, nil)
@@ -66,6 +95,9 @@ func (t *WebFetchTool) Execute(ctx context.Context, args string) (string, error)
 
 	resp, err := t.client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", NewTimeoutError(t.Name(), fmt.Sprintf("fetching %s timed out", fetchArgs.URL))
+		}
 		return "", fmt.Errorf("failed to fetch URL: %w", err)
 	}
 	defer resp.Body.Close()
@@ -96,6 +128,106 @@ func (t *WebFetchTool) Execute(ctx context.Context, args string) (string, error)
 	return fmt.Sprintf("Content from %s:\n\n%s", fetchArgs.URL, content), nil
 }
 
+// robotsAllow fetches host/robots.txt and checks whether it disallows the
+// requested path for a wildcard user-agent. Any failure to fetch or parse
+// robots.txt is treated as allowed - a missing or broken robots.txt
+// shouldn't block a request that would otherwise succeed.
+func (t *WebFetchTool) robotsAllow(ctx context.Context, target *url.URL) bool {
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", target.Scheme, target.Host)
+	req, err := http.NewRequestWithContext(ctx, "GET", robotsURL, nil)
+	if err != nil {
+		return true
+	}
+	req.Header.Set("User-Agent", "Coder-Agent/1.0")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return true
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return true
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true
+	}
+
+	return robotsTxtAllows(string(body), target.Path)
+}
+
+// robotsTxtAllows is a minimal robots.txt evaluator: it only looks at the
+// "User-agent: *" group and its "Disallow" rules, which covers the common
+// case of a site blanket-blocking crawlers without needing a full parser.
+func robotsTxtAllows(robotsTxt, path string) bool {
+	if path == "" {
+		path = "/"
+	}
+
+	inWildcardGroup := false
+	for _, line := range strings.Split(robotsTxt, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			inWildcardGroup = value == "*"
+		case "disallow":
+			if inWildcardGroup && value != "" && strings.HasPrefix(path, value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// hostOnly strips a port from a URL's Host component, if present.
+func hostOnly(host string) string {
+	if idx := strings.LastIndex(host, ":"); idx != -1 {
+		return host[:idx]
+	}
+	return host
+}
+
+// domainAllowed reports whether host may be fetched given an allowlist and
+// denylist, matching a domain and its subdomains against each entry.
+// DenyDomains always wins; when allow is non-empty, host must also match one
+// of its entries.
+func domainAllowed(host string, allow, deny []string) bool {
+	for _, d := range deny {
+		if domainMatches(host, d) {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, d := range allow {
+		if domainMatches(host, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// domainMatches reports whether host is domain or a subdomain of it.
+func domainMatches(host, domain string) bool {
+	host = strings.ToLower(host)
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
 func (t *WebFetchTool) htmlToMarkdown(html string) string {
 	// Very basic HTML stripping - in production you'd use a proper library
 	content := html
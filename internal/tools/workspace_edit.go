@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jake/gocode/internal/lsp"
+)
+
+// applyWorkspaceEdit writes every change in edit to disk. Shared by
+// rename_symbol and code_action, since textDocument/rename and code
+// actions that edit text directly both return the same WorkspaceEdit
+// shape. Each file is routed through registry.AuthorizeExternalWrite/
+// RecordExternalWrite first, the same workspace-boundary/policy/conflict
+// checks and file-change-hook/ledger bookkeeping every other file-mutating
+// tool gets via Registry.Execute - a WorkspaceEdit can touch files the
+// language server discovers, not just the tool's own file_path argument, so
+// that per-call pipeline can't cover them and this does it per file instead.
+func applyWorkspaceEdit(registry *Registry, toolName string, edit *lsp.WorkspaceEdit) ([]string, error) {
+	if edit == nil || len(edit.Changes) == 0 {
+		return nil, nil
+	}
+
+	var changed []string
+	for uri, edits := range edit.Changes {
+		if len(edits) == 0 {
+			continue
+		}
+
+		path, err := registry.AuthorizeExternalWrite(toolName, lsp.URIToPath(uri))
+		if err != nil {
+			return changed, err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return changed, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if err := os.WriteFile(path, []byte(applyTextEdits(string(content), edits)), 0644); err != nil {
+			return changed, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		registry.RecordExternalWrite(path)
+		changed = append(changed, path)
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// applyTextEdits applies a set of TextEdits to content, working from the
+// last edit to the first so that earlier edits' line/character positions
+// stay valid even though earlier text in the file has already shifted.
+func applyTextEdits(content string, edits []lsp.TextEdit) string {
+	sorted := append([]lsp.TextEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Range.Start.Line != sorted[j].Range.Start.Line {
+			return sorted[i].Range.Start.Line > sorted[j].Range.Start.Line
+		}
+		return sorted[i].Range.Start.Character > sorted[j].Range.Start.Character
+	})
+
+	lines := strings.Split(content, "\n")
+	for _, e := range sorted {
+		lines = applyOneTextEdit(lines, e)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func applyOneTextEdit(lines []string, e lsp.TextEdit) []string {
+	startLine, endLine := e.Range.Start.Line, e.Range.End.Line
+	if startLine < 0 || startLine >= len(lines) || endLine < 0 || endLine >= len(lines) {
+		return lines
+	}
+
+	startChar := min(e.Range.Start.Character, len(lines[startLine]))
+	endChar := min(e.Range.End.Character, len(lines[endLine]))
+
+	before := lines[startLine][:startChar]
+	after := lines[endLine][endChar:]
+	replaced := strings.Split(before+e.NewText+after, "\n")
+
+	result := append([]string{}, lines[:startLine]...)
+	result = append(result, replaced...)
+	result = append(result, lines[endLine+1:]...)
+	return result
+}
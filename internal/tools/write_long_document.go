@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jake/gocode/internal/llm"
+)
+
+// WriteLongDocumentTool generates a document section by section, streaming
+// each section straight to disk instead of returning the whole thing as one
+// chat completion. This lets a design doc or migration guide exceed
+// max_tokens without being truncated or having to be copy-pasted out of chat.
+type WriteLongDocumentTool struct {
+	client *llm.Client
+}
+
+func NewWriteLongDocumentTool(client *llm.Client) *WriteLongDocumentTool {
+	return &WriteLongDocumentTool{client: client}
+}
+
+func (t *WriteLongDocumentTool) Name() string {
+	return "write_long_document"
+}
+
+func (t *WriteLongDocumentTool) Description() string {
+	return "Generates a long document (design doc, migration guide, RFC) directly into a file, section by section, so it isn't limited by chat max_tokens. Writes an outline first, then expands each section in turn."
+}
+
+func (t *WriteLongDocumentTool) Parameters() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"file_path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to write the document to",
+			},
+			"topic": map[string]interface{}{
+				"type":        "string",
+				"description": "What the document should cover, and any constraints (audience, length, format)",
+			},
+		},
+		"required": []string{"file_path", "topic"},
+	}
+}
+
+type WriteLongDocumentArgs struct {
+	FilePath string `json:"file_path"`
+	Topic    string `json:"topic"`
+}
+
+func (t *WriteLongDocumentTool) Execute(ctx context.Context, args string) (string, error) {
+	var a WriteLongDocumentArgs
+	if err := UnmarshalArgs(args, &a); err != nil {
+		return "", NewInvalidArgsError(t.Name(), err)
+	}
+	if a.FilePath == "" || a.Topic == "" {
+		return "", NewInvalidArgsError(t.Name(), fmt.Errorf("file_path and topic are required"))
+	}
+
+	sections, err := t.generateOutline(ctx, a.Topic)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate outline: %w", err)
+	}
+	if len(sections) == 0 {
+		return "", fmt.Errorf("outline generation returned no sections")
+	}
+
+	if dir := filepath.Dir(a.FilePath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(a.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	for i, section := range sections {
+		body, err := t.generateSection(ctx, a.Topic, sections, i)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate section %q: %w", section, err)
+		}
+		if _, err := fmt.Fprintf(file, "## %s\n\n%s\n\n", section, strings.TrimSpace(body)); err != nil {
+			return "", fmt.Errorf("failed to write section %q: %w", section, err)
+		}
+	}
+
+	return fmt.Sprintf("Wrote %d sections to %s", len(sections), a.FilePath), nil
+}
+
+// generateOutline asks for a JSON array of section titles before any prose
+// is written, so the section-by-section pass has a fixed structure to fill.
+func (t *WriteLongDocumentTool) generateOutline(ctx context.Context, topic string) ([]string, error) {
+	resp, err := t.client.Complete(ctx, llm.CompletionRequest{
+		Messages: []llm.Message{
+			{
+				Role: "system",
+				Content: "Produce an outline for the requested document. " +
+					`Respond with ONLY a JSON array of section titles, e.g. ["Overview","Design","Rollout Plan"], and nothing else.`,
+			},
+			{Role: "user", Content: topic},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	var sections []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &sections); err != nil {
+		return nil, fmt.Errorf("unparseable outline: %w", err)
+	}
+	return sections, nil
+}
+
+// generateSection expands a single outline entry, given the full outline
+// for context so cross-references between sections stay coherent.
+func (t *WriteLongDocumentTool) generateSection(ctx context.Context, topic string, outline []string, index int) (string, error) {
+	resp, err := t.client.Complete(ctx, llm.CompletionRequest{
+		Messages: []llm.Message{
+			{
+				Role: "system",
+				Content: fmt.Sprintf(
+					"You are writing one section of a larger document about: %s\nFull outline: %s\nWrite ONLY the body of the section titled %q. Do not repeat the title.",
+					topic, strings.Join(outline, ", "), outline[index]),
+			},
+			{Role: "user", Content: fmt.Sprintf("Write the %q section.", outline[index])},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Content, nil
+}
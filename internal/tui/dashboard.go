@@ -0,0 +1,78 @@
+// Package tui implements the optional dashboard rendering mode for the REPL
+// (`gocode --tui`). It does not take over the terminal like a real
+// full-screen TUI framework (bubbletea isn't vendored in this module) —
+// instead it clears the screen and redraws stacked panes after each turn,
+// giving a dashboard-style view while the plain REPL stream stays the
+// default.
+package tui
+
+import (
+	"fmt"
+
+	"github.com/jake/gocode/internal/theme"
+)
+
+// Dashboard renders a snapshot of agent state as stacked panes.
+type Dashboard struct {
+	enabled bool
+}
+
+// NewDashboard creates a dashboard. When enabled is false, Render is a no-op
+// so callers don't need to branch on the flag themselves.
+func NewDashboard(enabled bool) *Dashboard {
+	return &Dashboard{enabled: enabled}
+}
+
+// Enabled reports whether TUI mode was requested.
+func (d *Dashboard) Enabled() bool {
+	return d.enabled
+}
+
+// Snapshot is the state redrawn into the dashboard after each turn.
+type Snapshot struct {
+	RecentMessages []string // last few conversation lines, already formatted
+	TodoItems      []string
+	RunningTools   []string
+	ContextUsed    int
+	ContextWindow  int
+}
+
+// Render clears the screen and redraws the conversation, plan, tools, and
+// context-usage panes.
+func (d *Dashboard) Render(snap Snapshot) {
+	if !d.enabled {
+		return
+	}
+
+	fmt.Print("\033[2J\033[H") // clear screen, move cursor home
+
+	fmt.Println(theme.SummaryBox("Conversation", tail(snap.RecentMessages, 10)))
+	fmt.Println()
+	fmt.Println(theme.SummaryBox("TODO / Plan", withPlaceholder(snap.TodoItems, "(no active plan)")))
+	fmt.Println()
+	fmt.Println(theme.SummaryBox("Running Tools", withPlaceholder(snap.RunningTools, "(idle)")))
+	fmt.Println()
+
+	pct := 0
+	if snap.ContextWindow > 0 {
+		pct = snap.ContextUsed * 100 / snap.ContextWindow
+	}
+	fmt.Println(theme.SummaryBox("Context Usage", []string{
+		fmt.Sprintf("%d / %d tokens (%d%%)", snap.ContextUsed, snap.ContextWindow, pct),
+	}))
+	fmt.Println()
+}
+
+func tail(lines []string, n int) []string {
+	if len(lines) <= n {
+		return lines
+	}
+	return lines[len(lines)-n:]
+}
+
+func withPlaceholder(lines []string, placeholder string) []string {
+	if len(lines) == 0 {
+		return []string{placeholder}
+	}
+	return lines
+}